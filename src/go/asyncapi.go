@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"strings"
+)
+
+const asyncApiVersion = "3.0.0"
+
+// defaultApiVersion is the version of the AsyncAPI document when the model has no version, since AsyncAPI requires one.
+const defaultApiVersion = "1.0.0"
+
+// AsyncApiDocument is an AsyncAPI document that describes the messaging of the system: its queues are the channels,
+// the events on those queues are the messages, and services that send to or receive from queues perform operations.
+type AsyncApiDocument struct {
+	AsyncApi   string                       `yaml:"asyncapi"`
+	Info       AsyncApiInfo                 `yaml:"info"`
+	Servers    map[string]AsyncApiServer    `yaml:"servers,omitempty"`
+	Channels   map[string]AsyncApiChannel   `yaml:"channels"`
+	Operations map[string]AsyncApiOperation `yaml:"operations"`
+	Components *AsyncApiComponents          `yaml:"components,omitempty"`
+}
+
+type AsyncApiInfo struct {
+	Title       string `yaml:"title"`
+	Version     string `yaml:"version"`
+	Description string `yaml:"description,omitempty"`
+}
+
+type AsyncApiServer struct {
+	Host        string `yaml:"host"`
+	Protocol    string `yaml:"protocol"`
+	Title       string `yaml:"title,omitempty"`
+	Description string `yaml:"description,omitempty"`
+}
+
+type AsyncApiChannel struct {
+	Address     string                       `yaml:"address"`
+	Title       string                       `yaml:"title,omitempty"`
+	Description string                       `yaml:"description,omitempty"`
+	Servers     []AsyncApiReference          `yaml:"servers,omitempty"`
+	Messages    map[string]AsyncApiReference `yaml:"messages,omitempty"`
+}
+
+type AsyncApiOperation struct {
+	Action      string              `yaml:"action"`
+	Channel     AsyncApiReference   `yaml:"channel"`
+	Title       string              `yaml:"title,omitempty"`
+	Description string              `yaml:"description,omitempty"`
+	Messages    []AsyncApiReference `yaml:"messages,omitempty"`
+}
+
+type AsyncApiComponents struct {
+	Messages map[string]AsyncApiMessage `yaml:"messages"`
+}
+
+type AsyncApiMessage struct {
+	Name        string             `yaml:"name"`
+	Title       string             `yaml:"title,omitempty"`
+	Description string             `yaml:"description,omitempty"`
+	Payload     *AsyncApiReference `yaml:"payload,omitempty"`
+}
+
+type AsyncApiReference struct {
+	Ref string `yaml:"$ref"`
+}
+
+// asyncApiProtocols maps parts of technology IDs and names to the AsyncAPI protocols they use.
+var asyncApiProtocols = []struct {
+	keyword  string
+	protocol string
+}{
+	{"kafka", "kafka"},
+	{"rabbitmq", "amqp"},
+	{"amqp", "amqp"},
+	{"activemq", "jms"},
+	{"jms", "jms"},
+	{"mqtt", "mqtt"},
+	{"nats", "nats"},
+	{"pulsar", "pulsar"},
+	{"sqs", "sqs"},
+	{"sns", "sns"},
+	{"pubsub", "googlepubsub"},
+	{"pub/sub", "googlepubsub"},
+	{"redis", "redis"},
+	{"ibmmq", "ibmmq"},
+	{"solace", "solace"},
+	{"stomp", "stomp"},
+	{"websocket", "ws"},
+}
+
+// protocolOf returns the AsyncAPI protocol of a technology, or an empty string if it isn't a known messaging
+// technology.
+func protocolOf(technology *Technology) string {
+	id := strings.ToLower(technology.Id)
+	name := strings.ToLower(technology.Name)
+	for _, candidate := range asyncApiProtocols {
+		if strings.Contains(id, candidate.keyword) || strings.Contains(name, candidate.keyword) {
+			return candidate.protocol
+		}
+	}
+	return ""
+}
+
+type asyncApiExporter struct {
+}
+
+func NewAsyncApiExporter() TextExporter {
+	return asyncApiExporter{}
+}
+
+func (a asyncApiExporter) export(model ArchitectureModel, printer *Printer) error {
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	err := encoder.Encode(NewAsyncApiDocument(&model))
+	if err != nil {
+		return err
+	}
+	printer.Print(buffer.String())
+	return nil
+}
+
+// NewAsyncApiDocument creates an AsyncAPI document for the queues of the model. A queue's host and address default to
+// its ID, but can be set using the properties host and address.
+func NewAsyncApiDocument(model *ArchitectureModel) AsyncApiDocument {
+	version := model.Version
+	if version == "" {
+		version = defaultApiVersion
+	}
+	result := AsyncApiDocument{
+		AsyncApi:   asyncApiVersion,
+		Info:       AsyncApiInfo{Title: model.System.Name, Version: version},
+		Servers:    make(map[string]AsyncApiServer),
+		Channels:   make(map[string]AsyncApiChannel),
+		Operations: make(map[string]AsyncApiOperation),
+	}
+	for _, queue := range model.Queues {
+		result.Channels[queue.Id] = asyncApiChannelOf(queue, model, result.Servers)
+	}
+	messages := make(map[string]AsyncApiMessage)
+	for _, event := range model.Events {
+		message := AsyncApiMessage{Name: event.Id, Title: event.Name, Description: event.Description}
+		if event.Schema != "" {
+			message.Payload = &AsyncApiReference{event.Schema}
+		}
+		messages[event.Id] = message
+	}
+	if len(messages) > 0 {
+		result.Components = &AsyncApiComponents{messages}
+	}
+	for _, service := range model.Services {
+		for _, use := range service.DataStores {
+			if use.Queue == nil {
+				continue
+			}
+			if use.DataFlow != Receive {
+				result.Operations[fmt.Sprintf("%v_send_%v", service.Id, use.Queue.Id)] = asyncApiOperationOf(service, use,
+					"send", "sends to", service.PublishedEvents)
+			}
+			if use.DataFlow != Send {
+				result.Operations[fmt.Sprintf("%v_receive_%v", service.Id, use.Queue.Id)] = asyncApiOperationOf(service,
+					use, "receive", "receives from", service.SubscribedEvents)
+			}
+		}
+	}
+	return result
+}
+
+func asyncApiChannelOf(queue *DataStore, model *ArchitectureModel, servers map[string]AsyncApiServer) AsyncApiChannel {
+	result := AsyncApiChannel{Address: queue.Id, Title: queue.Name, Description: queue.Description}
+	if address, found := queue.Properties["address"]; found {
+		result.Address = address
+	}
+	host, found := queue.Properties["host"]
+	if !found {
+		host = queue.Id
+	}
+	for _, technology := range queue.ApiTechnologies {
+		protocol := protocolOf(technology)
+		if protocol == "" {
+			continue
+		}
+		id := fmt.Sprintf("%v_%v", queue.Id, technology.Id)
+		servers[id] = AsyncApiServer{Host: host, Protocol: protocol, Title: technology.Name,
+			Description: technology.Description}
+		result.Servers = append(result.Servers, AsyncApiReference{"#/servers/" + id})
+	}
+	for _, event := range model.Events {
+		if event.Queue == queue {
+			if result.Messages == nil {
+				result.Messages = make(map[string]AsyncApiReference)
+			}
+			result.Messages[event.Id] = AsyncApiReference{"#/components/messages/" + event.Id}
+		}
+	}
+	return result
+}
+
+func asyncApiOperationOf(service *Service, use *DataStoreUse, action string, verb string,
+	events []*Event) AsyncApiOperation {
+	result := AsyncApiOperation{
+		Action:      action,
+		Channel:     AsyncApiReference{"#/channels/" + use.Queue.Id},
+		Title:       fmt.Sprintf("%v %v %v", service.Name, verb, use.Queue.Name),
+		Description: use.Description,
+	}
+	for _, event := range events {
+		if event.Queue == use.Queue {
+			result.Messages = append(result.Messages,
+				AsyncApiReference{fmt.Sprintf("#/channels/%v/messages/%v", use.Queue.Id, event.Id)})
+		}
+	}
+	return result
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAsyncApi(t *testing.T) {
+	model, issues := LintText(strings.Replace(eventsDefinition, "    name: Domain events\n", `    name: Domain events
+    apiTechnologies:
+      - kafka
+    properties:
+      host: kafka.example.com:9092
+`, 1) + `
+technologies:
+  kafka:
+    name: Apache Kafka
+    quadrant: platforms
+  java:
+    quadrant: languagesAndFrameworks
+`)
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+
+	document := NewAsyncApiDocument(model)
+
+	if document.AsyncApi != "3.0.0" || document.Info.Version != "1.0.0" {
+		t.Errorf("Invalid versions: %v, %+v", document.AsyncApi, document.Info)
+	}
+	if len(document.Servers) != 1 || document.Servers["events_kafka"].Protocol != "kafka" ||
+		document.Servers["events_kafka"].Host != "kafka.example.com:9092" {
+		t.Errorf("Invalid servers: %+v", document.Servers)
+	}
+	channel := document.Channels["events"]
+	if channel.Address != "events" || channel.Title != "Domain events" || len(channel.Servers) != 1 ||
+		channel.Messages["registered"].Ref != "#/components/messages/registered" {
+		t.Errorf("Invalid channel: %+v", channel)
+	}
+	send, found := document.Operations["auth_send_events"]
+	if !found || send.Action != "send" || send.Channel.Ref != "#/channels/events" || len(send.Messages) != 1 {
+		t.Errorf("Invalid send operation: %+v", document.Operations)
+	}
+	receive, found := document.Operations["mailer_receive_events"]
+	if !found || receive.Action != "receive" || len(receive.Messages) != 1 {
+		t.Errorf("Invalid receive operation: %+v", document.Operations)
+	}
+	if len(document.Operations) != 2 {
+		t.Errorf("Expected 2 operations, but got %v", len(document.Operations))
+	}
+	message := document.Components.Messages["registered"]
+	if message.Title != "Registered" || message.Description != "A guest registered" ||
+		message.Payload.Ref != "schemas/registered.json" {
+		t.Errorf("Invalid message: %+v", message)
+	}
+}
@@ -1,5 +1,10 @@
 package main
 
+import (
+	"fmt"
+	"strings"
+)
+
 type c4Exporter struct {
 }
 
@@ -9,11 +14,17 @@ func NewC4Exporter() TextExporter {
 
 const idOfSystemOfInterest = "system"
 
+// replicasTag marks the deployment nodes that hold the replicas of a single instance.
+const replicasTag = "Replicas"
+
 func (c c4Exporter) export(model ArchitectureModel, printer *Printer) error {
 	printer.PrintLn("workspace {")
 	printer.Start()
 	c.printModel(&model, printer)
-	c.printViews(printer)
+	err := c.printViews(&model, printer)
+	if err != nil {
+		return err
+	}
 	printer.End()
 	printer.PrintLn("}")
 	return nil
@@ -24,8 +35,19 @@ type usage struct {
 	used        string
 	description string
 	byPersona   bool
+	extensions  *Extensions
+	// technologies are those of the call, or nil for other relationships.
+	technologies []*Technology
+	dataFlow     DataFlow
+	// kind and id are those of the form or view that a persona uses, like form and order.
+	kind string
+	id   string
 }
 
+// modelPropertyPrefix starts the keys of the properties of relationships that carry the parts of the model that C4 has
+// no place for, so that importing the workspace restores them.
+const modelPropertyPrefix = "archmodel."
+
 func (c c4Exporter) printModel(model *ArchitectureModel, printer *Printer) {
 	printer.PrintLn("model {")
 	printer.Start()
@@ -34,6 +56,7 @@ func (c c4Exporter) printModel(model *ArchitectureModel, printer *Printer) {
 	usages = append(usages, c.printPersons(model, printer)...)
 	usages = append(usages, c.printSoftwareSystems(model, printer)...)
 	c.printRelationships(usages, printer)
+	c.printEnvironments(model, printer)
 
 	printer.End()
 	printer.PrintLn("}")
@@ -45,13 +68,18 @@ func (c c4Exporter) printPersons(model *ArchitectureModel, printer *Printer) []u
 		printer.PrintLn(persona.Id, " = person \"", persona.Name, "\" {")
 		printer.Start()
 		c.printDescription(persona, printer)
+		c.printTags(&persona.Extensions, printer)
+		c.printProperties(&persona.Extensions, printer)
 		for _, used := range persona.Uses {
 			if used.ExternalSystem != nil {
-				usages = append(usages, usage{persona.Id, used.ExternalSystem.Id, used.Description, true})
+				usages = append(usages, usage{user: persona.Id, used: used.ExternalSystem.Id,
+					description: used.Description, byPersona: true, dataFlow: used.DataFlow})
 			} else if used.Form != nil {
-				usages = append(usages, usage{persona.Id, used.Form.ImplementedBy.Id, used.Description, true})
+				usages = append(usages, usage{user: persona.Id, used: used.Form.ImplementedBy.Id,
+					description: used.Description, byPersona: true, dataFlow: used.DataFlow, kind: "form", id: used.Form.Id})
 			} else if used.View != nil {
-				usages = append(usages, usage{persona.Id, used.View.On.Id + "_db", used.Description, true})
+				usages = append(usages, usage{user: persona.Id, used: used.View.On.Id + "_db",
+					description: used.Description, byPersona: true, dataFlow: used.DataFlow, kind: "view", id: used.View.Id})
 			}
 		}
 		printer.End()
@@ -85,12 +113,57 @@ func (c c4Exporter) printSoftwareSystemOfInterest(model *ArchitectureModel, prin
 
 func (c c4Exporter) printContainers(model *ArchitectureModel, printer *Printer) []usage {
 	usages := make([]usage, 0)
-	usages = append(usages, c.printServices(model.Services, printer)...)
-	c.printDatabases(model.Databases, printer)
-	c.printQueues(model.Queues, printer)
+	for _, team := range teamsAndNobody(model) {
+		usages = append(usages, c.printContainersOwnedBy(team, model, printer)...)
+	}
+	return usages
+}
+
+// printContainersOwnedBy prints the containers that the team owns in a group, or the containers without an owner.
+func (c c4Exporter) printContainersOwnedBy(team *Team, model *ArchitectureModel, printer *Printer) []usage {
+	services := make([]*Service, 0)
+	for _, service := range model.Services {
+		if service.Owner == team {
+			services = append(services, service)
+		}
+	}
+	databases := make([]*Database, 0)
+	for _, database := range model.Databases {
+		if database.Owner == team {
+			databases = append(databases, database)
+		}
+	}
+	queues := make([]*DataStore, 0)
+	for _, queue := range model.Queues {
+		if queue.Owner == team {
+			queues = append(queues, queue)
+		}
+	}
+	if team != nil {
+		if len(services)+len(databases)+len(queues) == 0 {
+			return []usage{}
+		}
+		c.startGroup(team, printer)
+	}
+	usages := c.printServices(services, printer)
+	c.printDatabases(databases, printer)
+	c.printQueues(queues, printer)
+	if team != nil {
+		c.endGroup(printer)
+	}
 	return usages
 }
 
+func (c c4Exporter) startGroup(team *Team, printer *Printer) {
+	printer.PrintLn("group \"", team.Name, "\" {")
+	printer.Start()
+}
+
+func (c c4Exporter) endGroup(printer *Printer) {
+	printer.End()
+	printer.PrintLn("}")
+}
+
 func (c c4Exporter) printServices(services []*Service, printer *Printer) []usage {
 	usages := make([]usage, 0)
 	for _, service := range services {
@@ -98,12 +171,15 @@ func (c c4Exporter) printServices(services []*Service, printer *Printer) []usage
 		printer.Start()
 		c.printDescription(service, printer)
 		c.printTechnology(service, printer)
-		printer.PrintLn("tags \"Service\" \"", service.State.String(), "\"")
+		c.printTags(&service.Extensions, printer, "Service", service.State.String())
+		c.printProperties(&service.Extensions, printer)
 		for _, call := range service.Calls {
 			if call.ExternalSystemId != "" {
-				usages = append(usages, usage{service.Id, call.ExternalSystemId, call.Description, false})
+				usages = append(usages, usage{user: service.Id, used: call.ExternalSystemId, description: call.Description,
+					extensions: &call.Extensions, technologies: call.UsedTechnologies(), dataFlow: call.DataFlow})
 			} else {
-				usages = append(usages, usage{service.Id, call.ServiceId, call.Description, false})
+				usages = append(usages, usage{user: service.Id, used: call.ServiceId, description: call.Description,
+					extensions: &call.Extensions, technologies: call.UsedTechnologies(), dataFlow: call.DataFlow})
 			}
 		}
 		for _, dataStore := range service.DataStores {
@@ -113,7 +189,8 @@ func (c c4Exporter) printServices(services []*Service, printer *Printer) []usage
 			} else {
 				id = id + "_q"
 			}
-			usages = append(usages, usage{service.Id, id, dataStore.Description, false})
+			usages = append(usages, usage{user: service.Id, used: id, description: dataStore.Description,
+				dataFlow: dataStore.DataFlow})
 		}
 		printer.End()
 		printer.PrintLn("}")
@@ -144,9 +221,10 @@ func (c c4Exporter) printDatabases(databases []*Database, printer *Printer) {
 func (c c4Exporter) printDataStore(dataStore *DataStore, suffix string, tag string, printer *Printer) {
 	printer.PrintLn(dataStore.Id, suffix, " = container \"", dataStore.Name, "\" {")
 	printer.Start()
-	printer.PrintLn("tags \"", tag, "\"", " \"", dataStore.State.String(), "\"")
+	c.printTags(&dataStore.Extensions, printer, tag, dataStore.State.String())
 	c.printDescription(dataStore, printer)
 	c.printTechnology(dataStore, printer)
+	c.printProperties(&dataStore.Extensions, printer)
 	printer.End()
 	printer.PrintLn("}")
 }
@@ -159,20 +237,48 @@ func (c c4Exporter) printQueues(dataStores []*DataStore, printer *Printer) {
 
 func (c c4Exporter) printExternalSystems(model *ArchitectureModel, printer *Printer) []usage {
 	usages := make([]usage, 0)
-	for _, externalSystem := range model.ExternalSystems {
+	for _, team := range teamsAndNobody(model) {
+		externalSystems := make([]*ExternalSystem, 0)
+		for _, externalSystem := range model.ExternalSystems {
+			if externalSystem.Owner == team {
+				externalSystems = append(externalSystems, externalSystem)
+			}
+		}
+		if len(externalSystems) == 0 {
+			continue
+		}
+		if team != nil {
+			c.startGroup(team, printer)
+		}
+		usages = append(usages, c.printExternalSystemsOf(externalSystems, printer)...)
+		if team != nil {
+			c.endGroup(printer)
+		}
+	}
+	return usages
+}
+
+func (c c4Exporter) printExternalSystemsOf(externalSystems []*ExternalSystem, printer *Printer) []usage {
+	usages := make([]usage, 0)
+	for _, externalSystem := range externalSystems {
 		printer.PrintLn(externalSystem.Id, " = softwareSystem \"", externalSystem.Name, "\" {")
 		printer.Start()
-		printer.Print("tags \"External System\"")
+		tags := []string{"External System"}
 		if externalSystem.Type != "" {
-			printer.Print(" \"", externalSystem.Type, "\"")
+			tags = append(tags, externalSystem.Type)
 		}
-		printer.NewLine()
+		c.printTags(&externalSystem.Extensions, printer, tags...)
 		c.printDescription(externalSystem, printer)
+		c.printProperties(&externalSystem.Extensions, printer)
 		for _, call := range externalSystem.Calls {
 			if call.ExternalSystemId != "" {
-				usages = append(usages, usage{externalSystem.Id, call.ExternalSystemId, call.Description, false})
+				usages = append(usages, usage{user: externalSystem.Id, used: call.ExternalSystemId,
+					description: call.Description, extensions: &call.Extensions, technologies: call.UsedTechnologies(),
+					dataFlow: call.DataFlow})
 			} else if call.ServiceId != "" {
-				usages = append(usages, usage{externalSystem.Id, call.ServiceId, call.Description, false})
+				usages = append(usages, usage{user: externalSystem.Id, used: call.ServiceId,
+					description: call.Description, extensions: &call.Extensions, technologies: call.UsedTechnologies(),
+					dataFlow: call.DataFlow})
 			}
 		}
 		printer.End()
@@ -184,13 +290,26 @@ func (c c4Exporter) printExternalSystems(model *ArchitectureModel, printer *Prin
 func (c c4Exporter) printRelationships(usages []usage, printer *Printer) {
 	for _, usage := range usages {
 		printer.Print(usage.user, " -> ", usage.used)
-		if usage.description != "" {
+		if usage.description != "" || len(usage.technologies) > 0 {
 			printer.Print(" \"", usage.description, "\"")
 		}
+		if len(usage.technologies) > 0 {
+			names := make([]string, 0, len(usage.technologies))
+			for _, technology := range usage.technologies {
+				names = append(names, technology.Name)
+			}
+			printer.Print(" \"", strings.Join(names, ", "), "\"")
+		}
+		tags := make([]string, 0)
 		if usage.byPersona {
+			tags = append(tags, "Using")
+		}
+		extensions := c.extensionsOf(usage)
+		if len(tags)+len(extensions.Tags)+len(extensions.Properties) > 0 {
 			printer.PrintLn(" {")
 			printer.Start()
-			printer.PrintLn("tags \"Using\"")
+			c.printTags(extensions, printer, tags...)
+			c.printProperties(extensions, printer)
 			printer.End()
 			printer.Print("}")
 		}
@@ -198,112 +317,267 @@ func (c c4Exporter) printRelationships(usages []usage, printer *Printer) {
 	}
 }
 
-func (c c4Exporter) printViews(printer *Printer) {
-	printer.PrintLn("views {")
-	printer.Start()
-	printer.PrintLn("systemContext ", idOfSystemOfInterest, " {")
-	printer.Start()
-	printer.PrintLn("include *")
-	printer.PrintLn("autolayout")
-	printer.End()
-	printer.PrintLn("}")
-	printer.PrintLn("container ", idOfSystemOfInterest, " {")
+// extensionsOf returns the extensions of a relationship, with properties for its data flow and the form or view that it
+// uses, if any.
+func (c c4Exporter) extensionsOf(usage usage) *Extensions {
+	result := &Extensions{Properties: make(map[string]string)}
+	if usage.extensions != nil {
+		result.Tags = usage.extensions.Tags
+		for key, value := range usage.extensions.Properties {
+			result.Properties[key] = value
+		}
+	}
+	if usage.dataFlow != Bidirectional {
+		result.Properties[modelPropertyPrefix+"dataFlow"] = usage.dataFlow.String()
+	}
+	if usage.kind != "" {
+		result.Properties[modelPropertyPrefix+usage.kind] = usage.id
+	}
+	return result
+}
+
+// printTags prints the given tags, followed by the custom tags of an element.
+func (c c4Exporter) printTags(extensions *Extensions, printer *Printer, tags ...string) {
+	tags = append(tags, extensions.Tags...)
+	if len(tags) == 0 {
+		return
+	}
+	printer.Print("tags")
+	for _, tag := range tags {
+		printer.Print(" \"", escapeQuoted(tag), "\"")
+	}
+	printer.NewLine()
+}
+
+func (c c4Exporter) printProperties(extensions *Extensions, printer *Printer) {
+	if len(extensions.Properties) == 0 {
+		return
+	}
+	printer.PrintLn("properties {")
 	printer.Start()
-	printer.PrintLn("include *")
-	printer.PrintLn("autolayout")
-	printer.End()
-	printer.PrintLn("}")
-	c.printStyles(printer)
+	for _, key := range extensions.PropertyKeys() {
+		printer.PrintLn("\"", escapeQuoted(key), "\" \"", escapeQuoted(extensions.Properties[key]), "\"")
+	}
 	printer.End()
 	printer.PrintLn("}")
 }
 
-func (c c4Exporter) printStyles(printer *Printer) {
-	printer.PrintLn("styles {")
-	printer.Start()
-
-	c.printElementStyles(printer)
-	c.printStateStyles(printer)
-	c.printRelationshipStyles(printer)
+// printEnvironments prints the deployment environments after the relationships, so that Structurizr adds the
+// relationships between the containers to the container instances.
+func (c c4Exporter) printEnvironments(model *ArchitectureModel, printer *Printer) {
+	for _, environment := range model.Environments {
+		printer.PrintLn(environment.Id, "_env = deploymentEnvironment \"", environment.Name, "\" {")
+		printer.Start()
+		for _, deploymentNode := range environment.DeploymentNodes {
+			c.printDeploymentNode(deploymentNode, environment.Id, printer)
+		}
+		printer.End()
+		printer.PrintLn("}")
+	}
+}
 
+func (c c4Exporter) printDeploymentNode(deploymentNode *DeploymentNode, parentId string, printer *Printer) {
+	id := parentId + "_" + deploymentNode.Id
+	printer.PrintLn(id, " = deploymentNode \"", deploymentNode.Name, "\" {")
+	printer.Start()
+	c.printDescription(deploymentNode, printer)
+	c.printTechnology(deploymentNode, printer)
+	c.printTags(&deploymentNode.Extensions, printer)
+	c.printProperties(&deploymentNode.Extensions, printer)
+	for _, nested := range deploymentNode.DeploymentNodes {
+		c.printDeploymentNode(nested, id, printer)
+	}
+	for _, instance := range deploymentNode.Instances {
+		if instance.Replicas == 1 {
+			printer.PrintLn("containerInstance ", nodeIdOf(instance.Element()))
+			continue
+		}
+		c.printReplicas(instance, id, printer)
+	}
 	printer.End()
 	printer.PrintLn("}")
 }
 
-func (c c4Exporter) printElementStyles(printer *Printer) {
-	printer.PrintLn("element \"Person\" {")
+// printReplicas prints a deployment node for the replicas of an instance, since Structurizr only supports instances of
+// deployment nodes. The tag tells the Structurizr importer that the node is part of its parent.
+func (c c4Exporter) printReplicas(instance *Instance, parentId string, printer *Printer) {
+	printer.PrintLn(parentId, "_", nodeIdOf(instance.Element()), "_replicas = deploymentNode \"",
+		nameOf(instance.Element()), "\" {")
 	printer.Start()
-	printer.PrintLn("shape Person")
-	printer.PrintLn("stroke #3966a0")
-	printer.PrintLn("strokeWidth 10")
-	printer.PrintLn("background GhostWhite")
+	printer.PrintLn("tags \"", replicasTag, "\"")
+	printer.PrintLn("instances ", instance.Replicas)
+	printer.PrintLn("containerInstance ", nodeIdOf(instance.Element()))
 	printer.End()
 	printer.PrintLn("}")
+}
 
-	printer.PrintLn("element \"System of Interest\" {")
+func (c c4Exporter) printViews(model *ArchitectureModel, printer *Printer) error {
+	printer.PrintLn("views {")
 	printer.Start()
-	printer.PrintLn("background #b6d7a8")
-	printer.PrintLn("fontSize 36")
-	printer.PrintLn("shape RoundedBox")
-	printer.PrintLn("stroke black")
+	if len(model.DiagramViews) == 0 {
+		c.printDefaultViews(printer)
+	} else {
+		for _, view := range model.DiagramViews {
+			err := c.printFilteredView(view, model, printer)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if len(model.Teams) > 0 {
+		c.printTeamView(model, printer)
+	}
+	for _, environment := range model.Environments {
+		c.printDeploymentView(environment, printer)
+	}
+	c.printStyles(model.theme(), printer)
 	printer.End()
 	printer.PrintLn("}")
+	return nil
+}
 
-	printer.PrintLn("element \"External System\" {")
+func (c c4Exporter) printDefaultViews(printer *Printer) {
+	printer.PrintLn("systemContext ", idOfSystemOfInterest, " {")
 	printer.Start()
-	printer.PrintLn("background #e2e2e2")
-	printer.PrintLn("shape RoundedBox")
-	printer.PrintLn("stroke black")
+	printer.PrintLn("include *")
+	printer.PrintLn("autolayout")
 	printer.End()
 	printer.PrintLn("}")
-
-	printer.PrintLn("element \"central\" {")
+	printer.PrintLn("container ", idOfSystemOfInterest, " {")
 	printer.Start()
-	printer.PrintLn("background #a2c4c9")
+	printer.PrintLn("include *")
+	printer.PrintLn("autolayout")
 	printer.End()
 	printer.PrintLn("}")
+}
 
-	printer.PrintLn("element \"local\" {")
+// printTeamView prints a view of the elements that teams own, which Structurizr shows grouped by team. The view leaves
+// out the relationships, so that it shows ownership rather than dependencies. Without owned elements, there is no view.
+func (c c4Exporter) printTeamView(model *ArchitectureModel, printer *Printer) {
+	ids := make([]string, 0)
+	for _, element := range model.Elements() {
+		if ownerOf(element) != nil && containerOf(element) == element {
+			ids = append(ids, nodeIdOf(element))
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+	printer.PrintLn("container ", idOfSystemOfInterest, " \"teams\" \"Containers by team\" {")
 	printer.Start()
-	printer.PrintLn("background #38761d")
-	printer.PrintLn("color white")
+	printer.PrintLn("title \"Teams\"")
+	printer.PrintLn("include ", strings.Join(ids, " "))
+	printer.PrintLn("exclude \"*->*\"")
+	printer.PrintLn("autolayout")
 	printer.End()
 	printer.PrintLn("}")
+}
 
-	printer.PrintLn("element \"Service\" {")
+func (c c4Exporter) printDeploymentView(environment *Environment, printer *Printer) {
+	printer.PrintLn("deployment ", idOfSystemOfInterest, " \"", environment.Name, "\" \"", environment.Id,
+		"_deployment\" {")
 	printer.Start()
-	printer.PrintLn("stroke black")
+	printer.PrintLn("include *")
+	printer.PrintLn("autolayout")
 	printer.End()
 	printer.PrintLn("}")
+}
 
-	printer.PrintLn("element \"Database\" {")
+// printFilteredView prints a container view of the elements that pass the filter of a view, unless none do.
+func (c c4Exporter) printFilteredView(view *DiagramView, model *ArchitectureModel, printer *Printer) error {
+	slice, err := view.Filter.Apply(*model)
+	if err != nil {
+		return fmt.Errorf("invalid view %v: %v", view.Id, err)
+	}
+	ids := make([]string, 0)
+	for _, element := range slice.Elements() {
+		if containerOf(element) == element && !hasDifferentValueThan(kindOf(element), filterableKinds) {
+			ids = append(ids, nodeIdOf(element))
+		}
+	}
+	if len(ids) == 0 {
+		// Structurizr doesn't allow a view without elements
+		return nil
+	}
+	printer.Print("container ", idOfSystemOfInterest, " \"", view.Id, "\"")
+	if view.Description != "" {
+		printer.Print(" \"", view.Description, "\"")
+	}
+	printer.PrintLn(" {")
 	printer.Start()
-	printer.PrintLn("shape Cylinder")
-	printer.PrintLn("stroke black")
+	printer.PrintLn("title \"", view.Name, "\"")
+	printer.PrintLn("include ", strings.Join(ids, " "))
+	printer.PrintLn("autolayout")
 	printer.End()
 	printer.PrintLn("}")
+	return nil
+}
 
-	printer.PrintLn("element \"Queue\" {")
+func (c c4Exporter) printStyles(theme *Theme, printer *Printer) {
+	printer.PrintLn("styles {")
 	printer.Start()
-	printer.PrintLn("shape Pipe")
-	printer.PrintLn("stroke black")
+
+	c.printElementStyles(theme, printer)
+	c.printStateStyles(theme, printer)
+	c.printRelationshipStyles(printer)
+
 	printer.End()
 	printer.PrintLn("}")
 }
 
-func (c c4Exporter) printStateStyles(printer *Printer) {
-	c.printStateStyle(Ok, "b6d7a8", printer)
-	c.printStateStyle(Emerging, "a4c1f4", printer)
-	c.printStateStyle(Review, "fff2cc", printer)
-	c.printStateStyle(Revision, "ffd966", printer)
-	c.printStateStyle(Legacy, "e69238", printer)
-	c.printStateStyle(Deprecated, "cc0000", printer)
+var c4TagsByKind = map[string]string{
+	"persona":        "Person",
+	"system":         "System of Interest",
+	"externalSystem": "External System",
+	"service":        "Service",
+	"database":       "Database",
+	"queue":          "Queue",
 }
 
-func (c c4Exporter) printStateStyle(state State, backgroundColor string, printer *Printer) {
-	printer.PrintLn("element \"", state.String(), "\" {")
+func (c c4Exporter) printElementStyles(theme *Theme, printer *Printer) {
+	for _, kind := range []string{"persona", "system", "externalSystem"} {
+		c.printStyle(c4TagsByKind[kind], theme.Elements[kind], printer)
+	}
+	for _, name := range theme.TypeNames() {
+		c.printStyle(name, theme.Types[name], printer)
+	}
+	for _, kind := range []string{"service", "database", "queue"} {
+		c.printStyle(c4TagsByKind[kind], theme.Elements[kind], printer)
+	}
+}
+
+func (c c4Exporter) printStateStyles(theme *Theme, printer *Printer) {
+	for index, id := range allowedStates {
+		c.printStyle(State(index).String(), theme.States[id], printer)
+	}
+}
+
+// printStyle prints the style for elements with the given tag. Structurizr doesn't support fonts per element, so the
+// font is ignored.
+func (c c4Exporter) printStyle(tag string, style *Style, printer *Printer) {
+	if style == nil {
+		return
+	}
+	printer.PrintLn("element \"", tag, "\" {")
 	printer.Start()
-	printer.PrintLn("background #", backgroundColor)
+	if style.Shape != "" {
+		printer.PrintLn("shape ", style.Shape)
+	}
+	if style.Background != "" {
+		printer.PrintLn("background ", style.Background)
+	}
+	if style.Color != "" {
+		printer.PrintLn("color ", style.Color)
+	}
+	if style.Stroke != "" {
+		printer.PrintLn("stroke ", style.Stroke)
+	}
+	if style.StrokeWidth != 0 {
+		printer.PrintLn("strokeWidth ", style.StrokeWidth)
+	}
+	if style.FontSize != 0 {
+		printer.PrintLn("fontSize ", style.FontSize)
+	}
 	printer.End()
 	printer.PrintLn("}")
 }
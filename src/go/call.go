@@ -12,15 +12,26 @@ type Call struct {
 	ExternalSystem   *ExternalSystem `yaml:",omitempty"`
 	ServiceId        string          `yaml:"service,omitempty"`
 	Service          *Service        `yaml:",omitempty"`
+	InterfaceId      string          `yaml:"interface,omitempty"`
+	Interface        *Interface      `yaml:",omitempty"`
 	DataFlow         DataFlow
 	TechnologyIds    []string
 	TechnologiesId   string
 	Technologies     []*Technology
+	Extensions
 }
 
 func (c *Call) getTechnologies() []*Technology {
-	//TODO implement me
-	panic("implement me")
+	return c.Technologies
+}
+
+// UsedTechnologies returns the technologies of the call, or those of the called interface if the call doesn't specify
+// its own.
+func (c *Call) UsedTechnologies() []*Technology {
+	if len(c.Technologies) == 0 && c.Interface != nil {
+		return c.Interface.Technologies
+	}
+	return c.Technologies
 }
 
 func (c *Call) getNode() *yaml.Node {
@@ -57,11 +68,14 @@ func (c *Call) setDescription(description string) {
 
 const serviceField = "service"
 const systemField = "externalSystem"
+const interfaceField = "interface"
 
 func (c *Call) setDataFlow(dataFlow DataFlow) {
 	c.DataFlow = dataFlow
 }
 
+var callFields = []string{serviceField, systemField, interfaceField, "description", "dataFlow", "technologies"}
+
 func (c *Call) read(node *yaml.Node) []Issue {
 	c.node = node
 	fields, issue := toMap(node)
@@ -73,6 +87,8 @@ func (c *Call) read(node *yaml.Node) []Issue {
 	issues = append(issues, setDescription(fields, c)...)
 	issues = append(issues, setDataFlow(node, fields, c)...)
 	issues = append(issues, setTechnologies(fields, c)...)
+	issues = append(issues, setExtensions(fields, c)...)
+	issues = append(issues, checkExtensibleFields(fields, callFields)...)
 	return issues
 }
 
@@ -95,6 +111,15 @@ func (c *Call) readCallee(node *yaml.Node, issue *Issue, fields map[string]*yaml
 	} else {
 		issues = append(issues, *NodeError(fmt.Sprintf("One of %v or %v is required", serviceField, systemField), node))
 	}
+	interfaceName, interfaceFound, issue := stringFieldOf(fields, interfaceField)
+	if issue != nil {
+		issues = append(issues, *issue)
+	} else if interfaceFound && !serviceFound {
+		issues = append(issues, *NodeError(fmt.Sprintf("Only a call to a %v can specify an %v", serviceField,
+			interfaceField), fields[interfaceField]))
+	} else if interfaceFound {
+		c.InterfaceId = interfaceName
+	}
 	return issues
 }
 
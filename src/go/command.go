@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"sort"
+)
+
+// Command is a request to change the system, which is handled by a service.
+type Command struct {
+	node        *yaml.Node
+	Id          string
+	Name        string
+	Description string
+	HandledBy   *Service
+	Extensions
+}
+
+func (c *Command) setNode(node *yaml.Node) {
+	c.node = node
+}
+
+func (c *Command) setId(id string) {
+	c.Id = id
+}
+
+func (c *Command) setName(name string) {
+	c.Name = name
+}
+
+func (c *Command) getDescription() string {
+	return c.Description
+}
+
+func (c *Command) setDescription(description string) {
+	c.Description = description
+}
+
+var commandFields = []string{"name", "description"}
+
+// readCommands reads the commands that the service handles, either as a sequence of IDs or as a map of commands.
+func (s *Service) readCommands(fields map[string]*yaml.Node) []Issue {
+	issues := make([]Issue, 0)
+	commands := make([]*Command, 0)
+	commandMaps, found, issue := mapFieldOf(fields, "commands")
+	if found && issue == nil {
+		for id, commandNode := range commandMaps {
+			command := Command{}
+			commands = append(commands, &command)
+			fields, commandIssues := namedObject(commandNode, id, &command)
+			issues = append(issues, commandIssues...)
+			issues = append(issues, setDescription(fields, &command)...)
+			issues = append(issues, checkExtensibleFields(fields, commandFields)...)
+		}
+	} else if found {
+		commandNodes, _, issue := sequenceFieldOf(fields, "commands")
+		if issue != nil {
+			return []Issue{*issue}
+		}
+		for _, commandNode := range commandNodes {
+			id, issue := toString(commandNode, "command")
+			if issue == nil {
+				commands = append(commands, &Command{node: commandNode, Id: id, Name: id})
+			} else {
+				issues = append(issues, *issue)
+			}
+		}
+	}
+	sort.Slice(commands, func(i, j int) bool {
+		return commands[i].Id < commands[j].Id
+	})
+	s.Commands = commands
+	return issues
+}
+
+func (s *Service) findCommandById(id string) (*Command, bool) {
+	for _, candidate := range s.Commands {
+		if candidate.Id == id {
+			return candidate, true
+		}
+	}
+	return nil, false
+}
+
+func (model ArchitectureModel) findCommandById(id string) (*Command, bool) {
+	for _, service := range model.Services {
+		command, found := service.findCommandById(id)
+		if found {
+			return command, true
+		}
+	}
+	return nil, false
+}
+
+// hasCommands returns whether any service declares the commands it handles. Models without commands use free-form
+// command names in their workflows.
+func (model ArchitectureModel) hasCommands() bool {
+	for _, service := range model.Services {
+		if len(service.Commands) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// canIssueCommandTo returns whether a performer can issue commands to a service, either because it's part of the
+// service or because it calls the service.
+func canIssueCommandTo(performer interface{}, service *Service) bool {
+	var calls []*Call
+	switch p := performer.(type) {
+	case *Form:
+		if p.ImplementedBy == service {
+			return true
+		}
+		calls = p.ImplementedBy.Calls
+	case *Service:
+		if p == service {
+			return true
+		}
+		calls = p.Calls
+	case *ExternalSystem:
+		calls = p.Calls
+	}
+	for _, call := range calls {
+		if call.Service == service {
+			return true
+		}
+	}
+	return false
+}
+
+func (s ServiceValidator) validateCommandsAreUnique(services []*Service) []Issue {
+	issues := make([]Issue, 0)
+	commands := map[string]string{}
+	for _, service := range services {
+		for _, command := range service.Commands {
+			handler, found := commands[command.Id]
+			if found {
+				issues = append(issues, *NodeError(fmt.Sprintf("Command '%v' is already handled by service '%v'",
+					command.Id, handler), command.node))
+			} else {
+				commands[command.Id] = service.Id
+			}
+		}
+	}
+	return issues
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"sort"
+)
+
+type composeFile struct {
+	Services map[string]*composeService `yaml:"services"`
+}
+
+// composeService is a service in a docker-compose file. Both depends_on and networks are either a sequence of names or
+// a map with names as keys.
+type composeService struct {
+	Image     string    `yaml:"image"`
+	DependsOn yaml.Node `yaml:"depends_on"`
+	Networks  yaml.Node `yaml:"networks"`
+}
+
+// ImportCompose creates a draft model from a docker-compose file.
+func ImportCompose(fileName string) (*ImportedModel, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return importCompose(data)
+}
+
+// importCompose turns compose services into services, databases, and queues. A service that depends on another
+// calls or uses it, and a service uses the databases and queues on the networks it's on, except the default network.
+func importCompose(data []byte) (*ImportedModel, error) {
+	var compose composeFile
+	err := yaml.Unmarshal(data, &compose)
+	if err != nil {
+		return nil, fmt.Errorf("invalid compose file: %v", err)
+	}
+	result := NewImportedModel()
+	ids := sortedKeys(compose.Services)
+	for _, id := range ids {
+		result.addContainer(id, compose.Services[id].Image)
+	}
+	for _, id := range ids {
+		for _, dependency := range namesIn(&compose.Services[id].DependsOn) {
+			result.use(id, dependency, "Imported from depends_on")
+		}
+	}
+	servicesByNetwork := make(map[string][]string)
+	for _, id := range ids {
+		for _, network := range namesIn(&compose.Services[id].Networks) {
+			servicesByNetwork[network] = append(servicesByNetwork[network], id)
+		}
+	}
+	for _, network := range sortedKeys(servicesByNetwork) {
+		for _, from := range servicesByNetwork[network] {
+			for _, to := range servicesByNetwork[network] {
+				if result.kindOf(to) != "service" {
+					result.use(from, to, fmt.Sprintf("Imported from network %v", network))
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+// namesIn returns the items of a sequence or the keys of a map.
+func namesIn(node *yaml.Node) []string {
+	result := make([]string, 0)
+	switch node.Kind {
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			result = append(result, item.Value)
+		}
+	case yaml.MappingNode:
+		for index := 0; index < len(node.Content); index += 2 {
+			result = append(result, node.Content[index].Value)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
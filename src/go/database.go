@@ -74,7 +74,7 @@ type DatabaseReader struct {
 
 func (d DatabaseReader) read(node *yaml.Node, _ string, model *ArchitectureModel) []Issue {
 	databases := make([]*Database, 0)
-	dataStores, issues := DataStoreReader{}.read(node)
+	dataStores, issues := DataStoreReader{append(dataStoreFields, "views")}.read(node)
 	for _, dataStore := range dataStores {
 		database := Database{DataStore: *dataStore}
 		databases = append(databases, &database)
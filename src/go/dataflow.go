@@ -16,10 +16,15 @@ type DataProcessor interface {
 	setDataFlow(dataFlow DataFlow)
 }
 
-func setDataFlow(owner *yaml.Node, fields map[string]*yaml.Node, dataProcessor DataProcessor) []Issue {
-	const defaultDataFlow = "bidirectional"
-	var allowedDataFlows = []string{"send", "receive", defaultDataFlow}
+const defaultDataFlow = "bidirectional"
+
+var allowedDataFlows = []string{"send", "receive", defaultDataFlow}
 
+func (d DataFlow) String() string {
+	return allowedDataFlows[d]
+}
+
+func setDataFlow(owner *yaml.Node, fields map[string]*yaml.Node, dataProcessor DataProcessor) []Issue {
 	value, issue := enumFieldOf(owner, fields, "dataFlow", allowedDataFlows, defaultDataFlow)
 	if issue != nil {
 		return []Issue{*issue}
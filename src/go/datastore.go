@@ -17,13 +17,9 @@ type DataStore struct {
 	ApiTechnologyIds      []string
 	ApiTechnologyBundleId string
 	ApiTechnologies       []*Technology
-}
-
-func (s *DataStore) Print(printer *Printer) {
-	printer.Print(s.Name)
-	s.State.Print(printer)
-	PrintTechnologies(s.Technologies, printer)
-	printer.NewLine()
+	OwnerId               string
+	Owner                 *Team
+	Extensions
 }
 
 func (s *DataStore) getNode() *yaml.Node {
@@ -70,6 +66,18 @@ func (s *DataStore) setState(state State) {
 	s.State = state
 }
 
+func (s *DataStore) getOwnerId() string {
+	return s.OwnerId
+}
+
+func (s *DataStore) setOwnerId(id string) {
+	s.OwnerId = id
+}
+
+func (s *DataStore) setOwner(team *Team) {
+	s.Owner = team
+}
+
 func (s *DataStore) setTechnologyIds(technologies []string) {
 	s.TechnologyIds = technologies
 }
@@ -110,17 +118,22 @@ func (a ApiTechnologies) setTechnologyBundleId(technologyBundle string) {
 	a.dataStore.ApiTechnologyBundleId = technologyBundle
 }
 
-func (s *DataStore) read(id string, node *yaml.Node) []Issue {
+var dataStoreFields = []string{"name", "description", "state", "technologies", "apiTechnologies", "owner"}
+
+func (s *DataStore) read(id string, node *yaml.Node, allowedFields []string) []Issue {
 	var fields map[string]*yaml.Node
 	fields, issues := namedObject(node, id, s)
 	issues = append(issues, setDescription(fields, s)...)
 	issues = append(issues, setState(node, fields, s)...)
 	issues = append(issues, setTechnologies(fields, s)...)
 	issues = append(issues, setTechnologiesFrom(fields, "apiTechnologies", ApiTechnologies{s})...)
+	issues = append(issues, setOwner(fields, s)...)
+	issues = append(issues, checkExtensibleFields(fields, allowedFields)...)
 	return issues
 }
 
 type DataStoreReader struct {
+	allowedFields []string
 }
 
 func (r DataStoreReader) read(node *yaml.Node) ([]*DataStore, []Issue) {
@@ -136,7 +149,7 @@ func (r DataStoreReader) read(node *yaml.Node) ([]*DataStore, []Issue) {
 	for id, dataStoreNode := range dataStoresById {
 		dataStore := DataStore{}
 		dataStores = append(dataStores, &dataStore)
-		issues = append(issues, dataStore.read(id, dataStoreNode)...)
+		issues = append(issues, dataStore.read(id, dataStoreNode, r.allowedFields)...)
 	}
 	sort.Slice(dataStores, func(i, j int) bool {
 		return dataStores[i].Name < dataStores[j].Name
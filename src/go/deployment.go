@@ -0,0 +1,380 @@
+package main
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"sort"
+)
+
+// Environment is a place where the system is deployed, like production or test.
+type Environment struct {
+	node            *yaml.Node
+	Id              string
+	Name            string
+	Description     string
+	DeploymentNodes []*DeploymentNode
+	Extensions
+}
+
+func (e *Environment) setNode(node *yaml.Node) {
+	e.node = node
+}
+
+func (e *Environment) setId(id string) {
+	e.Id = id
+}
+
+func (e *Environment) setName(name string) {
+	e.Name = name
+}
+
+func (e *Environment) getDescription() string {
+	return e.Description
+}
+
+func (e *Environment) setDescription(description string) {
+	e.Description = description
+}
+
+var environmentFields = []string{"name", "description", "deploymentNodes"}
+
+func (e *Environment) read(id string, node *yaml.Node) []Issue {
+	var fields map[string]*yaml.Node
+	fields, issues := namedObject(node, id, e)
+	issues = append(issues, setDescription(fields, e)...)
+	deploymentNodes, deploymentNodeIssues := readDeploymentNodes(fields)
+	e.DeploymentNodes = deploymentNodes
+	issues = append(issues, deploymentNodeIssues...)
+	issues = append(issues, checkExtensibleFields(fields, environmentFields)...)
+	return issues
+}
+
+// Instances returns the instances that are deployed on any of the deployment nodes in the environment.
+func (e *Environment) Instances() []*Instance {
+	result := make([]*Instance, 0)
+	for _, deploymentNode := range e.DeploymentNodes {
+		result = append(result, deploymentNode.AllInstances()...)
+	}
+	return result
+}
+
+// DeploymentNode is infrastructure that hosts instances of services and data stores, like a cloud region, a cluster,
+// or a virtual machine. Deployment nodes may be nested.
+type DeploymentNode struct {
+	node               *yaml.Node
+	Id                 string
+	Name               string
+	Description        string
+	TechnologyIds      []string
+	TechnologyBundleId string
+	Technologies       []*Technology
+	DeploymentNodes    []*DeploymentNode
+	Instances          []*Instance
+	Extensions
+}
+
+func (d *DeploymentNode) setNode(node *yaml.Node) {
+	d.node = node
+}
+
+func (d *DeploymentNode) setId(id string) {
+	d.Id = id
+}
+
+func (d *DeploymentNode) setName(name string) {
+	d.Name = name
+}
+
+func (d *DeploymentNode) getDescription() string {
+	return d.Description
+}
+
+func (d *DeploymentNode) setDescription(description string) {
+	d.Description = description
+}
+
+func (d *DeploymentNode) getNode() *yaml.Node {
+	return d.node
+}
+
+func (d *DeploymentNode) getTechnologyIds() []string {
+	return d.TechnologyIds
+}
+
+func (d *DeploymentNode) setTechnologyIds(technologies []string) {
+	d.TechnologyIds = technologies
+}
+
+func (d *DeploymentNode) getTechnologyBundleId() string {
+	return d.TechnologyBundleId
+}
+
+func (d *DeploymentNode) setTechnologyBundleId(technologyBundle string) {
+	d.TechnologyBundleId = technologyBundle
+}
+
+func (d *DeploymentNode) getTechnologies() []*Technology {
+	return d.Technologies
+}
+
+func (d *DeploymentNode) setTechnologies(technologies []*Technology) {
+	d.Technologies = technologies
+}
+
+// AllInstances returns the instances deployed on the deployment node and on the deployment nodes nested in it.
+func (d *DeploymentNode) AllInstances() []*Instance {
+	result := append([]*Instance{}, d.Instances...)
+	for _, deploymentNode := range d.DeploymentNodes {
+		result = append(result, deploymentNode.AllInstances()...)
+	}
+	return result
+}
+
+var deploymentNodeFields = []string{"name", "description", "technologies", "deploymentNodes", "instances"}
+
+func (d *DeploymentNode) read(id string, node *yaml.Node) []Issue {
+	var fields map[string]*yaml.Node
+	fields, issues := namedObject(node, id, d)
+	issues = append(issues, setDescription(fields, d)...)
+	issues = append(issues, setTechnologies(fields, d)...)
+	deploymentNodes, deploymentNodeIssues := readDeploymentNodes(fields)
+	d.DeploymentNodes = deploymentNodes
+	issues = append(issues, deploymentNodeIssues...)
+	issues = append(issues, d.readInstances(fields)...)
+	issues = append(issues, checkExtensibleFields(fields, deploymentNodeFields)...)
+	return issues
+}
+
+func readDeploymentNodes(fields map[string]*yaml.Node) ([]*DeploymentNode, []Issue) {
+	deploymentNodes := make([]*DeploymentNode, 0)
+	deploymentNodesById, _, issue := mapFieldOf(fields, "deploymentNodes")
+	if issue != nil {
+		return deploymentNodes, []Issue{*issue}
+	}
+	issues := make([]Issue, 0)
+	for id, deploymentNodeNode := range deploymentNodesById {
+		deploymentNode := DeploymentNode{}
+		deploymentNodes = append(deploymentNodes, &deploymentNode)
+		issues = append(issues, deploymentNode.read(id, deploymentNodeNode)...)
+	}
+	sort.Slice(deploymentNodes, func(i, j int) bool {
+		return deploymentNodes[i].Name < deploymentNodes[j].Name
+	})
+	return deploymentNodes, issues
+}
+
+func (d *DeploymentNode) readInstances(fields map[string]*yaml.Node) []Issue {
+	instanceNodes, _, issue := sequenceFieldOf(fields, "instances")
+	if issue != nil {
+		return []Issue{*issue}
+	}
+	issues := make([]Issue, 0)
+	instances := make([]*Instance, 0)
+	for _, instanceNode := range instanceNodes {
+		instance := Instance{}
+		instances = append(instances, &instance)
+		issues = append(issues, instance.read(instanceNode)...)
+	}
+	d.Instances = instances
+	return issues
+}
+
+// Instance is a service, database, or queue that is deployed on a deployment node, with a number of replicas.
+type Instance struct {
+	node       *yaml.Node
+	ServiceId  string
+	Service    *Service
+	DatabaseId string
+	Database   *Database
+	QueueId    string
+	Queue      *DataStore
+	Replicas   int
+}
+
+var instanceFields = []string{"service", "database", "queue", "replicas"}
+
+func (i *Instance) read(node *yaml.Node) []Issue {
+	i.node = node
+	i.Replicas = 1
+	fields, issue := toMap(node)
+	if issue != nil {
+		return []Issue{*issue}
+	}
+	issues := make([]Issue, 0)
+	numFound := 0
+	for field, target := range map[string]*string{"service": &i.ServiceId, "database": &i.DatabaseId,
+		"queue": &i.QueueId} {
+		value, found, issue := stringFieldOf(fields, field)
+		if issue != nil {
+			issues = append(issues, *issue)
+		} else if found {
+			*target = value
+			numFound++
+		}
+	}
+	if numFound != 1 {
+		issues = append(issues, *NodeError("An instance must be either a service, a database, or a queue", node))
+	}
+	replicasNode, found := fields["replicas"]
+	if found {
+		replicas, issue := toNumber(replicasNode, "replicas")
+		if issue != nil {
+			issues = append(issues, *issue)
+		} else if replicas <= 0 || replicas != float64(int(replicas)) {
+			issues = append(issues, *NodeError("replicas must be a positive whole number", replicasNode))
+		} else {
+			i.Replicas = int(replicas)
+		}
+	}
+	issues = append(issues, checkFields(fields, instanceFields)...)
+	return issues
+}
+
+// Element returns the service, database, or queue that the instance deploys.
+func (i *Instance) Element() interface{} {
+	if i.Service != nil {
+		return i.Service
+	}
+	if i.Database != nil {
+		return i.Database
+	}
+	if i.Queue != nil {
+		return i.Queue
+	}
+	return nil
+}
+
+type EnvironmentReader struct {
+}
+
+func (r EnvironmentReader) read(node *yaml.Node, _ string, model *ArchitectureModel) []Issue {
+	if node == nil {
+		return []Issue{}
+	}
+	environmentsById, issue := toMap(node)
+	if issue != nil {
+		return []Issue{*issue}
+	}
+	issues := make([]Issue, 0)
+	environments := make([]*Environment, 0)
+	for id, environmentNode := range environmentsById {
+		environment := Environment{}
+		environments = append(environments, &environment)
+		issues = append(issues, environment.read(id, environmentNode)...)
+	}
+	sort.Slice(environments, func(i, j int) bool {
+		return environments[i].Name < environments[j].Name
+	})
+	model.Environments = environments
+	return issues
+}
+
+func (model ArchitectureModel) findEnvironmentById(id string) (*Environment, bool) {
+	for _, candidate := range model.Environments {
+		if candidate.Id == id {
+			return candidate, true
+		}
+	}
+	return nil, false
+}
+
+type DeploymentConnector struct {
+}
+
+func (c DeploymentConnector) connect(model *ArchitectureModel) []Issue {
+	issues := make([]Issue, 0)
+	for _, environment := range model.Environments {
+		for _, deploymentNode := range environment.DeploymentNodes {
+			issues = append(issues, c.connectDeploymentNode(deploymentNode, model)...)
+		}
+	}
+	return issues
+}
+
+func (c DeploymentConnector) connectDeploymentNode(deploymentNode *DeploymentNode, model *ArchitectureModel) []Issue {
+	issues := connectTechnologies(deploymentNode, model)
+	for _, instance := range deploymentNode.Instances {
+		issues = append(issues, c.connectInstance(instance, model)...)
+	}
+	for _, nested := range deploymentNode.DeploymentNodes {
+		issues = append(issues, c.connectDeploymentNode(nested, model)...)
+	}
+	return issues
+}
+
+func (c DeploymentConnector) connectInstance(instance *Instance, model *ArchitectureModel) []Issue {
+	if instance.ServiceId != "" {
+		service, issue := findService(instance.node, instance.ServiceId, model)
+		if issue != nil {
+			return []Issue{*issue}
+		}
+		instance.Service = service
+	} else if instance.DatabaseId != "" {
+		database, found := model.findDatabaseById(instance.DatabaseId)
+		if !found {
+			return []Issue{*NodeError(fmt.Sprintf("Unknown database '%v'", instance.DatabaseId), instance.node)}
+		}
+		instance.Database = database
+	} else if instance.QueueId != "" {
+		queue, found := model.findQueueById(instance.QueueId)
+		if !found {
+			return []Issue{*NodeError(fmt.Sprintf("Unknown queue '%v'", instance.QueueId), instance.node)}
+		}
+		instance.Queue = queue
+	}
+	return []Issue{}
+}
+
+// DeploymentValidator warns about services and data stores that aren't deployed in an environment, unless they're
+// deprecated. The warnings point at the elements that aren't deployed.
+type DeploymentValidator struct {
+}
+
+func (v DeploymentValidator) validate(model *ArchitectureModel) []Issue {
+	issues := make([]Issue, 0)
+	deployables := make([]interface{}, 0)
+	for _, service := range model.Services {
+		deployables = append(deployables, service)
+	}
+	for _, database := range model.Databases {
+		deployables = append(deployables, database)
+	}
+	for _, queue := range model.Queues {
+		deployables = append(deployables, queue)
+	}
+	for _, environment := range model.Environments {
+		deployed := make(map[interface{}]bool)
+		for _, instance := range environment.Instances() {
+			deployed[instance.Element()] = true
+		}
+		for _, deployable := range deployables {
+			if state, _ := stateOf(deployable); state != Deprecated && !deployed[deployable] {
+				issues = append(issues, *NodeWarning(fmt.Sprintf("%v '%v' isn't deployed in environment '%v'",
+					v.labelOf(deployable), idOf(deployable), environment.Id), v.nodeOf(deployable)))
+			}
+		}
+	}
+	return issues
+}
+
+func (v DeploymentValidator) nodeOf(deployable interface{}) *yaml.Node {
+	switch d := deployable.(type) {
+	case *Service:
+		return d.node
+	case *Database:
+		return d.node
+	default:
+		return deployable.(*DataStore).node
+	}
+}
+
+func (v DeploymentValidator) labelOf(deployable interface{}) string {
+	switch deployable.(type) {
+	case *Service:
+		return "Service"
+	case *Database:
+		return "Database"
+	default:
+		return "Queue"
+	}
+}
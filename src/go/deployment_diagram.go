@@ -0,0 +1,100 @@
+package main
+
+import "fmt"
+
+type deploymentExporter struct {
+	environmentId string
+}
+
+// NewDeploymentExporter creates an exporter that renders an environment as a Graphviz diagram, with a cluster for each
+// deployment node. Without an environment ID, the model must have exactly one environment.
+func NewDeploymentExporter(environmentId string) TextExporter {
+	return deploymentExporter{environmentId}
+}
+
+func (d deploymentExporter) export(model ArchitectureModel, printer *Printer) error {
+	environment, err := d.environmentOf(&model)
+	if err != nil {
+		return err
+	}
+	printer.PrintLn("digraph {")
+	printer.Start()
+	printer.PrintLn("compound=true")
+	printer.PrintLn("label=\"", environment.Name, "\"")
+	theme := dotThemeOf(&model)
+	instanceIds := make(map[interface{}][]string)
+	for _, deploymentNode := range environment.DeploymentNodes {
+		d.printDeploymentNode(deploymentNode, environment.Id, theme, instanceIds, printer)
+	}
+	d.printRelationships(&model, instanceIds, printer)
+	printer.End()
+	printer.PrintLn("}")
+	return nil
+}
+
+func (d deploymentExporter) environmentOf(model *ArchitectureModel) (*Environment, error) {
+	if d.environmentId != "" {
+		environment, found := model.findEnvironmentById(d.environmentId)
+		if !found {
+			return nil, fmt.Errorf("unknown environment '%v'", d.environmentId)
+		}
+		return environment, nil
+	}
+	if len(model.Environments) != 1 {
+		return nil, fmt.Errorf("model has %v environments, specify which one to export", len(model.Environments))
+	}
+	return model.Environments[0], nil
+}
+
+// printDeploymentNode prints a deployment node as a cluster and collects the IDs of the diagram nodes for the instances
+// of each container.
+func (d deploymentExporter) printDeploymentNode(deploymentNode *DeploymentNode, parentId string, theme *Theme,
+	instanceIds map[interface{}][]string, printer *Printer) {
+	id := parentId + "_" + deploymentNode.Id
+	printer.PrintLn("subgraph cluster_", id, " {")
+	printer.Start()
+	printer.Print("label=\"", deploymentNode.Name)
+	prefix := "\\n["
+	for _, technology := range deploymentNode.Technologies {
+		printer.Print(prefix, technology.Name)
+		prefix = ", "
+	}
+	if len(deploymentNode.Technologies) > 0 {
+		printer.Print("]")
+	}
+	printer.PrintLn("\"")
+	for _, nested := range deploymentNode.DeploymentNodes {
+		d.printDeploymentNode(nested, id, theme, instanceIds, printer)
+	}
+	for _, instance := range deploymentNode.Instances {
+		element := instance.Element()
+		instanceId := id + "_" + nodeIdOf(element)
+		instanceIds[element] = append(instanceIds[element], instanceId)
+		label := nameOf(element)
+		if instance.Replicas > 1 {
+			label = fmt.Sprintf("%v (x%v)", label, instance.Replicas)
+		}
+		printer.PrintLn(instanceId, "[", dotAttributesOf(theme.StyleOf(element)),
+			dotAttributesOfExtensions(extensionsOf(element)), ",label=\"", label, "\"]")
+	}
+	printer.End()
+	printer.PrintLn("}")
+}
+
+// printRelationships connects the instances of containers that have a relationship in the model. Relationships with
+// elements that aren't deployed in the environment, like personas, are left out.
+func (d deploymentExporter) printRelationships(model *ArchitectureModel, instanceIds map[interface{}][]string,
+	printer *Printer) {
+	printed := make(map[string]bool)
+	for _, relationship := range model.Relationships() {
+		for _, from := range instanceIds[containerOf(relationship.From)] {
+			for _, to := range instanceIds[containerOf(relationship.To)] {
+				edge := fmt.Sprintf("%v -> %v [dir=%v]", from, to, dotExporter{}.directionOf(relationship.DataFlow))
+				if !printed[edge] {
+					printed[edge] = true
+					printer.PrintLn(edge)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDeploymentDiagram(t *testing.T) {
+	model, issues := LintText(deploymentDefinition)
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+	printer := NewPrinter()
+
+	err := NewDeploymentExporter("").export(*model, printer)
+
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	expected := `digraph {
+    compound=true
+    label="Production"
+    subgraph cluster_production_cloud {
+        label="Cloud"
+        subgraph cluster_production_cloud_cluster {
+            label="Cluster\n[Kubernetes]"
+            production_cloud_cluster_api[shape=box,style=filled,fillcolor="#b6d7a8",label="Api (x3)"]
+            production_cloud_cluster_events_q[shape=parallelogram,style=filled,fillcolor="#b6d7a8",label="Events"]
+        }
+        production_cloud_customers_db[shape=cylinder,style=filled,fillcolor="#b6d7a8",label="Customers"]
+    }
+    production_cloud_cluster_api -> production_cloud_customers_db [dir=both]
+    production_cloud_cluster_api -> production_cloud_cluster_events_q [dir=forward]
+}
+`
+	if printer.String() != expected {
+		t.Errorf("Expected:\n%v\nbut got:\n%v", expected, printer.String())
+	}
+}
+
+func TestDeploymentDiagramOfUnknownEnvironment(t *testing.T) {
+	model, _ := LintText(deploymentDefinition)
+
+	err := NewDeploymentExporter("test").export(*model, NewPrinter())
+
+	if err == nil || err.Error() != "unknown environment 'test'" {
+		t.Errorf("Expected error for unknown environment, got %v", err)
+	}
+}
+
+func TestC4ExportOfEnvironments(t *testing.T) {
+	model, _ := LintText(deploymentDefinition)
+	printer := NewPrinter()
+
+	err := NewC4Exporter().export(*model, printer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{`        production_env = deploymentEnvironment "Production" {
+            production_cloud = deploymentNode "Cloud" {
+                production_cloud_cluster = deploymentNode "Cluster" {
+                    technology "Kubernetes"
+                    production_cloud_cluster_api_replicas = deploymentNode "Api" {
+                        tags "Replicas"
+                        instances 3
+                        containerInstance api
+                    }
+                    containerInstance events_q
+                }
+                containerInstance customers_db
+            }
+        }
+`, `        deployment system "Production" "production_deployment" {
+            include *
+            autolayout
+        }
+`} {
+		if !strings.Contains(printer.String(), expected) {
+			t.Errorf("Missing %v\ngot:\n%v", expected, printer.String())
+		}
+	}
+}
+
+func TestFilteredExportsOfEnvironments(t *testing.T) {
+	model, issues := LintText(deploymentDefinition)
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+	slice, err := ViewFilter{IncludeKinds: []string{"queue"}}.Apply(*model)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	printer := NewPrinter()
+	err = NewC4Exporter().export(slice, printer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `        production_env = deploymentEnvironment "Production" {
+            production_cloud = deploymentNode "Cloud" {
+                production_cloud_cluster = deploymentNode "Cluster" {
+                    technology "Kubernetes"
+                    containerInstance events_q
+                }
+            }
+        }
+`
+	if !strings.Contains(printer.String(), expected) {
+		t.Errorf("Missing %v\ngot:\n%v", expected, printer.String())
+	}
+
+	printer = NewPrinter()
+	err = NewDeploymentExporter("").export(slice, printer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = `digraph {
+    compound=true
+    label="Production"
+    subgraph cluster_production_cloud {
+        label="Cloud"
+        subgraph cluster_production_cloud_cluster {
+            label="Cluster\n[Kubernetes]"
+            production_cloud_cluster_events_q[shape=parallelogram,style=filled,fillcolor="#b6d7a8",label="Events"]
+        }
+    }
+}
+`
+	if printer.String() != expected {
+		t.Errorf("Expected:\n%v\nbut got:\n%v", expected, printer.String())
+	}
+
+	slice, err = ViewFilter{States: []string{"legacy"}}.Apply(*model)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(slice.Environments) != 1 || len(slice.Environments[0].DeploymentNodes) != 0 {
+		t.Errorf("Deployment nodes without instances are kept: %+v", slice.Environments)
+	}
+	if len(model.Environments[0].Instances()) != 3 {
+		t.Errorf("Original model changed")
+	}
+}
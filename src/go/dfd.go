@@ -8,17 +8,66 @@ func NewDfdExporter() TextExporter {
 }
 
 func (d dfdExporter) export(model ArchitectureModel, printer *Printer) error {
-	d.printPersonas(model.Personas, printer)
-	d.printExternalSystems(model.ExternalSystems, printer)
-	d.printServices(model.Services, printer)
-	d.printDatabases(model.Databases, printer)
-	d.printQueues(model.Queues, printer)
+	theme := dfdThemeOf(&model)
+	d.printPersonas(model.Personas, theme, printer)
+	d.printExternalSystems(model.ExternalSystems, theme, printer)
+	d.printServices(model.Services, theme, printer)
+	d.printDatabases(model.Databases, theme, printer)
+	d.printQueues(model.Queues, theme, printer)
 	return nil
 }
 
-func (d dfdExporter) printPersonas(personas []*Persona, printer *Printer) {
+// dfdThemeOf returns the theme of the model, or, for a model without styles, an empty theme, so that D2 uses its own
+// defaults.
+func dfdThemeOf(model *ArchitectureModel) *Theme {
+	if model.Theme != nil {
+		return model.Theme
+	}
+	return &Theme{map[string]*Style{}, map[string]*Style{}, map[string]*Style{}}
+}
+
+// printNode prints a node in the notation of data flow diagrams, so the shape of the style is ignored. Nodes without
+// a style and with at most one line for their shape take a single line.
+func (d dfdExporter) printNode(id string, name string, style Style, printer *Printer, shape ...string) {
+	if style.IsEmpty() && len(shape) <= 1 {
+		printer.Print(id, ": ", name)
+		for _, line := range shape {
+			printer.Print(" { ", line, " }")
+		}
+		printer.NewLine()
+		return
+	}
+	printer.PrintLn(id, ": ", name, " {")
+	printer.Start()
+	for _, line := range shape {
+		printer.PrintLn(line)
+	}
+	if style.Background != "" {
+		printer.PrintLn("style.fill: \"", style.Background, "\"")
+	}
+	if style.Stroke != "" {
+		printer.PrintLn("style.stroke: \"", style.Stroke, "\"")
+	}
+	if style.StrokeWidth != 0 {
+		// D2 uses the same scale as Structurizr, but allows at most 15
+		printer.PrintLn("style.stroke-width: ", minOf(style.StrokeWidth, 15))
+	}
+	if style.Color != "" {
+		printer.PrintLn("style.font-color: \"", style.Color, "\"")
+	}
+	if style.Font != "" {
+		printer.PrintLn("style.font: ", style.Font)
+	}
+	if style.FontSize != 0 {
+		printer.PrintLn("style.font-size: ", style.FontSize)
+	}
+	printer.End()
+	printer.PrintLn("}")
+}
+
+func (d dfdExporter) printPersonas(personas []*Persona, theme *Theme, printer *Printer) {
 	for _, persona := range personas {
-		printer.PrintLn(persona.Id, ": ", persona.Name)
+		d.printNode(persona.Id, persona.Name, theme.StyleOf(persona), printer)
 		for _, use := range persona.Uses {
 			d.printPersonaUse(persona, use, printer)
 		}
@@ -52,9 +101,9 @@ func (d dfdExporter) dataFlowOf(dataFlow DataFlow) string {
 	}
 }
 
-func (d dfdExporter) printExternalSystems(externalSystems []*ExternalSystem, printer *Printer) {
+func (d dfdExporter) printExternalSystems(externalSystems []*ExternalSystem, theme *Theme, printer *Printer) {
 	for _, externalSystem := range externalSystems {
-		printer.PrintLn(externalSystem.Id, ": ", externalSystem.Name)
+		d.printNode(externalSystem.Id, externalSystem.Name, theme.StyleOf(externalSystem), printer)
 		for _, call := range externalSystem.Calls {
 			d.printCall(externalSystem.Id, call, printer)
 		}
@@ -70,7 +119,7 @@ func (d dfdExporter) printCall(fromId string, call *Call, printer *Printer) {
 	} else {
 		panic(*call)
 	}
-	d.printTechnologies(call.Technologies, printer)
+	d.printTechnologies(call.UsedTechnologies(), printer)
 	printer.NewLine()
 }
 
@@ -86,9 +135,9 @@ func (d dfdExporter) printTechnologies(technologies []*Technology, printer *Prin
 	}
 }
 
-func (d dfdExporter) printServices(services []*Service, printer *Printer) {
+func (d dfdExporter) printServices(services []*Service, theme *Theme, printer *Printer) {
 	for _, service := range services {
-		printer.PrintLn(service.Id, ": ", service.Name, " { shape: circle }")
+		d.printNode(service.Id, service.Name, theme.StyleOf(service), printer, "shape: circle")
 		for _, call := range service.Calls {
 			d.printCall(service.Id, call, printer)
 		}
@@ -112,23 +161,19 @@ func (d dfdExporter) printDataStoreUse(fromId string, use *DataStoreUse, printer
 	printer.NewLine()
 }
 
-func (d dfdExporter) printDatabases(databases []*Database, printer *Printer) {
+func (d dfdExporter) printDatabases(databases []*Database, theme *Theme, printer *Printer) {
 	for _, database := range databases {
-		d.printDataStore(database.DataStore, "_db", printer)
+		d.printDataStore(database.Id+"_db", database.Name, theme.StyleOf(database), printer)
 	}
 }
 
-func (d dfdExporter) printDataStore(dataStore DataStore, suffix string, printer *Printer) {
-	printer.PrintLn(dataStore.Id, suffix, ": ", dataStore.Name, " {")
-	printer.Start()
-	printer.PrintLn("shape: image")
-	printer.PrintLn("icon: https://github.com/RemonSinnema/architecture-diagrams/raw/main/static/data-store.png")
-	printer.End()
-	printer.PrintLn("}")
+func (d dfdExporter) printDataStore(id string, name string, style Style, printer *Printer) {
+	d.printNode(id, name, style, printer, "shape: image",
+		"icon: https://github.com/RemonSinnema/architecture-diagrams/raw/main/static/data-store.png")
 }
 
-func (d dfdExporter) printQueues(queues []*DataStore, printer *Printer) {
+func (d dfdExporter) printQueues(queues []*DataStore, theme *Theme, printer *Printer) {
 	for _, queue := range queues {
-		d.printDataStore(*queue, "_q", printer)
+		d.printDataStore(queue.Id+"_q", queue.Name, theme.StyleOf(queue), printer)
 	}
 }
@@ -1,6 +1,8 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+)
 
 type dotExporter struct {
 }
@@ -21,16 +23,17 @@ func (d dotExporter) export(model ArchitectureModel, printer *Printer) error {
 }
 
 func (d dotExporter) printModel(model *ArchitectureModel, printer *Printer) {
-	d.printPersonas(model.Personas, printer)
-	d.printExternalSystems(model.ExternalSystems, printer)
-	d.printServices(model.Services, printer)
-	d.printDatabase(model.Databases, printer)
-	d.printQueue(model.Queues, printer)
+	theme := dotThemeOf(model)
+	d.printPersonas(model.Personas, theme, printer)
+	d.printExternalSystems(model.ExternalSystems, theme, printer)
+	d.printServices(model.Services, theme, printer)
+	d.printDatabase(model.Databases, theme, printer)
+	d.printQueue(model.Queues, theme, printer)
 }
 
-func (d dotExporter) printPersonas(personas []*Persona, printer *Printer) {
+func (d dotExporter) printPersonas(personas []*Persona, theme *Theme, printer *Printer) {
 	for _, persona := range personas {
-		printer.PrintLn(persona.Id, "[shape=polygon,sides=5,color=\"#3966a0\",label=\"", persona.Name, "\"]")
+		d.printNode(persona.Id, persona, persona.Name, theme, printer)
 		for _, use := range persona.Uses {
 			target := d.using(use)
 			if target != "" {
@@ -67,14 +70,37 @@ func (d dotExporter) directionOf(dataFlow DataFlow) string {
 	}
 }
 
-func (d dotExporter) printExternalSystems(externalSystems []*ExternalSystem, printer *Printer) {
+// dotThemeOf returns the theme of the model, or, for a model without styles, the styles that Graphviz exports have
+// always used. Those differ from the default theme, which is tuned for Structurizr.
+func dotThemeOf(model *ArchitectureModel) *Theme {
+	if model.Theme != nil {
+		return model.Theme
+	}
+	return &Theme{
+		Elements: map[string]*Style{
+			"persona":        {Shape: "Person", Stroke: "#3966a0"},
+			"externalSystem": {Shape: "RoundedBox", Background: "#e2e2e2"},
+			"database":       {Shape: "Cylinder"},
+			"queue":          {Shape: "Pipe"},
+		},
+		Types:  map[string]*Style{},
+		States: defaultTheme().States,
+	}
+}
+
+func (d dotExporter) printNode(id string, element interface{}, name string, theme *Theme, printer *Printer) {
+	printer.PrintLn(id, "[", dotAttributesOf(theme.StyleOf(element)), dotAttributesOfExtensions(extensionsOf(element)),
+		",label=\"", name, "\"]")
+}
+
+func (d dotExporter) printExternalSystems(externalSystems []*ExternalSystem, theme *Theme, printer *Printer) {
 	for _, externalSystem := range externalSystems {
-		printer.PrintLn(externalSystem.Id, "[shape=rectangle,style=\"rounded,filled\",fillcolor=\"#e2e2e2\",label=\"",
-			externalSystem.Name, "\"]")
+		d.printNode(externalSystem.Id, externalSystem, externalSystem.Name, theme, printer)
 		for _, call := range externalSystem.Calls {
 			target := d.calling(call)
 			if target != "" {
-				printer.PrintLn(externalSystem.Id, " -> ", target, " [dir=", d.directionOf(call.DataFlow), "]")
+				printer.PrintLn(externalSystem.Id, " -> ", target, " [dir=", d.directionOf(call.DataFlow),
+					d.labelOf(call.UsedTechnologies()), dotAttributesOfExtensions(&call.Extensions), "]")
 			}
 		}
 	}
@@ -91,14 +117,14 @@ func (d dotExporter) calling(call *Call) string {
 	return ""
 }
 
-func (d dotExporter) printServices(services []*Service, printer *Printer) {
+func (d dotExporter) printServices(services []*Service, theme *Theme, printer *Printer) {
 	for _, service := range services {
-		printer.PrintLn(service.Id, "[shape=box,style=filled,fillcolor=\"#", d.colorOf(service.State), "\",label=\"",
-			service.Name, "\"]")
+		d.printNode(service.Id, service, service.Name, theme, printer)
 		for _, call := range service.Calls {
 			target := d.calling(call)
 			if target != "" {
-				printer.PrintLn(service.Id, " -> ", target, " [dir=", d.directionOf(call.DataFlow), "]")
+				printer.PrintLn(service.Id, " -> ", target, " [dir=", d.directionOf(call.DataFlow),
+					d.labelOf(call.UsedTechnologies()), dotAttributesOfExtensions(&call.Extensions), "]")
 			}
 		}
 		for _, dataStore := range service.DataStores {
@@ -111,23 +137,12 @@ func (d dotExporter) printServices(services []*Service, printer *Printer) {
 	printer.PrintLn()
 }
 
-func (d dotExporter) colorOf(state State) string {
-	switch state {
-	case Ok:
-		return "b6d7a8"
-	case Emerging:
-		return "a4c1f4"
-	case Review:
-		return "fff2cc"
-	case Revision:
-		return "ffd966"
-	case Legacy:
-		return "e69238"
-	case Deprecated:
-		return "cc0000"
-	default:
-		return "ghostwhite"
+// labelOf returns the label attribute for an edge that uses the given technologies.
+func (d dotExporter) labelOf(technologies []*Technology) string {
+	if len(technologies) == 0 {
+		return ""
 	}
+	return fmt.Sprintf(",label=\"%v\"", joinTechnologies(technologies))
 }
 
 func (d dotExporter) storingIn(store *DataStoreUse) string {
@@ -140,16 +155,15 @@ func (d dotExporter) storingIn(store *DataStoreUse) string {
 	return ""
 }
 
-func (d dotExporter) printDatabase(databases []*Database, printer *Printer) {
+func (d dotExporter) printDatabase(databases []*Database, theme *Theme, printer *Printer) {
 	for _, database := range databases {
-		printer.PrintLn(database.Id, "_db [shape=cylinder,style=filled,fillcolor=\"#", d.colorOf(database.State),
-			"\",label=\"", database.Name, "\"]")
+		// Data stores have a space before their attributes, like they always had
+		d.printNode(database.Id+"_db ", database, database.Name, theme, printer)
 	}
 }
 
-func (d dotExporter) printQueue(queues []*DataStore, printer *Printer) {
+func (d dotExporter) printQueue(queues []*DataStore, theme *Theme, printer *Printer) {
 	for _, queue := range queues {
-		printer.PrintLn(queue.Id, "_q [shape=parallelogram,style=filled,fillcolor=\"#", d.colorOf(queue.State),
-			"\",label=\"", queue.Name, "\"]")
+		d.printNode(queue.Id+"_q ", queue, queue.Name, theme, printer)
 	}
 }
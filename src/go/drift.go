@@ -0,0 +1,88 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+// Drift is the difference between a hand-maintained model and a model imported from infrastructure. Only the kinds of
+// elements that the importer can find are compared, and elements are referred to like service:api.
+type Drift struct {
+	// Unmodeled are the imported elements that the model doesn't have.
+	Unmodeled []string
+	// Missing are the elements of the model that weren't imported.
+	Missing []string
+}
+
+// FindDrift compares the elements of a model with those of an imported model. IDs match when they only differ in case
+// or in dashes, underscores, and dots, so that a Kubernetes name like order-service matches an ID like orderService.
+func FindDrift(model *ArchitectureModel, imported *ImportedModel) Drift {
+	modeled := map[string][]string{
+		"service":  make([]string, 0, len(model.Services)),
+		"database": make([]string, 0, len(model.Databases)),
+		"queue":    make([]string, 0, len(model.Queues)),
+	}
+	for _, service := range model.Services {
+		modeled["service"] = append(modeled["service"], service.Id)
+	}
+	for _, database := range model.Databases {
+		modeled["database"] = append(modeled["database"], database.Id)
+	}
+	for _, queue := range model.Queues {
+		modeled["queue"] = append(modeled["queue"], queue.Id)
+	}
+	found := map[string][]string{
+		"service":  sortedKeys(imported.Services),
+		"database": sortedKeys(imported.Databases),
+		"queue":    sortedKeys(imported.Queues),
+	}
+	result := Drift{make([]string, 0), make([]string, 0)}
+	for _, kind := range imported.Kinds {
+		result.Unmodeled = append(result.Unmodeled, refsNotIn(kind, found[kind], modeled[kind])...)
+		result.Missing = append(result.Missing, refsNotIn(kind, modeled[kind], found[kind])...)
+	}
+	sort.Strings(result.Unmodeled)
+	sort.Strings(result.Missing)
+	return result
+}
+
+func refsNotIn(kind string, ids []string, others []string) []string {
+	known := make(map[string]bool)
+	for _, other := range others {
+		known[normalizedId(other)] = true
+	}
+	result := make([]string, 0)
+	for _, id := range ids {
+		if !known[normalizedId(id)] {
+			result = append(result, kind+":"+id)
+		}
+	}
+	return result
+}
+
+func normalizedId(id string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", "_", "", ".", "").Replace(id))
+}
+
+// Print prints the drift, with the source of the imported model, like k8s, to explain where elements were found.
+func (d Drift) Print(source string, printer *Printer) {
+	if len(d.Unmodeled) == 0 && len(d.Missing) == 0 {
+		printer.PrintLn("No drift between the model and ", source)
+		return
+	}
+	if len(d.Unmodeled) > 0 {
+		printer.PrintLn("In ", source, " but not in the model:")
+		for _, ref := range d.Unmodeled {
+			printer.PrintLn("- ", ref)
+		}
+	}
+	if len(d.Missing) > 0 {
+		if len(d.Unmodeled) > 0 {
+			printer.NewLine()
+		}
+		printer.PrintLn("In the model but not in ", source, ":")
+		for _, ref := range d.Missing {
+			printer.PrintLn("- ", ref)
+		}
+	}
+}
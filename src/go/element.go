@@ -0,0 +1,197 @@
+package main
+
+// Elements returns all identifiable elements of the model, grouped by kind.
+func (model ArchitectureModel) Elements() []interface{} {
+	result := make([]interface{}, 0)
+	for _, persona := range model.Personas {
+		result = append(result, persona)
+	}
+	for _, externalSystem := range model.ExternalSystems {
+		result = append(result, externalSystem)
+	}
+	for _, service := range model.Services {
+		result = append(result, service)
+		for _, form := range service.Forms {
+			result = append(result, form)
+		}
+		for _, command := range service.Commands {
+			result = append(result, command)
+		}
+	}
+	for _, database := range model.Databases {
+		result = append(result, database)
+		for _, view := range database.Views {
+			result = append(result, view)
+		}
+	}
+	for _, queue := range model.Queues {
+		result = append(result, queue)
+	}
+	for _, event := range model.Events {
+		result = append(result, event)
+	}
+	for _, technology := range model.Technologies {
+		result = append(result, technology)
+	}
+	for _, workflow := range model.Workflows {
+		result = append(result, workflow)
+	}
+	return result
+}
+
+// kindOf returns the kind of model element, as used in references like service:api.
+func kindOf(element interface{}) string {
+	switch element.(type) {
+	case *Persona:
+		return "persona"
+	case *ExternalSystem:
+		return "externalSystem"
+	case *Service:
+		return "service"
+	case *Form:
+		return "form"
+	case *Command:
+		return "command"
+	case *Database:
+		return "database"
+	case *View:
+		return "view"
+	case *DataStore:
+		return "queue"
+	case *Event:
+		return "event"
+	case *Technology:
+		return "technology"
+	case *Workflow:
+		return "workflow"
+	default:
+		return ""
+	}
+}
+
+func idOf(element interface{}) string {
+	switch e := element.(type) {
+	case *Persona:
+		return e.Id
+	case *ExternalSystem:
+		return e.Id
+	case *Service:
+		return e.Id
+	case *Form:
+		return e.Id
+	case *Command:
+		return e.Id
+	case *Database:
+		return e.Id
+	case *View:
+		return e.Id
+	case *DataStore:
+		return e.Id
+	case *Event:
+		return e.Id
+	case *Technology:
+		return e.Id
+	case *Workflow:
+		return e.Id
+	default:
+		return ""
+	}
+}
+
+func nameOf(element interface{}) string {
+	switch e := element.(type) {
+	case *Persona:
+		return e.Name
+	case *ExternalSystem:
+		return e.Name
+	case *Service:
+		return e.Name
+	case *Form:
+		return e.Name
+	case *Command:
+		return e.Name
+	case *Database:
+		return e.Name
+	case *View:
+		return e.Name
+	case *DataStore:
+		return e.Name
+	case *Event:
+		return e.Name
+	case *Technology:
+		return e.Name
+	case *Workflow:
+		return e.Name
+	default:
+		return ""
+	}
+}
+
+// refOf returns a reference to the element that is unique in the model, like service:api.
+func refOf(element interface{}) string {
+	return kindOf(element) + ":" + idOf(element)
+}
+
+// stateOf returns the state of the element and whether the element has a state at all.
+func stateOf(element interface{}) (State, bool) {
+	switch e := element.(type) {
+	case *Service:
+		return e.State, true
+	case *Form:
+		return e.State, true
+	case *Database:
+		return e.State, true
+	case *View:
+		return e.State, true
+	case *DataStore:
+		return e.State, true
+	default:
+		return Ok, false
+	}
+}
+
+// technologiesOf returns the technologies used to implement the element.
+func technologiesOf(element interface{}) []*Technology {
+	switch e := element.(type) {
+	case *Service:
+		return e.Technologies
+	case *Form:
+		return e.ImplementedBy.Technologies
+	case *Command:
+		return e.HandledBy.Technologies
+	case *Database:
+		return appendUnique(e.Technologies, e.ApiTechnologies)
+	case *View:
+		return appendUnique(e.On.Technologies, e.On.ApiTechnologies)
+	case *DataStore:
+		return appendUnique(e.Technologies, e.ApiTechnologies)
+	case *Relationship:
+		return e.Technologies
+	default:
+		return []*Technology{}
+	}
+}
+
+// containerOf returns the element that runs the given element: the service for a form or command and the database for
+// a view. Other elements are their own container.
+func containerOf(element interface{}) interface{} {
+	switch e := element.(type) {
+	case *Form:
+		return e.ImplementedBy
+	case *Command:
+		return e.HandledBy
+	case *View:
+		return e.On
+	default:
+		return element
+	}
+}
+
+func (model ArchitectureModel) findElementByRef(kind string, id string) (interface{}, bool) {
+	for _, element := range model.Elements() {
+		if kindOf(element) == kind && idOf(element) == id {
+			return element, true
+		}
+	}
+	return nil, false
+}
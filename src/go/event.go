@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"sort"
+)
+
+// Event signals that something interesting happened. Services publish events on a queue, from which other services
+// subscribe to them.
+type Event struct {
+	node        *yaml.Node
+	Id          string
+	Name        string
+	Description string
+	// Schema refers to the definition of the event's data, like a JSON Schema file.
+	Schema      string
+	QueueId     string
+	Queue       *DataStore
+	Publishers  []*Service
+	Subscribers []*Service
+	Extensions
+}
+
+func (e *Event) setNode(node *yaml.Node) {
+	e.node = node
+}
+
+func (e *Event) setId(id string) {
+	e.Id = id
+}
+
+func (e *Event) setName(name string) {
+	e.Name = name
+}
+
+func (e *Event) getDescription() string {
+	return e.Description
+}
+
+func (e *Event) setDescription(description string) {
+	e.Description = description
+}
+
+var eventFields = []string{"name", "description", "schema", "queue"}
+
+func (e *Event) read(id string, node *yaml.Node) []Issue {
+	var fields map[string]*yaml.Node
+	fields, issues := namedObject(node, id, e)
+	issues = append(issues, setDescription(fields, e)...)
+	schema, found, issue := stringFieldOf(fields, "schema")
+	if issue != nil {
+		issues = append(issues, *issue)
+	} else if found {
+		e.Schema = schema
+	}
+	queue, found, issue := stringFieldOf(fields, "queue")
+	if issue != nil {
+		issues = append(issues, *issue)
+	} else if found {
+		e.QueueId = queue
+	} else {
+		issues = append(issues, *NodeError("Missing required field queue", node))
+	}
+	issues = append(issues, checkExtensibleFields(fields, eventFields)...)
+	return issues
+}
+
+type EventReader struct {
+}
+
+func (r EventReader) read(node *yaml.Node, _ string, model *ArchitectureModel) []Issue {
+	if node == nil {
+		return []Issue{}
+	}
+	eventsById, issue := toMap(node)
+	if issue != nil {
+		return []Issue{*issue}
+	}
+	issues := make([]Issue, 0)
+	events := make([]*Event, 0)
+	for id, eventNode := range eventsById {
+		event := Event{}
+		events = append(events, &event)
+		issues = append(issues, event.read(id, eventNode)...)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Name < events[j].Name
+	})
+	model.Events = events
+	return issues
+}
+
+func (model ArchitectureModel) findEventById(id string) (*Event, bool) {
+	for _, candidate := range model.Events {
+		if candidate.Id == id {
+			return candidate, true
+		}
+	}
+	return nil, false
+}
+
+// EventConnector connects events to their queues, and services to the events they publish and subscribe to. A
+// service can only publish an event if it sends to the event's queue, and only subscribe to it if it receives from
+// that queue.
+type EventConnector struct {
+}
+
+func (c EventConnector) connect(model *ArchitectureModel) []Issue {
+	issues := make([]Issue, 0)
+	for _, event := range model.Events {
+		queue, found := model.findQueueById(event.QueueId)
+		if found {
+			event.Queue = queue
+		} else {
+			issues = append(issues, *NodeError(fmt.Sprintf("Unknown queue '%v'", event.QueueId), event.node))
+		}
+	}
+	for _, service := range model.Services {
+		published, publishIssues := c.connectEvents(service, service.PublishedEventIds, Receive, "publishes",
+			"send to", model)
+		service.PublishedEvents = published
+		for _, event := range published {
+			event.Publishers = append(event.Publishers, service)
+		}
+		issues = append(issues, publishIssues...)
+		subscribed, subscribeIssues := c.connectEvents(service, service.SubscribedEventIds, Send, "subscribes to",
+			"receive from", model)
+		service.SubscribedEvents = subscribed
+		for _, event := range subscribed {
+			event.Subscribers = append(event.Subscribers, service)
+		}
+		issues = append(issues, subscribeIssues...)
+	}
+	return issues
+}
+
+// connectEvents finds the events with the given IDs, which the service must exchange with their queues using any
+// data flow other than the excluded one.
+func (c EventConnector) connectEvents(service *Service, ids []string, excluded DataFlow, verb string, flow string,
+	model *ArchitectureModel) ([]*Event, []Issue) {
+	events := make([]*Event, 0)
+	issues := make([]Issue, 0)
+	for _, id := range ids {
+		event, found := model.findEventById(id)
+		if !found {
+			issues = append(issues, *NodeError(fmt.Sprintf("Unknown event '%v'", id), service.node))
+			continue
+		}
+		events = append(events, event)
+		if event.Queue != nil && !c.usesQueue(service, event.Queue, excluded) {
+			issues = append(issues, *NodeError(fmt.Sprintf("Service '%v' %v event '%v', but doesn't %v queue '%v'",
+				service.Id, verb, event.Id, flow, event.Queue.Id), service.node))
+		}
+	}
+	return events, issues
+}
+
+func (c EventConnector) usesQueue(service *Service, queue *DataStore, excluded DataFlow) bool {
+	for _, use := range service.DataStores {
+		if use.Queue == queue && use.DataFlow != excluded {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Service) publishes(event *Event) bool {
+	for _, candidate := range s.PublishedEvents {
+		if candidate == event {
+			return true
+		}
+	}
+	return false
+}
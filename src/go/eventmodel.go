@@ -28,6 +28,113 @@ func (e eventModelExporter) printWorkflow(workflow *Workflow, model *Architectur
 	printer.PrintLn("}")
 }
 
+const (
+	commandColor = "#a4c1f4"
+	viewColor    = "#b6d7a8"
+	eventColor   = "#f6b26b"
+)
+
+// eventModelLane is a row of an event model. Personas and external systems have lanes at the top, commands and views
+// share the lane in the middle, and events are at the bottom, in the lanes of the queues they travel on.
+type eventModelLane struct {
+	id    string
+	name  string
+	steps []eventModelStep
+}
+
+type eventModelStep struct {
+	id    string
+	label string
+	color string
+}
+
 func (e eventModelExporter) printLanes(workflow *Workflow, model *ArchitectureModel, printer *Printer) {
-	// TODO: Implement
+	actors := make([]*eventModelLane, 0)
+	commands := &eventModelLane{id: "commands", name: "Commands and views"}
+	events := make([]*eventModelLane, 0)
+	ids := make([]string, 0)
+	for index, step := range workflow.Steps {
+		item := eventModelStep{id: fmt.Sprintf("step%v", index+1)}
+		var lane *eventModelLane
+		switch {
+		case step.Form != nil:
+			item.label = step.Form.Name
+			actors, lane = e.laneOf(actors, step.Performer)
+		case step.View != "":
+			item.label = step.View
+			if _, ok := step.Performer.(*Persona); ok {
+				actors, lane = e.laneOf(actors, step.Performer)
+			} else {
+				item.color = viewColor
+				lane = commands
+			}
+		case step.CommandId != "":
+			item.label = step.CommandId
+			if step.Command != nil {
+				item.label = fmt.Sprintf("%v (%v)", step.Command.Name, step.Command.HandledBy.Name)
+			}
+			item.color = commandColor
+			lane = commands
+		case step.EventId != "":
+			item.color = eventColor
+			if step.Event == nil {
+				item.label = step.EventId
+				events, lane = e.laneOf(events, step.Performer)
+			} else {
+				item.label = step.Event.Name
+				events, lane = e.laneOf(events, step.Event.Queue)
+			}
+		case step.Service != nil:
+			item.label = step.Service.Name
+			actors, lane = e.laneOf(actors, step.Service)
+		case step.ExternalSystem != nil:
+			item.label = step.ExternalSystem.Name
+			actors, lane = e.laneOf(actors, step.ExternalSystem)
+		default:
+			continue
+		}
+		lane.steps = append(lane.steps, item)
+		ids = append(ids, lane.id+"."+item.id)
+	}
+	lanes := actors
+	if len(commands.steps) > 0 {
+		lanes = append(lanes, commands)
+	}
+	for _, lane := range append(lanes, events...) {
+		e.printLane(lane, printer)
+	}
+	for index := 1; index < len(ids); index++ {
+		printer.PrintLn(ids[index-1], " -> ", ids[index])
+	}
+}
+
+// laneOf returns the lane for an element, which is added to the lanes if it isn't there yet.
+func (e eventModelExporter) laneOf(lanes []*eventModelLane, element interface{}) ([]*eventModelLane, *eventModelLane) {
+	element = containerOf(element)
+	id := nodeIdOf(element)
+	for _, lane := range lanes {
+		if lane.id == id {
+			return lanes, lane
+		}
+	}
+	lane := &eventModelLane{id: id, name: nameOf(element)}
+	return append(lanes, lane), lane
+}
+
+func (e eventModelExporter) printLane(lane *eventModelLane, printer *Printer) {
+	printer.PrintLn(lane.id, ": ", lane.name, " {")
+	printer.Start()
+	for _, step := range lane.steps {
+		if step.color == "" {
+			printer.PrintLn(step.id, ": ", step.label)
+			continue
+		}
+		printer.PrintLn(step.id, ": ", step.label, " {")
+		printer.Start()
+		printer.PrintLn("style.fill: \"", step.color, "\"")
+		printer.End()
+		printer.PrintLn("}")
+	}
+	printer.End()
+	printer.PrintLn("}")
 }
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestEventModel(t *testing.T) {
+	model, issues := LintText(`personas:
+  guest:
+    uses:
+      - form: registration
+
+services:
+  gateway:
+    forms:
+      - registration
+    calls:
+      - service: auth
+  auth:
+    commands:
+      - register
+    publishes:
+      - registered
+    dataStores:
+      - queue: events
+        dataFlow: send
+      - database: guests
+        dataFlow: send
+
+databases:
+  guests:
+    views:
+      - guestList
+
+queues:
+  events:
+    name: Domain events
+
+events:
+  registered:
+    queue: events
+
+workflows:
+  register:
+    name: Register
+    steps:
+      - performer: guest
+        form: registration
+      - performer: registration
+        command: register
+      - performer: auth
+        event: registered
+      - performer: auth
+        view: guestList
+`)
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+	printer := NewPrinter()
+
+	err := NewEventModelExporter("register").export(*model, printer)
+
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	expected := `direction: right
+Register: {
+    guest: Guest {
+        step1: registration
+    }
+    commands: Commands and views {
+        step2: register (Auth) {
+            style.fill: "#a4c1f4"
+        }
+        step4: guestList {
+            style.fill: "#b6d7a8"
+        }
+    }
+    events_q: Domain events {
+        step3: Registered {
+            style.fill: "#f6b26b"
+        }
+    }
+    guest.step1 -> commands.step2
+    commands.step2 -> events_q.step3
+    events_q.step3 -> commands.step4
+}
+`
+	if printer.String() != expected {
+		t.Errorf("Expected:\n%v\nbut got:\n%v", expected, printer.String())
+	}
+}
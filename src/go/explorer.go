@@ -0,0 +1,457 @@
+package main
+
+import (
+	"encoding/json"
+	"html"
+	"sort"
+)
+
+type explorerExporter struct {
+	layoutEngine LayoutEngine
+}
+
+// NewExplorerExporter creates an exporter that writes a single HTML file in which people can explore the model. The
+// file embeds the model and the script that draws it, so that it works offline, for instance when attached to a ticket.
+func NewExplorerExporter() TextExporter {
+	return explorerExporter{NewEvolutionaryLayoutEngine()}
+}
+
+// explorerShapeSizes are the sizes of the shapes of the elements in the explorer, in mini-grid cells. They follow the
+// ratios in the layout engine's README.
+var explorerShapeSizes = map[string]Size{
+	"persona":        {3, 4},
+	"externalSystem": {9, 3},
+	"service":        {6, 3},
+	"database":       {6, 4},
+	"queue":          {6, 3},
+}
+
+// explorerScale is the number of pixels per mini-grid cell.
+const explorerScale = 20
+
+// explorerData is what the explorer embeds: the model in its JSON representation, plus what the script needs to draw
+// and navigate it.
+type explorerData struct {
+	Model     JsonModel          `json:"model"`
+	Scale     int                `json:"scale"`
+	Nodes     []explorerNode     `json:"nodes"`
+	Edges     []explorerEdge     `json:"edges"`
+	Workflows []explorerWorkflow `json:"workflows"`
+}
+
+// explorerNode is an element in the diagram, with its position in mini-grid cells.
+type explorerNode struct {
+	Ref          string   `json:"ref"`
+	Kind         string   `json:"kind"`
+	KindName     string   `json:"kindName"`
+	Name         string   `json:"name"`
+	Description  string   `json:"description,omitempty"`
+	State        string   `json:"state,omitempty"`
+	Type         string   `json:"type,omitempty"`
+	Technologies []string `json:"technologies"`
+	Style        Style    `json:"style"`
+	X            int      `json:"x"`
+	Y            int      `json:"y"`
+	Width        int      `json:"width"`
+	Height       int      `json:"height"`
+}
+
+type explorerEdge struct {
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Kind        string `json:"kind"`
+	Description string `json:"description,omitempty"`
+}
+
+type explorerWorkflow struct {
+	Id    string         `json:"id"`
+	Name  string         `json:"name"`
+	Steps []explorerStep `json:"steps"`
+}
+
+// explorerStep is a step in a workflow, with references to the nodes that the step involves. The target is empty for
+// steps that don't interact with another element, like publishing an event.
+type explorerStep struct {
+	Performer string `json:"performer"`
+	Target    string `json:"target,omitempty"`
+	Action    string `json:"action"`
+}
+
+func (e explorerExporter) export(model ArchitectureModel, printer *Printer) error {
+	data, err := json.Marshal(e.dataOf(&model))
+	if err != nil {
+		return err
+	}
+	printer.PrintLn(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>`, html.EscapeString(model.System.Name), `</title>
+<style>`)
+	printer.Print(explorerStyleSheet)
+	printer.PrintLn(`</style>
+</head>
+<body>`)
+	printer.Print(explorerBody)
+	// json.Marshal escapes <, >, and &, so the data can't end the script element
+	printer.PrintLn("<script>")
+	printer.PrintLn("const data = ", string(data), ";")
+	printer.Print(explorerScript)
+	printer.PrintLn(`</script>
+</body>
+</html>`)
+	return nil
+}
+
+func (e explorerExporter) dataOf(model *ArchitectureModel) explorerData {
+	elements := make([]interface{}, 0)
+	diagram := &Diagram{make([]*Shape, 0), make([]*Connection, 0)}
+	shapes := make(map[interface{}]*Shape)
+	for _, element := range model.Elements() {
+		if size, found := explorerShapeSizes[kindOf(element)]; found {
+			shape := &Shape{Id: refOf(element), Text: nameOf(element), Size: size}
+			elements = append(elements, element)
+			diagram.Shapes = append(diagram.Shapes, shape)
+			shapes[element] = shape
+		}
+	}
+	edges := make([]explorerEdge, 0)
+	connected := make(map[[2]*Shape]bool)
+	for _, relationship := range model.Relationships() {
+		from := shapes[containerOf(relationship.From)]
+		to := shapes[containerOf(relationship.To)]
+		if from == nil || to == nil {
+			continue
+		}
+		edges = append(edges, explorerEdge{from.Id, to.Id, relationship.Kind(), relationship.Description})
+		if from != to && !connected[[2]*Shape{from, to}] && !connected[[2]*Shape{to, from}] {
+			connected[[2]*Shape{from, to}] = true
+			diagram.Connections = append(diagram.Connections, &Connection{Start: from, End: to})
+		}
+	}
+	layout := e.layoutEngine.layOut(diagram)
+	theme := model.theme()
+	nodes := make([]explorerNode, 0)
+	for _, element := range elements {
+		shape := shapes[element]
+		rectangle := layout.Shapes[shape]
+		node := explorerNode{
+			Ref:          shape.Id,
+			Kind:         kindOf(element),
+			KindName:     siteKinds[siteKindOf(element)].name,
+			Name:         shape.Text,
+			Description:  descriptionOf(element),
+			Technologies: make([]string, 0),
+			Style:        theme.StyleOf(element),
+			X:            rectangle.Min.X,
+			Y:            rectangle.Min.Y,
+			Width:        rectangle.Dx(),
+			Height:       rectangle.Dy(),
+		}
+		if state, found := stateOf(element); found {
+			node.State = state.Id()
+		}
+		if externalSystem, ok := element.(*ExternalSystem); ok {
+			node.Type = externalSystem.Type
+		}
+		for _, technology := range technologiesOf(element) {
+			node.Technologies = append(node.Technologies, technology.Name)
+		}
+		sort.Strings(node.Technologies)
+		nodes = append(nodes, node)
+	}
+	return explorerData{NewJsonModel(model), explorerScale, nodes, edges, e.workflowsOf(model, shapes)}
+}
+
+func (e explorerExporter) workflowsOf(model *ArchitectureModel, shapes map[interface{}]*Shape) []explorerWorkflow {
+	result := make([]explorerWorkflow, 0)
+	for _, workflow := range model.Workflows {
+		steps := make([]explorerStep, 0)
+		for _, step := range workflow.Steps {
+			performer := shapes[containerOf(step.Performer)]
+			if performer == nil {
+				continue
+			}
+			explored := explorerStep{Performer: performer.Id, Action: nameOf(step.Performer) + " " + actionOf(step)}
+			if target := shapes[targetOf(model, step)]; target != nil {
+				explored.Target = target.Id
+			}
+			steps = append(steps, explored)
+		}
+		if len(steps) > 0 {
+			result = append(result, explorerWorkflow{workflow.Id, workflow.Name, steps})
+		}
+	}
+	return result
+}
+
+const explorerStyleSheet = `body { font-family: sans-serif; margin: 0; display: flex; height: 100vh; color: #222; }
+aside { width: 20em; padding: 1em; overflow-y: auto; border-right: 1px solid #ccc; background: #f7f7f7; }
+aside h1 { font-size: 1.3em; margin-top: 0; }
+aside h2 { font-size: 1em; margin-bottom: 0.3em; }
+aside label { display: block; margin: 0.3em 0; }
+aside select { width: 100%; }
+main { flex: 1; overflow: auto; }
+#details:empty, #step:empty { display: none; }
+.node { cursor: pointer; }
+.node text { pointer-events: none; }
+.edge { stroke: #666; fill: none; marker-end: url(#arrow); }
+.step { stroke: #cc0000; stroke-width: 3; stroke-dasharray: 6 3; fill: none; marker-end: url(#arrow); }
+.dimmed { opacity: 0.2; }
+.hidden { display: none; }
+.selected rect, .selected path, .selected circle { stroke-width: 4; }
+`
+
+const explorerBody = `<aside>
+<h1 id="title"></h1>
+<h2>Filter</h2>
+<label>State <select id="state"><option value="">All</option></select></label>
+<label>Technology <select id="technology"><option value="">All</option></select></label>
+<label>Type <select id="type"><option value="">All</option></select></label>
+<h2>Workflow</h2>
+<select id="workflow"><option value="">None</option></select>
+<p><button id="previous">Previous</button> <button id="next">Next</button></p>
+<p id="step"></p>
+<div id="details"></div>
+</aside>
+<main><svg id="diagram" xmlns="http://www.w3.org/2000/svg" font-family="sans-serif" font-size="13"></svg></main>
+`
+
+const explorerScript = `const svgNamespace = 'http://www.w3.org/2000/svg';
+const nodes = new Map(data.nodes.map(node => [node.ref, node]));
+const shapes = new Map();
+const lines = [];
+let selected = null;
+let workflow = null;
+let step = -1;
+
+function create(name, attributes, parent) {
+  const result = document.createElementNS(svgNamespace, name);
+  for (const [key, value] of Object.entries(attributes)) {
+    result.setAttribute(key, value);
+  }
+  parent.appendChild(result);
+  return result;
+}
+
+function center(node) {
+  return {x: (node.x + node.width / 2) * data.scale, y: (node.y + node.height / 2) * data.scale};
+}
+
+function draw() {
+  const svg = document.getElementById('diagram');
+  let width = 0;
+  let height = 0;
+  for (const node of data.nodes) {
+    width = Math.max(width, (node.x + node.width) * data.scale);
+    height = Math.max(height, (node.y + node.height) * data.scale);
+  }
+  svg.setAttribute('width', width + data.scale);
+  svg.setAttribute('height', height + data.scale);
+  const marker = create('marker', {id: 'arrow', viewBox: '0 0 10 10', refX: 10, refY: 5, markerWidth: 8,
+    markerHeight: 8, orient: 'auto-start-reverse'}, create('defs', {}, svg));
+  create('path', {d: 'M 0 0 L 10 5 L 0 10 z'}, marker);
+  for (const edge of data.edges) {
+    const from = nodes.get(edge.from);
+    const to = nodes.get(edge.to);
+    if (from === to) {
+      continue;
+    }
+    const start = center(from);
+    const end = border(to, start);
+    const line = create('line', {class: 'edge', x1: start.x, y1: start.y, x2: end.x, y2: end.y}, svg);
+    create('title', {}, line).textContent = from.name + ' ' + edge.kind + ' ' + to.name +
+      (edge.description ? ': ' + edge.description : '');
+    lines.push({edge, line});
+  }
+  for (const node of data.nodes) {
+    const group = create('g', {class: 'node'}, svg);
+    drawShape(node, group);
+    const label = create('text', {x: center(node).x, y: center(node).y, 'text-anchor': 'middle',
+      'dominant-baseline': 'middle', fill: node.style.color || 'black'}, group);
+    label.textContent = node.name;
+    create('title', {}, group).textContent = node.kindName + ': ' + node.name;
+    group.addEventListener('click', event => {
+      event.stopPropagation();
+      select(node);
+    });
+    shapes.set(node.ref, group);
+  }
+  svg.addEventListener('click', () => select(null));
+}
+
+function drawShape(node, group) {
+  const x = node.x * data.scale;
+  const y = node.y * data.scale;
+  const width = node.width * data.scale;
+  const height = node.height * data.scale;
+  const style = {fill: node.style.background || 'white', stroke: node.style.stroke || '#333',
+    'stroke-width': node.style.strokeWidth || 1};
+  switch (node.kind) {
+    case 'persona':
+      create('circle', {cx: x + width / 2, cy: y + height / 4, r: height / 4, ...style}, group);
+      create('rect', {x, y: y + height / 2, width, height: height / 2, rx: width / 3, ...style}, group);
+      break;
+    case 'database': {
+      const rim = height / 8;
+      const arc = 'a ' + width / 2 + ' ' + rim + ' 0 0 0 ';
+      create('path', {d: 'M ' + x + ' ' + (y + rim) + ' ' + arc + width + ' 0 ' + arc + -width + ' 0 v ' +
+        (height - 2 * rim) + ' ' + arc + width + ' 0 v ' + -(height - 2 * rim), ...style}, group);
+      break;
+    }
+    case 'queue':
+      create('rect', {x, y, width, height, rx: height / 2, ...style}, group);
+      break;
+    default:
+      create('rect', {x, y, width, height, rx: node.kind === 'service' ? 8 : 0, ...style}, group);
+  }
+}
+
+// border returns the point where the line from the given point to the center of the node enters the node.
+function border(node, from) {
+  const to = center(node);
+  const dx = to.x - from.x;
+  const dy = to.y - from.y;
+  const scale = Math.max(Math.abs(dx) / (node.width * data.scale / 2), Math.abs(dy) / (node.height * data.scale / 2));
+  return scale > 1 ? {x: to.x - dx / scale, y: to.y - dy / scale} : to;
+}
+
+function visible(node) {
+  const state = document.getElementById('state').value;
+  const technology = document.getElementById('technology').value;
+  const type = document.getElementById('type').value;
+  return (!state || node.state === state) && (!technology || node.technologies.includes(technology)) &&
+    (!type || node.kind === type || node.type === type);
+}
+
+function neighborsOf(node) {
+  const result = new Set([node.ref]);
+  for (const edge of data.edges) {
+    if (edge.from === node.ref) {
+      result.add(edge.to);
+    } else if (edge.to === node.ref) {
+      result.add(edge.from);
+    }
+  }
+  return result;
+}
+
+function update() {
+  let highlighted = null;
+  if (selected) {
+    highlighted = neighborsOf(selected);
+  } else if (workflow && step >= 0) {
+    const current = workflow.steps[step];
+    highlighted = new Set([current.performer, current.target].filter(ref => ref));
+  }
+  for (const node of data.nodes) {
+    const shape = shapes.get(node.ref);
+    shape.classList.toggle('hidden', !visible(node));
+    shape.classList.toggle('dimmed', highlighted !== null && !highlighted.has(node.ref));
+    shape.classList.toggle('selected', node === selected);
+  }
+  for (const {edge, line} of lines) {
+    line.classList.toggle('hidden', !visible(nodes.get(edge.from)) || !visible(nodes.get(edge.to)));
+    line.classList.toggle('dimmed', highlighted !== null &&
+      !(selected ? edge.from === selected.ref || edge.to === selected.ref :
+        highlighted.has(edge.from) && highlighted.has(edge.to)));
+  }
+  showStep();
+  showDetails();
+}
+
+function showStep() {
+  const svg = document.getElementById('diagram');
+  const text = document.getElementById('step');
+  svg.querySelectorAll('.step').forEach(line => line.remove());
+  text.textContent = '';
+  if (!workflow || step < 0) {
+    return;
+  }
+  const current = workflow.steps[step];
+  text.textContent = 'Step ' + (step + 1) + ' of ' + workflow.steps.length + ': ' + current.action;
+  if (current.target && current.target !== current.performer) {
+    const start = center(nodes.get(current.performer));
+    const end = border(nodes.get(current.target), start);
+    create('line', {class: 'step', x1: start.x, y1: start.y, x2: end.x, y2: end.y}, svg);
+  }
+}
+
+function showDetails() {
+  const details = document.getElementById('details');
+  details.textContent = '';
+  if (!selected) {
+    return;
+  }
+  const add = (name, text) => {
+    const result = document.createElement(name);
+    result.textContent = text;
+    details.appendChild(result);
+    return result;
+  };
+  add('h2', selected.name);
+  add('p', selected.kindName + (selected.type ? ' (' + selected.type + ')' : '') +
+    (selected.state ? ', ' + selected.state : ''));
+  if (selected.description) {
+    add('p', selected.description);
+  }
+  if (selected.technologies.length > 0) {
+    add('p', 'Technologies: ' + selected.technologies.join(', '));
+  }
+  const list = add('ul', '');
+  for (const edge of data.edges) {
+    if (edge.from === selected.ref || edge.to === selected.ref) {
+      const item = document.createElement('li');
+      item.textContent = nodes.get(edge.from).name + ' ' + edge.kind + ' ' + nodes.get(edge.to).name +
+        (edge.description ? ': ' + edge.description : '');
+      list.appendChild(item);
+    }
+  }
+}
+
+function select(node) {
+  selected = node;
+  update();
+}
+
+function fill(id, values, listener) {
+  const select = document.getElementById(id);
+  for (const [value, text] of values) {
+    const option = document.createElement('option');
+    option.value = value;
+    option.textContent = text;
+    select.appendChild(option);
+  }
+  select.addEventListener('change', listener);
+}
+
+function unique(values) {
+  return [...new Set(values.filter(value => value))].sort();
+}
+
+document.getElementById('title').textContent = data.model.system.name;
+fill('state', unique(data.nodes.map(node => node.state)).map(state => [state, state]), update);
+fill('technology', unique(data.nodes.flatMap(node => node.technologies)).map(name => [name, name]), update);
+const types = unique(data.nodes.map(node => node.type)).map(type => [type, 'External system: ' + type]);
+fill('type', [...new Map(data.nodes.map(node => [node.kind, node.kindName]))].concat(types), update);
+fill('workflow', data.workflows.map(workflow => [workflow.id, workflow.name]), event => {
+  workflow = data.workflows.find(candidate => candidate.id === event.target.value) || null;
+  step = workflow ? 0 : -1;
+  selected = null;
+  update();
+});
+document.getElementById('previous').addEventListener('click', () => {
+  if (workflow && step > 0) {
+    step--;
+    update();
+  }
+});
+document.getElementById('next').addEventListener('click', () => {
+  if (workflow && step < workflow.steps.length - 1) {
+    step++;
+    update();
+  }
+});
+draw();
+update();
+`
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestExplorer(t *testing.T) {
+	model, issues := LintText(queryDefinition)
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+	printer := NewPrinter()
+
+	err := NewExplorerExporter().export(*model, printer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text := printer.String()
+	for _, remote := range []string{`src="`, `href="`, "@import", "url(http"} {
+		if strings.Contains(text, remote) {
+			t.Errorf("Explorer isn't self-contained, since it contains %v:\n%v", remote, text)
+		}
+	}
+	if strings.Count(text, "</script>") != 1 {
+		t.Errorf("Explorer should have exactly one script:\n%v", text)
+	}
+	start := strings.Index(text, "const data = ")
+	end := strings.Index(text[start:], ";\n")
+	if start < 0 || end < 0 {
+		t.Fatalf("Missing data:\n%v", text)
+	}
+	var data explorerData
+	err = json.Unmarshal([]byte(text[start+len("const data = "):start+end]), &data)
+	if err != nil {
+		t.Fatalf("Invalid data: %v", err)
+	}
+	if len(data.Model.Services) != 3 {
+		t.Errorf("Expected the model, but got %+v", data.Model)
+	}
+	refs := make([]string, 0)
+	for _, node := range data.Nodes {
+		refs = append(refs, node.Ref)
+		if node.Width == 0 || node.Height == 0 {
+			t.Errorf("Node %v isn't laid out: %+v", node.Ref, node)
+		}
+		for _, other := range data.Nodes {
+			if other.Ref != node.Ref && node.X < other.X+other.Width && other.X < node.X+node.Width &&
+				node.Y < other.Y+other.Height && other.Y < node.Y+node.Height {
+				t.Errorf("Node %v overlaps %v", node.Ref, other.Ref)
+			}
+		}
+	}
+	expectedRefs := "persona:cs persona:dev service:api service:console service:reporting database:subscriptions"
+	if strings.Join(refs, " ") != expectedRefs {
+		t.Errorf("Expected nodes %v, but got %v", expectedRefs, refs)
+	}
+	if len(data.Edges) != 5 || data.Edges[0] != (explorerEdge{"persona:cs", "database:subscriptions", "uses", ""}) {
+		t.Errorf("Unexpected edges: %+v", data.Edges)
+	}
+	if len(data.Workflows) != 1 || data.Workflows[0].Steps[0] !=
+		(explorerStep{"persona:dev", "service:console", "Dev uses form subscriptions"}) {
+		t.Errorf("Unexpected workflows: %+v", data.Workflows)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"gopkg.in/yaml.v3"
+	"sort"
+	"strings"
+)
+
+// Extensions hold custom data about an element that the model doesn't define: tags to classify the element, and
+// properties with arbitrary values.
+type Extensions struct {
+	Tags       []string
+	Properties map[string]string
+}
+
+func (e *Extensions) getExtensions() *Extensions {
+	return e
+}
+
+// Extensible is implemented by everything that embeds Extensions.
+type Extensible interface {
+	getExtensions() *Extensions
+}
+
+var extensionFields = []string{"tags", "properties"}
+
+func setExtensions(fields map[string]*yaml.Node, e Extensible) []Issue {
+	extensions := e.getExtensions()
+	issues := readStrings(fields, "tags", nil, &extensions.Tags)
+	propertyNodes, _, issue := mapFieldOf(fields, "properties")
+	if issue != nil {
+		return append(issues, *issue)
+	}
+	extensions.Properties = make(map[string]string)
+	for key, valueNode := range propertyNodes {
+		value, issue := toString(valueNode, key)
+		if issue != nil {
+			issues = append(issues, *issue)
+		} else {
+			extensions.Properties[key] = value
+		}
+	}
+	return issues
+}
+
+// PropertyKeys returns the keys of the properties in alphabetical order.
+func (e *Extensions) PropertyKeys() []string {
+	result := make([]string, 0, len(e.Properties))
+	for key := range e.Properties {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (e *Extensions) hasAnyTag(tags []string) bool {
+	for _, tag := range e.Tags {
+		if !hasDifferentValueThan(tag, tags) {
+			return true
+		}
+	}
+	return false
+}
+
+// extensionsOf returns the extensions of an element, or nil if it can't have any.
+func extensionsOf(element interface{}) *Extensions {
+	if extensible, ok := element.(Extensible); ok {
+		return extensible.getExtensions()
+	}
+	if relationship, ok := element.(*Relationship); ok {
+		return relationship.Extensions
+	}
+	return nil
+}
+
+// checkExtensibleFields warns about fields that aren't allowed for an element that may have tags and properties, which
+// is where custom data belongs.
+func checkExtensibleFields(fields map[string]*yaml.Node, allowed []string) []Issue {
+	allowedOrExtension := append(append([]string{}, allowed...), extensionFields...)
+	return checkFieldsWithHint(fields, allowedOrExtension, "use tags or properties for custom data")
+}
+
+// dotPropertyPrefix starts the Graphviz attributes of properties, so that properties like color can't change how
+// Graphviz draws an element.
+const dotPropertyPrefix = "property."
+
+// dotAttributesOfExtensions returns Graphviz attributes for tags and properties, each prefixed with a comma.
+func dotAttributesOfExtensions(extensions *Extensions) string {
+	if extensions == nil {
+		return ""
+	}
+	result := ""
+	if len(extensions.Tags) > 0 {
+		result += ",tags=\"" + escapeQuoted(strings.Join(extensions.Tags, ",")) + "\""
+	}
+	for _, key := range extensions.PropertyKeys() {
+		result += ",\"" + dotPropertyPrefix + escapeQuoted(key) + "\"=\"" + escapeQuoted(extensions.Properties[key]) + "\""
+	}
+	return result
+}
+
+// escapeQuoted escapes text for use between double quotes in Graphviz and Structurizr, which both use backslashes.
+func escapeQuoted(text string) string {
+	return strings.NewReplacer("\\", "\\\\", "\"", "\\\"").Replace(text)
+}
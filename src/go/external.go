@@ -12,15 +12,12 @@ type ExternalSystem struct {
 	Name        string
 	Description string
 	Type        string
-	Calls       []*Call
-}
-
-func (es *ExternalSystem) Print(printer *Printer) {
-	printer.Print(es.Name)
-	if es.Type != "" {
-		printer.Print(" (", es.Type, ")")
-	}
-	printer.NewLine()
+	// SystemId is the ID of the system of another model that this external system is, in a landscape of models.
+	SystemId string
+	Calls    []*Call
+	OwnerId  string
+	Owner    *Team
+	Extensions
 }
 
 func (es *ExternalSystem) setNode(node *yaml.Node) {
@@ -35,6 +32,21 @@ func (es *ExternalSystem) setName(name string) {
 	es.Name = name
 }
 
+func (es *ExternalSystem) getNode() *yaml.Node {
+	return es.node
+}
+func (es *ExternalSystem) getOwnerId() string {
+	return es.OwnerId
+}
+
+func (es *ExternalSystem) setOwnerId(id string) {
+	es.OwnerId = id
+}
+
+func (es *ExternalSystem) setOwner(team *Team) {
+	es.Owner = team
+}
+
 func (es *ExternalSystem) getDescription() string {
 	return es.Description
 }
@@ -43,12 +55,22 @@ func (es *ExternalSystem) setDescription(description string) {
 	es.Description = description
 }
 
+var externalSystemFields = []string{"name", "description", "type", "system", "calls", "owner"}
+
 func (es *ExternalSystem) read(id string, node *yaml.Node) []Issue {
 	var fields map[string]*yaml.Node
 	fields, issues := namedObject(node, id, es)
 	issues = append(issues, setDescription(fields, es)...)
 	issues = append(issues, es.readType(fields)...)
+	systemId, found, issue := stringFieldOf(fields, "system")
+	if issue != nil {
+		issues = append(issues, *issue)
+	} else if found {
+		es.SystemId = systemId
+	}
 	issues = append(issues, es.readCalls(fields)...)
+	issues = append(issues, setOwner(fields, es)...)
+	issues = append(issues, checkExtensibleFields(fields, externalSystemFields)...)
 	return issues
 }
 
@@ -114,6 +136,7 @@ func (c ExternalSystemConnector) connect(model *ArchitectureModel) []Issue {
 	for _, externalSystem := range model.ExternalSystems {
 		for _, call := range externalSystem.Calls {
 			issues = append(issues, c.connectCall(call, model)...)
+			issues = append(issues, connectInterface(call)...)
 			issues = append(issues, connectTechnologies(call, model)...)
 		}
 	}
@@ -0,0 +1,546 @@
+package main
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"sort"
+	"strings"
+)
+
+// ViewFilter selects the part of a model that an exporter shows. Empty fields don't filter anything.
+type ViewFilter struct {
+	// Focus is a reference to an element, like service:api. When set, only elements within Hops relationships of the
+	// focus are kept.
+	Focus        string
+	Hops         int
+	IncludeKinds []string
+	ExcludeKinds []string
+	// Types filters external systems on their type.
+	Types []string
+	// States filters elements that have a state.
+	States []string
+	// Technologies filters elements that are implemented with technologies on their technology IDs.
+	Technologies []string
+	// Tags filters elements that have at least one of the tags.
+	Tags []string
+}
+
+var filterableKinds = []string{"persona", "externalSystem", "service", "database", "queue"}
+
+const defaultHops = 1
+
+func (f ViewFilter) IsEmpty() bool {
+	return f.Focus == "" && len(f.IncludeKinds) == 0 && len(f.ExcludeKinds) == 0 && len(f.Types) == 0 &&
+		len(f.States) == 0 && len(f.Technologies) == 0 && len(f.Tags) == 0
+}
+
+// Apply returns a slice of the model that contains only the elements that pass the filter, and only the
+// relationships between those elements. The elements of the original model aren't changed.
+func (f ViewFilter) Apply(model ArchitectureModel) (ArchitectureModel, error) {
+	if f.IsEmpty() {
+		return model, nil
+	}
+	kept := make(map[interface{}]bool)
+	for _, element := range model.Elements() {
+		if containerOf(element) == element && f.accepts(element) {
+			kept[element] = true
+		}
+	}
+	if f.Focus != "" {
+		focus, err := model.findElement(f.Focus)
+		if err != nil {
+			return model, err
+		}
+		kept = f.near(containerOf(focus), kept, &model)
+	}
+	return sliceOf(model, kept), nil
+}
+
+// Validate returns an error for a kind or state that doesn't exist, since filtering on it would leave nothing.
+func (f ViewFilter) Validate() error {
+	for _, field := range []struct {
+		name    string
+		values  []string
+		allowed []string
+	}{
+		{"include", f.IncludeKinds, filterableKinds},
+		{"exclude", f.ExcludeKinds, filterableKinds},
+		{"state", f.States, allowedStates},
+	} {
+		for _, value := range field.values {
+			if hasDifferentValueThan(value, field.allowed) {
+				return fmt.Errorf("invalid %v '%v': must be one of %v", field.name, value, stringsIn(field.allowed))
+			}
+		}
+	}
+	return nil
+}
+
+func (f ViewFilter) accepts(element interface{}) bool {
+	kind := kindOf(element)
+	if hasDifferentValueThan(kind, filterableKinds) {
+		return true
+	}
+	if len(f.IncludeKinds) > 0 && hasDifferentValueThan(kind, f.IncludeKinds) {
+		return false
+	}
+	if !hasDifferentValueThan(kind, f.ExcludeKinds) {
+		return false
+	}
+	if externalSystem, ok := element.(*ExternalSystem); ok && len(f.Types) > 0 &&
+		hasDifferentValueThan(externalSystem.Type, f.Types) {
+		return false
+	}
+	if state, found := stateOf(element); found && len(f.States) > 0 && hasDifferentValueThan(state.Id(), f.States) {
+		return false
+	}
+	if len(f.Tags) > 0 && !extensionsOf(element).hasAnyTag(f.Tags) {
+		return false
+	}
+	if _, ok := element.(*Service); ok && len(f.Technologies) > 0 {
+		return f.usesAnyTechnology(element)
+	}
+	if _, ok := element.(*Database); ok && len(f.Technologies) > 0 {
+		return f.usesAnyTechnology(element)
+	}
+	if _, ok := element.(*DataStore); ok && len(f.Technologies) > 0 {
+		return f.usesAnyTechnology(element)
+	}
+	return true
+}
+
+func (f ViewFilter) usesAnyTechnology(element interface{}) bool {
+	for _, technology := range technologiesOf(element) {
+		if !hasDifferentValueThan(technology.Id, f.Technologies) {
+			return true
+		}
+	}
+	return false
+}
+
+// near returns the kept elements that can be reached from the focus within the given number of hops, following
+// relationships in either direction.
+func (f ViewFilter) near(focus interface{}, kept map[interface{}]bool, model *ArchitectureModel) map[interface{}]bool {
+	relationships := model.Relationships()
+	result := map[interface{}]bool{focus: true}
+	current := []interface{}{focus}
+	for hop := 0; hop < f.Hops; hop++ {
+		next := make([]interface{}, 0)
+		for _, relationship := range relationships {
+			from := relationship.From
+			to := containerOf(relationship.To)
+			for _, pair := range [][]interface{}{{from, to}, {to, from}} {
+				if isOneOf(pair[0], current) && kept[pair[1]] && !result[pair[1]] {
+					result[pair[1]] = true
+					next = append(next, pair[1])
+				}
+			}
+		}
+		current = next
+	}
+	return result
+}
+
+func isOneOf(element interface{}, candidates []interface{}) bool {
+	for _, candidate := range candidates {
+		if candidate == element {
+			return true
+		}
+	}
+	return false
+}
+
+func sliceOf(model ArchitectureModel, kept map[interface{}]bool) ArchitectureModel {
+	result := model
+	result.Personas = make([]*Persona, 0)
+	for _, persona := range model.Personas {
+		if kept[persona] {
+			slice := *persona
+			slice.Uses = make([]*Used, 0)
+			for _, used := range persona.Uses {
+				if kept[usedContainerOf(used)] {
+					slice.Uses = append(slice.Uses, used)
+				}
+			}
+			result.Personas = append(result.Personas, &slice)
+		}
+	}
+	result.ExternalSystems = make([]*ExternalSystem, 0)
+	for _, externalSystem := range model.ExternalSystems {
+		if kept[externalSystem] {
+			slice := *externalSystem
+			slice.Calls = keptCalls(externalSystem.Calls, kept)
+			result.ExternalSystems = append(result.ExternalSystems, &slice)
+		}
+	}
+	result.Services = make([]*Service, 0)
+	for _, service := range model.Services {
+		if kept[service] {
+			slice := *service
+			slice.Calls = keptCalls(service.Calls, kept)
+			slice.DataStores = make([]*DataStoreUse, 0)
+			for _, use := range service.DataStores {
+				if (use.Database != nil && kept[use.Database]) || (use.Queue != nil && kept[use.Queue]) {
+					slice.DataStores = append(slice.DataStores, use)
+				}
+			}
+			result.Services = append(result.Services, &slice)
+		}
+	}
+	result.Databases = make([]*Database, 0)
+	for _, database := range model.Databases {
+		if kept[database] {
+			result.Databases = append(result.Databases, database)
+		}
+	}
+	result.Queues = make([]*DataStore, 0)
+	for _, queue := range model.Queues {
+		if kept[queue] {
+			result.Queues = append(result.Queues, queue)
+		}
+	}
+	result.Events = keptEvents(model.Events, kept)
+	result.Workflows = keptWorkflows(&model, result.Events, kept)
+	result.Environments = keptEnvironments(model.Environments, kept)
+	result.Teams = keptTeams(&result)
+	result.Technologies = keptTechnologies(&result)
+	return result
+}
+
+// keptEvents returns the events on kept queues, or without a queue, with only their kept publishers and subscribers.
+func keptEvents(events []*Event, kept map[interface{}]bool) []*Event {
+	result := make([]*Event, 0)
+	for _, event := range events {
+		if event.Queue != nil && !kept[event.Queue] {
+			continue
+		}
+		slice := *event
+		slice.Publishers = keptServices(event.Publishers, kept)
+		slice.Subscribers = keptServices(event.Subscribers, kept)
+		result = append(result, &slice)
+	}
+	return result
+}
+
+func keptServices(services []*Service, kept map[interface{}]bool) []*Service {
+	result := make([]*Service, 0)
+	for _, service := range services {
+		if kept[service] {
+			result = append(result, service)
+		}
+	}
+	return result
+}
+
+// keptWorkflows returns the workflows with only the steps whose elements are all kept. Workflows without steps left are
+// dropped.
+func keptWorkflows(model *ArchitectureModel, events []*Event, kept map[interface{}]bool) []*Workflow {
+	keptEvent := make(map[string]bool)
+	for _, event := range events {
+		keptEvent[event.Id] = true
+	}
+	result := make([]*Workflow, 0)
+	for _, workflow := range model.Workflows {
+		slice := *workflow
+		slice.Steps = make([]*Step, 0)
+		for _, step := range workflow.Steps {
+			if keptStep(model, step, kept) && (step.Event == nil || keptEvent[step.Event.Id]) {
+				slice.Steps = append(slice.Steps, step)
+			}
+		}
+		if len(slice.Steps) > 0 || len(workflow.Steps) == 0 {
+			result = append(result, &slice)
+		}
+	}
+	return result
+}
+
+// keptStep returns whether the containers of the elements that a step refers to are all kept.
+func keptStep(model *ArchitectureModel, step *Step, kept map[interface{}]bool) bool {
+	elements := make([]interface{}, 0)
+	if step.Performer != nil {
+		elements = append(elements, step.Performer)
+	}
+	if step.Form != nil {
+		elements = append(elements, step.Form)
+	}
+	if step.Command != nil {
+		elements = append(elements, step.Command)
+	}
+	if step.Service != nil {
+		elements = append(elements, step.Service)
+	}
+	if step.ExternalSystem != nil {
+		elements = append(elements, step.ExternalSystem)
+	}
+	if step.View != "" {
+		for _, database := range model.Databases {
+			if database.hasView(step.View) {
+				elements = append(elements, database)
+			}
+		}
+	}
+	for _, element := range elements {
+		if !kept[containerOf(element)] {
+			return false
+		}
+	}
+	return true
+}
+
+// keptEnvironments returns the environments with only the instances of kept elements. Deployment nodes that are left
+// without instances or nested deployment nodes are dropped.
+func keptEnvironments(environments []*Environment, kept map[interface{}]bool) []*Environment {
+	result := make([]*Environment, 0)
+	for _, environment := range environments {
+		slice := *environment
+		slice.DeploymentNodes = keptDeploymentNodes(environment.DeploymentNodes, kept)
+		result = append(result, &slice)
+	}
+	return result
+}
+
+func keptDeploymentNodes(deploymentNodes []*DeploymentNode, kept map[interface{}]bool) []*DeploymentNode {
+	result := make([]*DeploymentNode, 0)
+	for _, deploymentNode := range deploymentNodes {
+		slice := *deploymentNode
+		slice.DeploymentNodes = keptDeploymentNodes(deploymentNode.DeploymentNodes, kept)
+		slice.Instances = make([]*Instance, 0)
+		for _, instance := range deploymentNode.Instances {
+			if kept[instance.Element()] {
+				slice.Instances = append(slice.Instances, instance)
+			}
+		}
+		empty := len(slice.DeploymentNodes) == 0 && len(slice.Instances) == 0
+		if !empty || (len(deploymentNode.DeploymentNodes) == 0 && len(deploymentNode.Instances) == 0) {
+			result = append(result, &slice)
+		}
+	}
+	return result
+}
+
+// keptTeams returns the teams that own an element of the slice.
+func keptTeams(slice *ArchitectureModel) []*Team {
+	owners := make(map[*Team]bool)
+	for _, element := range slice.Elements() {
+		if owner := ownerOf(element); owner != nil {
+			owners[owner] = true
+		}
+	}
+	result := make([]*Team, 0)
+	for _, team := range slice.Teams {
+		if owners[team] {
+			result = append(result, team)
+		}
+	}
+	return result
+}
+
+// keptTechnologies returns the technologies that the elements, relationships, and deployment nodes of the slice use.
+func keptTechnologies(slice *ArchitectureModel) []*Technology {
+	used := make(map[*Technology]bool)
+	for _, element := range slice.Elements() {
+		for _, technology := range technologiesOf(element) {
+			used[technology] = true
+		}
+	}
+	for _, relationship := range slice.Relationships() {
+		for _, technology := range relationship.Technologies {
+			used[technology] = true
+		}
+	}
+	for _, environment := range slice.Environments {
+		addDeploymentNodeTechnologies(environment.DeploymentNodes, used)
+	}
+	result := make([]*Technology, 0)
+	for _, technology := range slice.Technologies {
+		if used[technology] {
+			result = append(result, technology)
+		}
+	}
+	return result
+}
+
+func addDeploymentNodeTechnologies(deploymentNodes []*DeploymentNode, used map[*Technology]bool) {
+	for _, deploymentNode := range deploymentNodes {
+		for _, technology := range deploymentNode.Technologies {
+			used[technology] = true
+		}
+		addDeploymentNodeTechnologies(deploymentNode.DeploymentNodes, used)
+	}
+}
+
+func usedContainerOf(used *Used) interface{} {
+	if used.ExternalSystem != nil {
+		return used.ExternalSystem
+	}
+	if used.Form != nil {
+		return used.Form.ImplementedBy
+	}
+	if used.View != nil {
+		return used.View.On
+	}
+	return nil
+}
+
+func keptCalls(calls []*Call, kept map[interface{}]bool) []*Call {
+	result := make([]*Call, 0)
+	for _, call := range calls {
+		if kept[call.Callee()] {
+			result = append(result, call)
+		}
+	}
+	return result
+}
+
+// SplitList splits a comma-separated command line value.
+func SplitList(value string) []string {
+	result := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// DiagramView is a named filter on the model, for which exporters that support multiple views, like c4, create a view.
+type DiagramView struct {
+	node        *yaml.Node
+	Id          string
+	Name        string
+	Description string
+	Filter      ViewFilter
+}
+
+func (v *DiagramView) setNode(node *yaml.Node) {
+	v.node = node
+}
+
+func (v *DiagramView) setId(id string) {
+	v.Id = id
+}
+
+func (v *DiagramView) setName(name string) {
+	v.Name = name
+}
+
+func (v *DiagramView) getDescription() string {
+	return v.Description
+}
+
+func (v *DiagramView) setDescription(description string) {
+	v.Description = description
+}
+
+var diagramViewFields = []string{"name", "description", "focus", "hops", "include", "exclude", "types", "states",
+	"technologies", "tags"}
+
+func (v *DiagramView) read(id string, node *yaml.Node) []Issue {
+	var fields map[string]*yaml.Node
+	fields, issues := namedObject(node, id, v)
+	issues = append(issues, setDescription(fields, v)...)
+	focus, found, issue := stringFieldOf(fields, "focus")
+	if issue != nil {
+		issues = append(issues, *issue)
+	} else if found {
+		v.Filter.Focus = focus
+		v.Filter.Hops = defaultHops
+	}
+	hopsNode, found := fields["hops"]
+	if found {
+		hops, issue := toNumber(hopsNode, "hops")
+		if issue != nil {
+			issues = append(issues, *issue)
+		} else if hops < 0 || hops != float64(int(hops)) {
+			issues = append(issues, *NodeError("hops must be a whole number that isn't negative", hopsNode))
+		} else {
+			v.Filter.Hops = int(hops)
+		}
+	}
+	issues = append(issues, readStrings(fields, "include", filterableKinds, &v.Filter.IncludeKinds)...)
+	issues = append(issues, readStrings(fields, "exclude", filterableKinds, &v.Filter.ExcludeKinds)...)
+	issues = append(issues, readStrings(fields, "types", nil, &v.Filter.Types)...)
+	issues = append(issues, readStrings(fields, "states", allowedStates, &v.Filter.States)...)
+	issues = append(issues, readStrings(fields, "technologies", nil, &v.Filter.Technologies)...)
+	issues = append(issues, readStrings(fields, "tags", nil, &v.Filter.Tags)...)
+	issues = append(issues, checkFields(fields, diagramViewFields)...)
+	return issues
+}
+
+// readStrings reads a sequence of strings, which must all be allowed values, unless allowed is nil.
+func readStrings(fields map[string]*yaml.Node, field string, allowed []string, target *[]string) []Issue {
+	nodes, _, issue := sequenceFieldOf(fields, field)
+	if issue != nil {
+		return []Issue{*issue}
+	}
+	issues := make([]Issue, 0)
+	values := make([]string, 0)
+	for _, node := range nodes {
+		value, issue := toString(node, field)
+		if issue != nil {
+			issues = append(issues, *issue)
+		} else if allowed != nil && hasDifferentValueThan(value, allowed) {
+			issues = append(issues, *NodeError(fmt.Sprintf("Invalid %v: must be one of %v", field, stringsIn(allowed)), node))
+		} else {
+			values = append(values, value)
+		}
+	}
+	*target = values
+	return issues
+}
+
+type DiagramViewReader struct {
+}
+
+func (r DiagramViewReader) read(node *yaml.Node, _ string, model *ArchitectureModel) []Issue {
+	if node == nil {
+		return []Issue{}
+	}
+	viewsById, issue := toMap(node)
+	if issue != nil {
+		return []Issue{*issue}
+	}
+	issues := make([]Issue, 0)
+	views := make([]*DiagramView, 0)
+	for id, viewNode := range viewsById {
+		view := DiagramView{}
+		views = append(views, &view)
+		issues = append(issues, view.read(id, viewNode)...)
+	}
+	sort.Slice(views, func(i, j int) bool {
+		return views[i].Id < views[j].Id
+	})
+	model.DiagramViews = views
+	return issues
+}
+
+type DiagramViewConnector struct {
+}
+
+func (c DiagramViewConnector) connect(model *ArchitectureModel) []Issue {
+	issues := make([]Issue, 0)
+	for _, view := range model.DiagramViews {
+		if view.Filter.Focus != "" {
+			if _, err := model.findElement(view.Filter.Focus); err != nil {
+				issues = append(issues, *NodeError(fmt.Sprintf("Invalid focus: %v", err), view.node))
+			}
+		}
+		for _, technology := range view.Filter.Technologies {
+			if _, found := lookUpTechnology(model, technology); !found {
+				issues = append(issues, *NodeError(fmt.Sprintf("Unknown technology '%v'", technology), view.node))
+			}
+		}
+	}
+	return issues
+}
+
+func (model ArchitectureModel) findDiagramViewById(id string) (*DiagramView, bool) {
+	for _, candidate := range model.DiagramViews {
+		if candidate.Id == id {
+			return candidate, true
+		}
+	}
+	return nil, false
+}
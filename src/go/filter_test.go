@@ -0,0 +1,239 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const filterDefinition = `personas:
+  user:
+    uses:
+      - externalSystem: web
+externalSystems:
+  web:
+    type: frontend
+    calls:
+      - service: api
+  mail:
+    type: email
+    tags:
+      - smtp
+services:
+  api:
+    technologies:
+      - java
+    calls:
+      - service: billing
+      - externalSystem: mail
+    dataStores:
+      - database: customers
+  billing:
+    state: deprecated
+    technologies:
+      - cobol
+databases:
+  customers:
+    technologies:
+      - postgres
+technologies:
+  java:
+    quadrant: languagesAndFrameworks
+  cobol:
+    quadrant: languagesAndFrameworks
+  postgres:
+    quadrant: platforms
+views:
+  backend:
+    name: Backend
+    include:
+      - service
+      - database
+`
+
+func TestFilter(t *testing.T) {
+	model, issues := LintText(filterDefinition)
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+
+	for _, test := range []struct {
+		name     string
+		filter   ViewFilter
+		expected []string
+	}{
+		{"empty", ViewFilter{}, []string{"persona:user", "externalSystem:mail", "externalSystem:web", "service:api",
+			"service:billing", "database:customers"}},
+		{"include", ViewFilter{IncludeKinds: []string{"service"}}, []string{"service:api", "service:billing"}},
+		{"exclude", ViewFilter{ExcludeKinds: []string{"persona", "externalSystem"}}, []string{"service:api",
+			"service:billing", "database:customers"}},
+		{"types", ViewFilter{Types: []string{"email"}}, []string{"persona:user", "externalSystem:mail", "service:api",
+			"service:billing", "database:customers"}},
+		{"states", ViewFilter{States: []string{"deprecated"}}, []string{"persona:user", "externalSystem:mail",
+			"externalSystem:web", "service:billing"}},
+		{"technologies", ViewFilter{Technologies: []string{"cobol"}}, []string{"persona:user", "externalSystem:mail",
+			"externalSystem:web", "service:billing"}},
+		{"tags", ViewFilter{Tags: []string{"smtp"}}, []string{"externalSystem:mail"}},
+		{"focus", ViewFilter{Focus: "web", Hops: 1}, []string{"persona:user", "externalSystem:web", "service:api"}},
+		{"hops", ViewFilter{Focus: "web", Hops: 2}, []string{"persona:user", "externalSystem:mail", "externalSystem:web",
+			"service:api", "service:billing", "database:customers"}},
+	} {
+		slice, err := test.filter.Apply(*model)
+		if err != nil {
+			t.Fatalf("%v: %v", test.name, err)
+		}
+		actual := make([]string, 0)
+		for _, element := range slice.Elements() {
+			if containerOf(element) == element && !hasDifferentValueThan(kindOf(element), filterableKinds) {
+				actual = append(actual, refOf(element))
+			}
+		}
+		if !equalStrings(actual, test.expected) {
+			t.Errorf("%v: expected %v, but got %v", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestFilterKeepsOnlyRelationshipsBetweenKeptElements(t *testing.T) {
+	model, _ := LintText(filterDefinition)
+
+	slice, _ := ViewFilter{IncludeKinds: []string{"service"}}.Apply(*model)
+
+	api := slice.Services[0]
+	if len(api.Calls) != 1 || api.Calls[0].ServiceId != "billing" || len(api.DataStores) != 0 {
+		t.Errorf("Invalid relationships: %+v, %+v", api.Calls, api.DataStores)
+	}
+	if len(model.Services[0].Calls) != 2 {
+		t.Errorf("Original model changed")
+	}
+}
+
+func TestC4ExportOfDiagramViews(t *testing.T) {
+	model, _ := LintText(filterDefinition)
+	printer := NewPrinter()
+
+	err := NewC4Exporter().export(*model, printer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `        container system "backend" {
+            title "Backend"
+            include api billing customers_db
+            autolayout
+        }
+`
+	if !strings.Contains(printer.String(), expected) {
+		t.Errorf("Missing view, got:\n%v", printer.String())
+	}
+}
+
+func TestC4ExportOfInvalidDiagramView(t *testing.T) {
+	model, _ := LintText(filterDefinition)
+	model.DiagramViews[0].Filter.Focus = "unknown"
+
+	err := NewC4Exporter().export(*model, NewPrinter())
+
+	if err == nil || !strings.Contains(err.Error(), "backend") {
+		t.Errorf("Expected error for invalid view, but got %v", err)
+	}
+}
+
+func TestFilterDropsReferencesToRemovedElements(t *testing.T) {
+	model, issues := LintText(eventsDefinition + `    name: Register
+  notify:
+    steps:
+      - performer: mailer
+        service: auth
+teams:
+  identity:
+    name: Identity
+  messaging:
+    name: Messaging
+technologies:
+  go:
+    quadrant: languagesAndFrameworks
+  kafka:
+    quadrant: platforms
+`)
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+	model.Services[0].Owner = model.Teams[0]
+	model.Services[0].Technologies = []*Technology{model.Technologies[0]}
+	model.Queues[0].Owner = model.Teams[1]
+	model.Queues[0].Technologies = []*Technology{model.Technologies[1]}
+
+	slice, err := ViewFilter{IncludeKinds: []string{"service"}}.Apply(*model)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(slice.Events) != 0 {
+		t.Errorf("Event on removed queue is kept: %+v", slice.Events)
+	}
+	if len(slice.Workflows) != 1 || slice.Workflows[0].Id != "notify" || len(slice.Workflows[0].Steps) != 1 {
+		t.Errorf("Workflow with removed event is kept: %+v", slice.Workflows)
+	}
+	if len(slice.Teams) != 1 || slice.Teams[0].Id != "identity" {
+		t.Errorf("Team without kept elements is kept: %+v", slice.Teams)
+	}
+	if len(slice.Technologies) != 1 || slice.Technologies[0].Id != "go" {
+		t.Errorf("Technology of removed element is kept: %+v", slice.Technologies)
+	}
+	if len(model.Events) != 1 || len(model.Workflows) != 2 || len(model.Teams) != 2 || len(model.Technologies) != 2 {
+		t.Errorf("Original model changed")
+	}
+}
+
+func TestFilterKeepsPublishersAndSubscribersOfKeptEvents(t *testing.T) {
+	model, issues := LintText(eventsDefinition)
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+
+	slice, err := ViewFilter{Focus: "auth", Hops: 1}.Apply(*model)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(slice.Events) != 1 || len(slice.Events[0].Publishers) != 1 || len(slice.Events[0].Subscribers) != 0 {
+		t.Errorf("Invalid events: %+v", slice.Events)
+	}
+	if len(slice.Workflows) != 1 || len(slice.Workflows[0].Steps) != 1 {
+		t.Errorf("Invalid workflows: %+v", slice.Workflows)
+	}
+}
+
+func TestC4ExportOfEmptyDiagramView(t *testing.T) {
+	model, _ := LintText(strings.Replace(filterDefinition, "    include:\n", "    states:\n      - emerging\n    include:\n",
+		1))
+	printer := NewPrinter()
+
+	err := NewC4Exporter().export(*model, printer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(printer.String(), "backend") || strings.Contains(printer.String(), "include\n") {
+		t.Errorf("Empty view is exported:\n%v", printer.String())
+	}
+}
+
+func TestValidateFilter(t *testing.T) {
+	for _, test := range []struct {
+		filter   ViewFilter
+		expected string
+	}{
+		{ViewFilter{IncludeKinds: []string{"service", "servce"}}, "invalid include 'servce'"},
+		{ViewFilter{ExcludeKinds: []string{"form"}}, "invalid exclude 'form'"},
+		{ViewFilter{States: []string{"legcy"}}, "invalid state 'legcy'"},
+	} {
+		err := test.filter.Validate()
+		if err == nil || !strings.HasPrefix(err.Error(), test.expected) {
+			t.Errorf("Expected error %v, but got %v", test.expected, err)
+		}
+	}
+	if err := (ViewFilter{IncludeKinds: []string{"service"}, States: []string{"legacy"}}).Validate(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
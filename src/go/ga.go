@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"io"
 )
 
 type GeneticAlgorithm[G Cloner[G]] interface {
@@ -18,7 +19,7 @@ func NewGeneticAlgorithm[G Cloner[G]](
 	termination Termination[G],
 ) GeneticAlgorithm[G] {
 	return &ga[G]{makePool(poolSize, createGenome), operators, fitnessFunction,
-		selection, termination}
+		selection, termination, io.Discard}
 }
 
 func makePool[G Cloner[G]](size int, createGenome func() *Genome[G]) Population[G] {
@@ -37,6 +38,8 @@ type ga[G Cloner[G]] struct {
 	fitnessFunction FitnessFunction[G]
 	selection       Selection[G]
 	termination     Termination[G]
+	// log receives a dump of the pool after every iteration, which helps when tuning the algorithm.
+	log io.Writer
 }
 
 func (ga *ga[G]) best() *Genome[G] {
@@ -66,17 +69,17 @@ func (ga *ga[G]) refreshPool() {
 }
 
 func (ga *ga[G]) dumpPool(message string) {
-	fmt.Printf("\n%s:\n", message)
+	fmt.Fprintf(ga.log, "\n%s:\n", message)
 	total := 0.0
 	count := 0.0
 	max := 0.0
 	for _, dna := range ga.pool.all() {
-		fmt.Printf("%v => %0.4f\n", dna.Genes, dna.Fitness)
+		fmt.Fprintf(ga.log, "%v => %0.4f\n", dna.Genes, dna.Fitness)
 		total += dna.Fitness
 		count += 1.0
 		if dna.Fitness > max {
 			max = dna.Fitness
 		}
 	}
-	fmt.Printf("avg %0.4f, max %0.4f\n", total/count, max)
+	fmt.Fprintf(ga.log, "avg %0.4f, max %0.4f\n", total/count, max)
 }
@@ -86,3 +86,18 @@ func makeSelection() Selection[bit] {
 func makeTermination() Termination[bit] {
 	return NewOrTermination(NewMaxFitnessAchieved[bit](1.0), NewMaxIterations[bit](10))
 }
+
+func TestNoProgressMade(t *testing.T) {
+	termination := NewNoProgressMade[bit](3)
+	population := Population[bit]{[]*Genome[bit]{{[]bit{true}, 0.5}}}
+
+	for iteration, expected := range []bool{false, false, true, true, true} {
+		if termination.isMet(population) != expected {
+			t.Errorf("Expected %v for iteration %v", expected, iteration)
+		}
+	}
+	population.genomes[0].Fitness = 0.75
+	if termination.isMet(population) {
+		t.Errorf("Met after progress")
+	}
+}
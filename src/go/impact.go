@@ -0,0 +1,268 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Impact lists everything that depends on an element, for instance everything that breaks when a service is down.
+type Impact struct {
+	Element interface{}
+	// Affected are the elements that depend on the element, directly or transitively, in the order in which they were
+	// found.
+	Affected []interface{}
+	// Personas are the affected personas, i.e. those whose forms, views, or external systems depend on the element.
+	Personas []*Persona
+	// Workflows are the workflows that have steps that involve the element or any of the affected elements.
+	Workflows []*Workflow
+	next      map[interface{}]interface{}
+}
+
+// AnalyzeImpact finds the elements that depend on the given element by following relationships backwards. A
+// technology affects the elements implemented with it and the calls that use it.
+func AnalyzeImpact(model *ArchitectureModel, element interface{}) *Impact {
+	result := &Impact{
+		Element:   element,
+		Affected:  make([]interface{}, 0),
+		Personas:  make([]*Persona, 0),
+		Workflows: make([]*Workflow, 0),
+		next:      map[interface{}]interface{}{element: nil},
+	}
+	if technology, ok := element.(*Technology); ok {
+		result.addUsersOf(technology, model)
+	}
+	result.addDependents(model)
+	result.addWorkflows(model)
+	return result
+}
+
+func (i *Impact) isAffected(element interface{}) bool {
+	_, found := i.next[element]
+	return found
+}
+
+func (i *Impact) affect(element interface{}, next interface{}) {
+	i.next[element] = next
+	i.Affected = append(i.Affected, element)
+	if persona, ok := element.(*Persona); ok {
+		i.Personas = append(i.Personas, persona)
+	}
+}
+
+func (i *Impact) addUsersOf(technology *Technology, model *ArchitectureModel) {
+	for _, element := range model.Elements() {
+		if containerOf(element) != element || i.isAffected(element) {
+			continue
+		}
+		if _, found := lookUp(technologiesOf(element), technology.Id); found {
+			i.affect(element, technology)
+		}
+	}
+	for _, relationship := range model.Relationships() {
+		if _, found := lookUp(relationship.Technologies, technology.Id); found && !i.isAffected(relationship.From) {
+			i.affect(relationship.From, technology)
+		}
+	}
+}
+
+func (i *Impact) addDependents(model *ArchitectureModel) {
+	relationships := model.Relationships()
+	for changed := true; changed; {
+		changed = false
+		for _, relationship := range relationships {
+			if i.isAffected(relationship.From) {
+				continue
+			}
+			to := relationship.To
+			container := containerOf(to)
+			if !i.isAffected(to) && container != to && i.isAffected(container) {
+				i.affect(to, container)
+			}
+			if i.isAffected(to) {
+				i.affect(relationship.From, to)
+				changed = true
+			}
+		}
+	}
+}
+
+func (i *Impact) addWorkflows(model *ArchitectureModel) {
+	for _, workflow := range model.Workflows {
+		if len(i.involvedIn(workflow)) > 0 {
+			i.Workflows = append(i.Workflows, workflow)
+		}
+	}
+}
+
+// involvedIn returns the elements other than personas that steps of a workflow involve and that are affected, or whose
+// containers are.
+func (i *Impact) involvedIn(workflow *Workflow) []interface{} {
+	result := make([]interface{}, 0)
+	for _, step := range workflow.Steps {
+		for _, element := range elementsOfStep(step) {
+			if _, ok := element.(*Persona); ok {
+				continue
+			}
+			if i.isAffected(element) || i.isAffected(containerOf(element)) {
+				result = append(result, element)
+			}
+		}
+	}
+	return result
+}
+
+// PathOf returns the chain of dependencies from an affected element to the analyzed element.
+func (i *Impact) PathOf(element interface{}) []interface{} {
+	result := make([]interface{}, 0)
+	for current := element; current != nil; current = i.next[current] {
+		result = append(result, current)
+	}
+	return result
+}
+
+func (i *Impact) Print(printer *Printer) {
+	printer.PrintLn("Impact of ", refOf(i.Element), " (", nameOf(i.Element), ")")
+	if len(i.Affected) == 0 {
+		printer.PrintLn("Nothing depends on it")
+		return
+	}
+	printer.NewLine()
+	printer.PrintLn("Affected:")
+	for _, element := range i.Affected {
+		if _, ok := element.(*Persona); !ok {
+			i.printPath(element, printer)
+		}
+	}
+	if len(i.Personas) > 0 {
+		printer.NewLine()
+		printer.PrintLn("Personas:")
+		for _, persona := range i.Personas {
+			i.printPath(persona, printer)
+		}
+	}
+	if len(i.Workflows) > 0 {
+		printer.NewLine()
+		printer.PrintLn("Workflows:")
+		for _, workflow := range i.Workflows {
+			printer.PrintLn("- ", workflow.Name)
+		}
+	}
+}
+
+func (i *Impact) printPath(element interface{}, printer *Printer) {
+	refs := make([]string, 0)
+	for _, step := range i.PathOf(element) {
+		refs = append(refs, refOf(step))
+	}
+	printer.PrintLn("- ", nameOf(element), ": ", strings.Join(refs, " -> "))
+}
+
+// findElement finds an element by a reference like service:api, or by ID only if that is unique.
+func (model ArchitectureModel) findElement(ref string) (interface{}, error) {
+	kind, id, found := strings.Cut(ref, ":")
+	if found {
+		element, found := model.findElementByRef(kind, id)
+		if !found {
+			return nil, fmt.Errorf("unknown %v '%v'", kind, id)
+		}
+		return element, nil
+	}
+	candidates := make([]interface{}, 0)
+	for _, element := range model.Elements() {
+		if idOf(element) == ref {
+			candidates = append(candidates, element)
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return nil, fmt.Errorf("unknown element '%v'", ref)
+	case 1:
+		return candidates[0], nil
+	default:
+		return nil, fmt.Errorf("ambiguous element '%v': use <kind>:%v, e.g. %v", ref, ref, refOf(candidates[0]))
+	}
+}
+
+const (
+	impactedColor = "cc0000"
+	affectedColor = "ffd966"
+)
+
+type impactExporter struct {
+	ref string
+	dot bool
+}
+
+// NewImpactExporter creates an exporter for the subgraph of elements and workflows affected by the given element. The
+// format depends on the extension of the output file: .dot or .gv for Graphviz, and D2 otherwise.
+func NewImpactExporter(ref string, output string) TextExporter {
+	extension := strings.ToLower(filepath.Ext(output))
+	return impactExporter{ref, extension == ".dot" || extension == ".gv"}
+}
+
+func (e impactExporter) export(model ArchitectureModel, printer *Printer) error {
+	element, err := model.findElement(e.ref)
+	if err != nil {
+		return err
+	}
+	impact := AnalyzeImpact(&model, element)
+	if e.dot {
+		printer.PrintLn("digraph {")
+		printer.Start()
+	}
+	e.printNode(element, impactedColor, printer)
+	for _, affected := range impact.Affected {
+		if containerOf(affected) == affected {
+			e.printNode(affected, affectedColor, printer)
+		}
+	}
+	for _, workflow := range impact.Workflows {
+		e.printNode(workflow, affectedColor, printer)
+	}
+	for _, affected := range impact.Affected {
+		next := impact.next[affected]
+		from := impactNodeIdOf(affected)
+		to := impactNodeIdOf(next)
+		if from != to {
+			printer.PrintLn(from, " -> ", to)
+		}
+	}
+	for _, workflow := range impact.Workflows {
+		printed := make(map[string]bool)
+		for _, involved := range impact.involvedIn(workflow) {
+			if to := impactNodeIdOf(involved); !printed[to] {
+				printed[to] = true
+				printer.PrintLn(impactNodeIdOf(workflow), " -> ", to)
+			}
+		}
+	}
+	if e.dot {
+		printer.End()
+		printer.PrintLn("}")
+	}
+	return nil
+}
+
+func (e impactExporter) printNode(element interface{}, color string, printer *Printer) {
+	if e.dot {
+		printer.PrintLn(impactNodeIdOf(element), " [shape=box,style=filled,fillcolor=\"#", color, "\",label=\"",
+			escapeQuoted(nameOf(element)), "\"]")
+	} else {
+		printer.PrintLn(impactNodeIdOf(element), ": \"", escapeQuoted(nameOf(element)), "\" { style.fill: \"#", color,
+			"\" }")
+	}
+}
+
+// impactNodeIdOf returns the ID of the node of an element in the subgraph, which, unlike in other diagrams, may be an
+// event or a workflow.
+func impactNodeIdOf(element interface{}) string {
+	switch e := element.(type) {
+	case *Event:
+		return e.Id + "_event"
+	case *Workflow:
+		return e.Id + "_workflow"
+	default:
+		return nodeIdOf(element)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestImpact(t *testing.T) {
+	model, issues := LintText(queryDefinition)
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+	api, err := model.findElement("service:api")
+	if err != nil {
+		t.Fatalf("Can't find service: %v", err)
+	}
+
+	impact := AnalyzeImpact(model, api)
+
+	console, _ := model.findServiceById("console")
+	if !impact.isAffected(console) {
+		t.Errorf("Caller isn't affected: %+v", impact.Affected)
+	}
+	reporting, _ := model.findServiceById("reporting")
+	if impact.isAffected(reporting) {
+		t.Errorf("Unrelated service is affected")
+	}
+	if len(impact.Personas) != 1 || impact.Personas[0].Id != "dev" {
+		t.Fatalf("Invalid personas: %+v", impact.Personas)
+	}
+	path := impact.PathOf(impact.Personas[0])
+	if len(path) != 4 || path[1] != console.Forms[0] || path[2] != console || path[3] != api {
+		t.Errorf("Invalid path: %+v", path)
+	}
+	if len(impact.Workflows) != 1 {
+		t.Errorf("Invalid workflows: %+v", impact.Workflows)
+	}
+}
+
+func TestTechnologyImpact(t *testing.T) {
+	model, _ := LintText(queryDefinition)
+	cobol, _ := model.findElement("cobol")
+
+	impact := AnalyzeImpact(model, cobol)
+
+	if len(impact.Affected) != 1 || refOf(impact.Affected[0]) != "service:reporting" {
+		t.Errorf("Invalid affected elements: %+v", impact.Affected)
+	}
+}
+
+func TestImpactExportOfEvent(t *testing.T) {
+	model, issues := LintText(eventsDefinition)
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+	printer := NewPrinter()
+
+	err := NewImpactExporter("event:registered", "impact.dot").export(*model, printer)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `digraph {
+    registered_event [shape=box,style=filled,fillcolor="#cc0000",label="Registered"]
+    register_workflow [shape=box,style=filled,fillcolor="#ffd966",label="Register"]
+    register_workflow -> registered_event
+}
+`
+	if printer.String() != expected {
+		t.Errorf("Expected:\n%v\nbut got:\n%v", expected, printer.String())
+	}
+}
+
+func TestImpactExportEscapesNames(t *testing.T) {
+	model, issues := LintText(strings.Replace(eventsDefinition, "name: Domain events", `name: Domain "events"`, 1))
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+	printer := NewPrinter()
+
+	err := NewImpactExporter("queue:events", "impact.d2").export(*model, printer)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(printer.String(), "\n")
+	if lines[0] != `events_q: "Domain \"events\"" { style.fill: "#cc0000" }` {
+		t.Errorf("Invalid node: %v", lines[0])
+	}
+	for _, expected := range []string{"auth -> events_q", "register_workflow -> auth"} {
+		if !strings.Contains(printer.String(), expected+"\n") {
+			t.Errorf("Missing edge %v in:\n%v", expected, printer.String())
+		}
+	}
+}
@@ -0,0 +1,622 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ImportedModel is a draft model that an importer derives from files that describe how a system is deployed, or from a
+// model in another format. It only contains what can be derived from those files, and is meant to be completed by
+// hand.
+type ImportedModel struct {
+	SystemName      string
+	Personas        map[string]*ImportedPersona
+	ExternalSystems map[string]*ImportedExternalSystem
+	Services        map[string]*ImportedService
+	Databases       map[string]*ImportedDataStore
+	Queues          map[string]*ImportedDataStore
+	// Technologies maps the IDs of technologies to their names.
+	Technologies map[string]string
+	Teams        map[string]*ImportedElement
+	Environments map[string]*ImportedEnvironment
+	// Kinds are the kinds of elements that the importer can find, like service or database. Other kinds of elements
+	// aren't compared when looking for drift.
+	Kinds []string
+	// Warnings are about parts of the imported files that couldn't be imported.
+	Warnings []Issue
+}
+
+// ImportedElement is what an importer found out about an element, besides its relationships. All fields are optional.
+type ImportedElement struct {
+	Name        string
+	Description string
+	State       string
+	// Owner is the ID of the team that owns the element.
+	Owner        string
+	Technologies []string
+	Tags         []string
+	Properties   map[string]string
+}
+
+// ImportedPersona is a persona of an imported model, with the external systems it uses.
+type ImportedPersona struct {
+	ImportedElement
+	Uses []ImportedUse
+}
+
+// ImportedExternalSystem is an external system of an imported model, with the services it calls.
+type ImportedExternalSystem struct {
+	ImportedElement
+	Type  string
+	Calls []ImportedUse
+}
+
+// ImportedService is a service of an imported model, with the services it calls and the data stores it uses.
+type ImportedService struct {
+	ImportedElement
+	Calls      []ImportedUse
+	DataStores []ImportedUse
+	Forms      []string
+}
+
+// ImportedUse is a call to a service or external system, or a use of a database, queue, external system, form, or view.
+// The kind is that of the used element, like service or database.
+type ImportedUse struct {
+	Kind        string
+	Id          string
+	Description string
+	// DataFlow is empty for the default data flow.
+	DataFlow     string
+	Technologies []string
+	Tags         []string
+	Properties   map[string]string
+}
+
+type ImportedDataStore struct {
+	ImportedElement
+	// Views are those of a database.
+	Views []string
+}
+
+// ImportedEnvironment is an environment of an imported model, with its deployment nodes by ID.
+type ImportedEnvironment struct {
+	Name            string
+	DeploymentNodes map[string]*ImportedDeploymentNode
+}
+
+type ImportedDeploymentNode struct {
+	ImportedElement
+	DeploymentNodes map[string]*ImportedDeploymentNode
+	Instances       []ImportedInstance
+}
+
+// ImportedInstance deploys a service, database, or queue, depending on the kind.
+type ImportedInstance struct {
+	Kind     string
+	Id       string
+	Replicas int
+}
+
+// importers create draft models from the files that describe how a system is deployed, by the kind of file.
+var importers = map[string]func(input string) (*ImportedModel, error){
+	"compose":     ImportCompose,
+	"k8s":         ImportKubernetes,
+	"structurizr": ImportStructurizr,
+	"terraform":   ImportTerraform,
+}
+
+func NewImportedModel() *ImportedModel {
+	return &ImportedModel{
+		Personas:        make(map[string]*ImportedPersona),
+		ExternalSystems: make(map[string]*ImportedExternalSystem),
+		Services:        make(map[string]*ImportedService),
+		Databases:       make(map[string]*ImportedDataStore),
+		Queues:          make(map[string]*ImportedDataStore),
+		Technologies:    make(map[string]string),
+		Teams:           make(map[string]*ImportedElement),
+		Environments:    make(map[string]*ImportedEnvironment),
+		Kinds:           []string{"service", "database", "queue"},
+		Warnings:        make([]Issue, 0),
+	}
+}
+
+// infrastructureImages are the container images that importers recognize as databases and queues, by a keyword in the
+// name of the image.
+var infrastructureImages = []struct {
+	keyword        string
+	kind           string
+	technologyId   string
+	technologyName string
+}{
+	{"postgres", "database", "postgresql", "PostgreSQL"},
+	{"mysql", "database", "mysql", "MySQL"},
+	{"mariadb", "database", "mariadb", "MariaDB"},
+	{"mongo", "database", "mongodb", "MongoDB"},
+	{"redis", "database", "redis", "Redis"},
+	{"kafka", "queue", "kafka", "Kafka"},
+	{"rabbitmq", "queue", "rabbitmq", "RabbitMQ"},
+}
+
+// addContainer adds a service, or a database or queue if the image is recognizable.
+func (m *ImportedModel) addContainer(id string, image string) {
+	name := strings.ToLower(image)
+	if index := strings.LastIndex(name, "/"); index >= 0 {
+		name = name[index+1:]
+	}
+	name, _, _ = strings.Cut(name, ":")
+	name, _, _ = strings.Cut(name, "@")
+	for _, candidate := range infrastructureImages {
+		if strings.Contains(name, candidate.keyword) {
+			m.addDataStore(candidate.kind, id, candidate.technologyId, candidate.technologyName)
+			return
+		}
+	}
+	m.addService(id)
+}
+
+func (m *ImportedModel) addService(id string) *ImportedService {
+	service, found := m.Services[id]
+	if !found {
+		service = &ImportedService{}
+		m.Services[id] = service
+	}
+	return service
+}
+
+func (m *ImportedModel) addDataStore(kind string, id string, technologyId string,
+	technologyName string) *ImportedDataStore {
+	dataStores := m.Databases
+	if kind == "queue" {
+		dataStores = m.Queues
+	}
+	dataStore, found := dataStores[id]
+	if !found {
+		dataStore = &ImportedDataStore{}
+		dataStores[id] = dataStore
+	}
+	if technologyId != "" {
+		m.addTechnology(&dataStore.Technologies, technologyId, technologyName)
+	}
+	return dataStore
+}
+
+// addTechnology adds a technology to a list of technologies, unless the list already has it.
+func (m *ImportedModel) addTechnology(technologies *[]string, id string, name string) {
+	m.Technologies[id] = name
+	for _, existing := range *technologies {
+		if existing == id {
+			return
+		}
+	}
+	*technologies = append(*technologies, id)
+}
+
+// kindOf returns the kind of the element with the given ID, or an empty string if there is no such element.
+func (m *ImportedModel) kindOf(id string) string {
+	if _, found := m.Services[id]; found {
+		return "service"
+	}
+	if _, found := m.Databases[id]; found {
+		return "database"
+	}
+	if _, found := m.Queues[id]; found {
+		return "queue"
+	}
+	if _, found := m.ExternalSystems[id]; found {
+		return "externalSystem"
+	}
+	if _, found := m.Personas[id]; found {
+		return "persona"
+	}
+	return ""
+}
+
+// element returns the element with the given ID, or nil if there is no such element.
+func (m *ImportedModel) element(id string) *ImportedElement {
+	switch m.kindOf(id) {
+	case "service":
+		return &m.Services[id].ImportedElement
+	case "database":
+		return &m.Databases[id].ImportedElement
+	case "queue":
+		return &m.Queues[id].ImportedElement
+	case "externalSystem":
+		return &m.ExternalSystems[id].ImportedElement
+	case "persona":
+		return &m.Personas[id].ImportedElement
+	default:
+		return nil
+	}
+}
+
+// tag adds a tag to the element with the given ID.
+func (m *ImportedModel) tag(id string, tag string) {
+	element := m.element(id)
+	if element == nil {
+		return
+	}
+	for _, existing := range element.Tags {
+		if existing == tag {
+			return
+		}
+	}
+	element.Tags = append(element.Tags, tag)
+}
+
+// use adds a call or data store use from one element to another, unless it already uses that element. Uses that end
+// at an unknown element, or that the model doesn't support, are ignored.
+func (m *ImportedModel) use(from string, to string, description string) {
+	m.addUse(m.kindOf(from), from, ImportedUse{Kind: m.kindOf(to), Id: to, Description: description})
+}
+
+// has returns whether the model has an element of the given kind with the given ID. Elements of different kinds may
+// have the same ID, like a service and its database.
+func (m *ImportedModel) has(kind string, id string) bool {
+	found := false
+	switch kind {
+	case "persona":
+		_, found = m.Personas[id]
+	case "externalSystem":
+		_, found = m.ExternalSystems[id]
+	case "service":
+		_, found = m.Services[id]
+	case "database":
+		_, found = m.Databases[id]
+	case "queue":
+		_, found = m.Queues[id]
+	case "form":
+		for _, service := range m.Services {
+			found = found || contains(service.Forms, id)
+		}
+	case "view":
+		for _, database := range m.Databases {
+			found = found || contains(database.Views, id)
+		}
+	}
+	return found
+}
+
+// addUse adds a use of an external system, form, or view by a persona, a call from a service or external system to a service or
+// external system, or a data store use by a service, unless the element already uses the used element. It returns
+// whether the model supports the use.
+func (m *ImportedModel) addUse(fromKind string, from string, use ImportedUse) bool {
+	if !m.has(fromKind, from) || !m.has(use.Kind, use.Id) || (fromKind == use.Kind && from == use.Id) {
+		return false
+	}
+	var uses *[]ImportedUse
+	called := use.Kind == "service" || use.Kind == "externalSystem"
+	switch fromKind {
+	case "persona":
+		if use.Kind == "externalSystem" || use.Kind == "form" || use.Kind == "view" {
+			uses = &m.Personas[from].Uses
+		}
+	case "service":
+		if called {
+			uses = &m.Services[from].Calls
+		} else if use.Kind == "database" || use.Kind == "queue" {
+			uses = &m.Services[from].DataStores
+		}
+	case "externalSystem":
+		if called {
+			uses = &m.ExternalSystems[from].Calls
+		}
+	}
+	if uses == nil {
+		return false
+	}
+	for _, existing := range *uses {
+		if existing.Kind == use.Kind && existing.Id == use.Id {
+			return true
+		}
+	}
+	*uses = append(*uses, use)
+	return true
+}
+
+// Node returns the YAML of the imported model.
+func (m *ImportedModel) Node() *yaml.Node {
+	result := &yaml.Node{Kind: yaml.MappingNode}
+	if m.SystemName != "" {
+		system := &yaml.Node{Kind: yaml.MappingNode}
+		addNode(system, "name", scalarNode(m.SystemName))
+		addNode(result, "system", system)
+	}
+	if len(m.Personas) > 0 {
+		personas := &yaml.Node{Kind: yaml.MappingNode}
+		for _, id := range sortedKeys(m.Personas) {
+			persona := m.Personas[id]
+			node := &yaml.Node{Kind: yaml.MappingNode}
+			addElementNodes(node, &persona.ImportedElement)
+			addUsesNode(node, "uses", persona.Uses)
+			addExtensionNodes(node, &persona.ImportedElement)
+			addNode(personas, id, node)
+		}
+		addNode(result, "personas", personas)
+	}
+	if len(m.ExternalSystems) > 0 {
+		externalSystems := &yaml.Node{Kind: yaml.MappingNode}
+		for _, id := range sortedKeys(m.ExternalSystems) {
+			externalSystem := m.ExternalSystems[id]
+			node := &yaml.Node{Kind: yaml.MappingNode}
+			addElementNodes(node, &externalSystem.ImportedElement)
+			if externalSystem.Type != "" {
+				addNode(node, "type", scalarNode(externalSystem.Type))
+			}
+			addUsesNode(node, "calls", externalSystem.Calls)
+			addExtensionNodes(node, &externalSystem.ImportedElement)
+			addNode(externalSystems, id, node)
+		}
+		addNode(result, "externalSystems", externalSystems)
+	}
+	if len(m.Services) > 0 {
+		services := &yaml.Node{Kind: yaml.MappingNode}
+		for _, id := range sortedKeys(m.Services) {
+			service := m.Services[id]
+			node := &yaml.Node{Kind: yaml.MappingNode}
+			addElementNodes(node, &service.ImportedElement)
+			addUsesNode(node, "calls", service.Calls)
+			addUsesNode(node, "dataStores", service.DataStores)
+			if len(service.Forms) > 0 {
+				addNode(node, "forms", scalarsNode(service.Forms))
+			}
+			addExtensionNodes(node, &service.ImportedElement)
+			addNode(services, id, node)
+		}
+		addNode(result, "services", services)
+	}
+	for _, section := range []struct {
+		field      string
+		dataStores map[string]*ImportedDataStore
+	}{{"databases", m.Databases}, {"queues", m.Queues}} {
+		if len(section.dataStores) == 0 {
+			continue
+		}
+		dataStores := &yaml.Node{Kind: yaml.MappingNode}
+		for _, id := range sortedKeys(section.dataStores) {
+			node := &yaml.Node{Kind: yaml.MappingNode}
+			addElementNodes(node, &section.dataStores[id].ImportedElement)
+			if len(section.dataStores[id].Views) > 0 {
+				addNode(node, "views", scalarsNode(section.dataStores[id].Views))
+			}
+			addExtensionNodes(node, &section.dataStores[id].ImportedElement)
+			addNode(dataStores, id, node)
+		}
+		addNode(result, section.field, dataStores)
+	}
+	if len(m.Technologies) > 0 {
+		technologies := &yaml.Node{Kind: yaml.MappingNode}
+		for _, id := range sortedKeys(m.Technologies) {
+			node := &yaml.Node{Kind: yaml.MappingNode}
+			addNode(node, "name", scalarNode(m.Technologies[id]))
+			addNode(node, "quadrant", scalarNode("platforms"))
+			addNode(technologies, id, node)
+		}
+		addNode(result, "technologies", technologies)
+	}
+	if len(m.Teams) > 0 {
+		teams := &yaml.Node{Kind: yaml.MappingNode}
+		for _, id := range sortedKeys(m.Teams) {
+			node := &yaml.Node{Kind: yaml.MappingNode}
+			addElementNodes(node, m.Teams[id])
+			addNode(teams, id, node)
+		}
+		addNode(result, "teams", teams)
+	}
+	if len(m.Environments) > 0 {
+		environments := &yaml.Node{Kind: yaml.MappingNode}
+		for _, id := range sortedKeys(m.Environments) {
+			environment := m.Environments[id]
+			node := &yaml.Node{Kind: yaml.MappingNode}
+			if environment.Name != "" {
+				addNode(node, "name", scalarNode(environment.Name))
+			}
+			addDeploymentNodesNode(node, environment.DeploymentNodes)
+			addNode(environments, id, node)
+		}
+		addNode(result, "environments", environments)
+	}
+	return result
+}
+
+// addElementNodes adds the fields that describe an element.
+func addElementNodes(node *yaml.Node, element *ImportedElement) {
+	for _, field := range []struct {
+		name  string
+		value string
+	}{{"name", element.Name}, {"description", element.Description}, {"state", element.State},
+		{"owner", element.Owner}} {
+		if field.value != "" {
+			addNode(node, field.name, scalarNode(field.value))
+		}
+	}
+	if len(element.Technologies) > 0 {
+		addNode(node, "technologies", scalarsNode(element.Technologies))
+	}
+}
+
+// addExtensionNodes adds the tags and properties of an element.
+func addExtensionNodes(node *yaml.Node, element *ImportedElement) {
+	addTagsNode(node, element.Tags)
+	if len(element.Properties) > 0 {
+		properties := &yaml.Node{Kind: yaml.MappingNode}
+		for _, key := range sortedKeys(element.Properties) {
+			addNode(properties, key, scalarNode(element.Properties[key]))
+		}
+		addNode(node, "properties", properties)
+	}
+}
+
+func addDeploymentNodesNode(node *yaml.Node, deploymentNodes map[string]*ImportedDeploymentNode) {
+	if len(deploymentNodes) == 0 {
+		return
+	}
+	result := &yaml.Node{Kind: yaml.MappingNode}
+	for _, id := range sortedKeys(deploymentNodes) {
+		deploymentNode := deploymentNodes[id]
+		child := &yaml.Node{Kind: yaml.MappingNode}
+		addElementNodes(child, &deploymentNode.ImportedElement)
+		addDeploymentNodesNode(child, deploymentNode.DeploymentNodes)
+		if len(deploymentNode.Instances) > 0 {
+			instances := &yaml.Node{Kind: yaml.SequenceNode}
+			for _, instance := range deploymentNode.Instances {
+				item := &yaml.Node{Kind: yaml.MappingNode}
+				addNode(item, instance.Kind, scalarNode(instance.Id))
+				if instance.Replicas > 1 {
+					addNode(item, "replicas", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int",
+						Value: fmt.Sprint(instance.Replicas)})
+				}
+				instances.Content = append(instances.Content, item)
+			}
+			addNode(child, "instances", instances)
+		}
+		addExtensionNodes(child, &deploymentNode.ImportedElement)
+		addNode(result, id, child)
+	}
+	addNode(node, "deploymentNodes", result)
+}
+
+func addUsesNode(node *yaml.Node, field string, uses []ImportedUse) {
+	if len(uses) == 0 {
+		return
+	}
+	sequence := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, use := range uses {
+		item := &yaml.Node{Kind: yaml.MappingNode}
+		addNode(item, use.Kind, scalarNode(use.Id))
+		if use.Description != "" {
+			addNode(item, "description", scalarNode(use.Description))
+		}
+		if use.DataFlow != "" {
+			addNode(item, "dataFlow", scalarNode(use.DataFlow))
+		}
+		if len(use.Technologies) > 0 {
+			addNode(item, "technologies", scalarsNode(use.Technologies))
+		}
+		addExtensionNodes(item, &ImportedElement{Tags: use.Tags, Properties: use.Properties})
+		sequence.Content = append(sequence.Content, item)
+	}
+	addNode(node, field, sequence)
+}
+
+func addTagsNode(node *yaml.Node, tags []string) {
+	if len(tags) > 0 {
+		addNode(node, "tags", scalarsNode(tags))
+	}
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	result := make([]string, 0, len(values))
+	for key := range values {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func addNode(mapping *yaml.Node, key string, value *yaml.Node) {
+	mapping.Content = append(mapping.Content, scalarNode(key), value)
+}
+
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+func scalarsNode(values []string) *yaml.Node {
+	result := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, value := range values {
+		result.Content = append(result.Content, scalarNode(value))
+	}
+	return result
+}
+
+// mergeNodes adds what the imported node has and the existing node doesn't. Existing scalars are kept, and items of
+// sequences are only added when the existing sequence doesn't have them yet.
+func mergeNodes(existing *yaml.Node, imported *yaml.Node) {
+	switch {
+	case existing.Kind == yaml.MappingNode && imported.Kind == yaml.MappingNode:
+		for index := 0; index < len(imported.Content); index += 2 {
+			key := imported.Content[index].Value
+			value := imported.Content[index+1]
+			if existingValue := valueOf(existing, key); existingValue != nil {
+				mergeNodes(existingValue, value)
+			} else {
+				existing.Content = append(existing.Content, imported.Content[index], value)
+			}
+		}
+	case existing.Kind == yaml.SequenceNode && imported.Kind == yaml.SequenceNode:
+		for _, item := range imported.Content {
+			if !containsItem(existing, item) {
+				existing.Content = append(existing.Content, item)
+			}
+		}
+	}
+}
+
+func valueOf(mapping *yaml.Node, key string) *yaml.Node {
+	for index := 0; index+1 < len(mapping.Content); index += 2 {
+		if mapping.Content[index].Value == key {
+			return mapping.Content[index+1]
+		}
+	}
+	return nil
+}
+
+// containsItem returns whether a sequence has an item like the given one. Scalars are alike when they're equal, and
+// maps when they have the same value for the first key of the given map, like service: api.
+func containsItem(sequence *yaml.Node, item *yaml.Node) bool {
+	for _, candidate := range sequence.Content {
+		if candidate.Kind != item.Kind {
+			continue
+		}
+		switch item.Kind {
+		case yaml.ScalarNode:
+			if candidate.Value == item.Value {
+				return true
+			}
+		case yaml.MappingNode:
+			if len(item.Content) == 0 {
+				continue
+			}
+			if value := valueOf(candidate, item.Content[0].Value); value != nil && value.Value == item.Content[1].Value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WriteImportedModel writes the imported model to the output file. If the file already exists, the imported model is
+// merged into it, so that hand-written parts of the model are kept.
+func WriteImportedModel(model *ImportedModel, output string) error {
+	node := model.Node()
+	existing, err := os.ReadFile(output)
+	if err == nil {
+		var document yaml.Node
+		err = yaml.Unmarshal(existing, &document)
+		if err != nil {
+			return fmt.Errorf("can't merge into %v: %v", output, err)
+		}
+		if !document.IsZero() {
+			if document.Kind != yaml.DocumentNode || document.Content[0].Kind != yaml.MappingNode {
+				return fmt.Errorf("can't merge into %v: must be a map", output)
+			}
+			mergeNodes(document.Content[0], node)
+			node = document.Content[0]
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	err = encoder.Encode(node)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(output, buffer.Bytes(), 0666)
+}
@@ -0,0 +1,334 @@
+package main
+
+import (
+	"gopkg.in/yaml.v3"
+	"reflect"
+	"testing"
+)
+
+const composeDefinition = `services:
+  web:
+    build: .
+    depends_on:
+      - api
+  api:
+    image: acme/api:1.2
+    depends_on:
+      db:
+        condition: service_healthy
+    networks:
+      - backend
+  worker:
+    image: acme/worker
+    networks: [backend]
+  db:
+    image: postgres:15
+    networks:
+      - backend
+  events:
+    image: bitnami/kafka:3.6
+    networks:
+      backend: {}
+  cache:
+    image: redis
+`
+
+func TestImportCompose(t *testing.T) {
+	imported, err := importCompose([]byte(composeDefinition))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(imported.Services) != 3 || len(imported.Databases) != 2 || len(imported.Queues) != 1 {
+		t.Fatalf("Invalid elements: %+v", imported)
+	}
+	if imported.Databases["db"].Technologies[0] != "postgresql" || imported.Queues["events"].Technologies[0] != "kafka" {
+		t.Errorf("Invalid technologies: %+v", imported.Technologies)
+	}
+	if calls := imported.Services["web"].Calls; len(calls) != 1 || calls[0].Id != "api" {
+		t.Errorf("Invalid calls: %+v", calls)
+	}
+	if uses := imported.Services["api"].DataStores; len(uses) != 2 ||
+		!reflect.DeepEqual(uses[0], ImportedUse{Kind: "database", Id: "db", Description: "Imported from depends_on"}) ||
+		!reflect.DeepEqual(uses[1], ImportedUse{Kind: "queue", Id: "events", Description: "Imported from network backend"}) {
+		t.Errorf("Invalid data store uses: %+v", uses)
+	}
+	if uses := imported.Services["worker"].DataStores; len(uses) != 2 {
+		t.Errorf("Invalid data store uses: %+v", uses)
+	}
+
+	text, err := yaml.Marshal(imported.Node())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, issues := LintText(string(text))
+	if hasIssue(issues, hasError("")) {
+		t.Errorf("Invalid model: %+v\n%v", issues, string(text))
+	}
+}
+
+func TestMergeImportedModel(t *testing.T) {
+	var document yaml.Node
+	_ = yaml.Unmarshal([]byte(`# Hand-written
+services:
+  web:
+    name: Web shop
+    calls:
+      - service: api
+        description: Fetches products
+databases:
+  db:
+    technologies: mysql
+technologies:
+  mysql:
+    quadrant: platforms
+`), &document)
+	imported, _ := importCompose([]byte(composeDefinition))
+
+	mergeNodes(document.Content[0], imported.Node())
+
+	model, issues := LintText(nodeToString(t, &document))
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+	web, _ := model.findServiceById("web")
+	if web.Name != "Web shop" || len(web.Calls) != 1 || web.Calls[0].Description != "Fetches products" {
+		t.Errorf("Hand-written service not kept: %+v", web)
+	}
+	if len(model.Databases[1].Technologies) != 1 || model.Databases[1].Technologies[0].Id != "mysql" {
+		t.Errorf("Hand-written technologies not kept: %+v", model.Databases[1])
+	}
+	if len(model.Services) != 3 || len(model.Queues) != 1 {
+		t.Errorf("Imported elements not added: %+v", model.Services)
+	}
+}
+
+func nodeToString(t *testing.T, node *yaml.Node) string {
+	text, err := yaml.Marshal(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(text)
+}
+
+const kubernetesDefinition = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: orders-api
+  namespace: shop
+spec:
+  template:
+    metadata:
+      labels:
+        app: orders
+    spec:
+      containers:
+        - image: acme/orders:2.0
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: orders-db
+  namespace: shop
+spec:
+  template:
+    metadata:
+      labels:
+        app: orders-db
+    spec:
+      containers:
+        - image: postgres:15
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: orders
+  namespace: shop
+spec:
+  selector:
+    app: orders
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: shop
+  namespace: shop
+spec:
+  rules:
+    - http:
+        paths:
+          - path: /orders
+            backend:
+              service:
+                name: orders
+---
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: orders-egress
+  namespace: shop
+spec:
+  podSelector:
+    matchLabels:
+      app: orders
+  egress:
+    - to:
+        - podSelector:
+            matchLabels:
+              app: orders-db
+        - ipBlock:
+            cidr: 10.0.0.0/8
+`
+
+const kubernetesReportingDefinition = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: reports
+spec:
+  template:
+    spec:
+      containers:
+        - image: redis
+`
+
+func TestImportKubernetes(t *testing.T) {
+	imported, err := importKubernetes([]byte(kubernetesDefinition), []byte(kubernetesReportingDefinition))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(imported.Services) != 2 || len(imported.Databases) != 1 || len(imported.ExternalSystems) != 1 {
+		t.Fatalf("Invalid elements: %+v", imported)
+	}
+	if tags := imported.Services["orders-api"].Tags; len(tags) != 1 || tags[0] != "shop" {
+		t.Errorf("Invalid tags: %+v", tags)
+	}
+	if tags := imported.Services["reports"].Tags; len(tags) != 1 || tags[0] != "default" {
+		t.Errorf("Invalid tags: %+v", tags)
+	}
+	if imported.Databases["orders-db"].Technologies[0] != "postgresql" {
+		t.Errorf("Invalid technologies: %+v", imported.Databases["orders-db"])
+	}
+	if calls := imported.ExternalSystems["shop"].Calls; len(calls) != 1 || !reflect.DeepEqual(calls[0],
+		ImportedUse{Kind: "service", Id: "orders-api", Description: "Imported from ingress shop"}) {
+		t.Errorf("Invalid calls: %+v", calls)
+	}
+	if uses := imported.Services["orders-api"].DataStores; len(uses) != 1 || !reflect.DeepEqual(uses[0],
+		ImportedUse{Kind: "database", Id: "orders-db", Description: "Imported from network policy orders-egress"}) {
+		t.Errorf("Invalid data store uses: %+v", uses)
+	}
+
+	text, err := yaml.Marshal(imported.Node())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, issues := LintText(string(text))
+	if hasIssue(issues, hasError("")) {
+		t.Errorf("Invalid model: %+v\n%v", issues, string(text))
+	}
+}
+
+func TestDrift(t *testing.T) {
+	imported, err := importKubernetes([]byte(kubernetesDefinition))
+	if err != nil {
+		t.Fatal(err)
+	}
+	model, issues := LintText(`services:
+  ordersApi:
+    name: Orders
+  billing:
+    name: Billing
+`)
+	if model == nil {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+
+	drift := FindDrift(model, imported)
+
+	if len(drift.Unmodeled) != 1 || drift.Unmodeled[0] != "database:orders-db" {
+		t.Errorf("Invalid unmodeled elements: %+v", drift.Unmodeled)
+	}
+	if len(drift.Missing) != 1 || drift.Missing[0] != "service:billing" {
+		t.Errorf("Invalid missing elements: %+v", drift.Missing)
+	}
+}
+
+const terraformDefinition = `{
+  "version": 4,
+  "resources": [
+    {
+      "mode": "managed",
+      "type": "aws_db_instance",
+      "name": "orders",
+      "instances": [{"attributes": {"identifier": "orders-db", "engine": "postgres"}}]
+    },
+    {
+      "mode": "managed",
+      "type": "google_sql_database_instance",
+      "name": "billing",
+      "instances": [{"attributes": {"name": "", "database_version": "MYSQL_8_0"}}]
+    },
+    {
+      "mode": "managed",
+      "type": "aws_sqs_queue",
+      "name": "jobs",
+      "instances": [{"attributes": {"name": "jobs-a"}}, {"attributes": {"name": "jobs-b"}}]
+    },
+    {
+      "mode": "data",
+      "type": "aws_sns_topic",
+      "name": "alerts",
+      "instances": [{"attributes": {"name": "alerts"}}]
+    },
+    {
+      "mode": "managed",
+      "type": "aws_s3_bucket",
+      "name": "assets",
+      "instances": [{"attributes": {"bucket": "assets"}}]
+    }
+  ]
+}`
+
+func TestImportTerraform(t *testing.T) {
+	imported, err := importTerraform([]byte(terraformDefinition))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(imported.Services) != 0 || len(imported.Databases) != 2 || len(imported.Queues) != 2 {
+		t.Fatalf("Invalid elements: %+v", imported)
+	}
+	if technologies := imported.Databases["orders-db"].Technologies; len(technologies) != 2 ||
+		technologies[0] != "postgresql" || technologies[1] != "amazon-rds" {
+		t.Errorf("Invalid technologies: %+v", technologies)
+	}
+	if technologies := imported.Databases["billing"].Technologies; len(technologies) != 2 ||
+		technologies[0] != "mysql" || technologies[1] != "cloud-sql" {
+		t.Errorf("Invalid technologies: %+v", technologies)
+	}
+	if technologies := imported.Queues["jobs-b"].Technologies; len(technologies) != 1 ||
+		technologies[0] != "amazon-sqs" {
+		t.Errorf("Invalid technologies: %+v", technologies)
+	}
+
+	model, issues := LintText(`services:
+  orders:
+    name: Orders
+databases:
+  ordersDb:
+    name: Orders
+  legacy:
+    name: Legacy
+`)
+	if model == nil {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+	drift := FindDrift(model, imported)
+
+	if len(drift.Unmodeled) != 3 || drift.Unmodeled[0] != "database:billing" {
+		t.Errorf("Invalid unmodeled elements: %+v", drift.Unmodeled)
+	}
+	if len(drift.Missing) != 1 || drift.Missing[0] != "database:legacy" {
+		t.Errorf("Invalid missing elements: %+v", drift.Missing)
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"sort"
+)
+
+// Interface is an API that a service offers to its callers, like a REST API or a gRPC service.
+type Interface struct {
+	node        *yaml.Node
+	Id          string
+	Name        string
+	Description string
+	Type        string
+	// Spec refers to the definition of the interface, like an OpenAPI file or a protobuf file.
+	Spec               string
+	Version            string
+	TechnologyIds      []string
+	TechnologyBundleId string
+	Technologies       []*Technology
+	ImplementedBy      *Service
+	Extensions
+}
+
+func (i *Interface) setNode(node *yaml.Node) {
+	i.node = node
+}
+
+func (i *Interface) setId(id string) {
+	i.Id = id
+}
+
+func (i *Interface) setName(name string) {
+	i.Name = name
+}
+
+func (i *Interface) getDescription() string {
+	return i.Description
+}
+
+func (i *Interface) setDescription(description string) {
+	i.Description = description
+}
+
+func (i *Interface) getNode() *yaml.Node {
+	return i.node
+}
+
+func (i *Interface) getTechnologyIds() []string {
+	return i.TechnologyIds
+}
+
+func (i *Interface) setTechnologyIds(technologies []string) {
+	i.TechnologyIds = technologies
+}
+
+func (i *Interface) getTechnologyBundleId() string {
+	return i.TechnologyBundleId
+}
+
+func (i *Interface) setTechnologyBundleId(technologyBundle string) {
+	i.TechnologyBundleId = technologyBundle
+}
+
+func (i *Interface) getTechnologies() []*Technology {
+	return i.Technologies
+}
+
+func (i *Interface) setTechnologies(technologies []*Technology) {
+	i.Technologies = technologies
+}
+
+var interfaceFields = []string{"name", "description", "type", "spec", "version", "technologies"}
+
+var interfaceTypes = []string{"graphql", "grpc", "rest"}
+
+func (i *Interface) read(id string, node *yaml.Node) []Issue {
+	var fields map[string]*yaml.Node
+	fields, issues := namedObject(node, id, i)
+	issues = append(issues, setDescription(fields, i)...)
+	issues = append(issues, setTechnologies(fields, i)...)
+	interfaceType, issue := enumFieldOf(node, fields, "type", interfaceTypes, "")
+	if issue != nil {
+		issues = append(issues, *issue)
+	} else {
+		i.Type = interfaceType
+	}
+	for field, target := range map[string]*string{"spec": &i.Spec, "version": &i.Version} {
+		value, found, issue := stringFieldOf(fields, field)
+		if issue != nil {
+			issues = append(issues, *issue)
+		} else if found {
+			*target = value
+		}
+	}
+	issues = append(issues, checkExtensibleFields(fields, interfaceFields)...)
+	return issues
+}
+
+func (s *Service) readInterfaces(fields map[string]*yaml.Node) []Issue {
+	interfacesById, _, issue := mapFieldOf(fields, "interfaces")
+	if issue != nil {
+		return []Issue{*issue}
+	}
+	issues := make([]Issue, 0)
+	interfaces := make([]*Interface, 0)
+	for id, interfaceNode := range interfacesById {
+		iface := Interface{ImplementedBy: s}
+		interfaces = append(interfaces, &iface)
+		issues = append(issues, iface.read(id, interfaceNode)...)
+	}
+	sort.Slice(interfaces, func(i, j int) bool {
+		return interfaces[i].Id < interfaces[j].Id
+	})
+	s.Interfaces = interfaces
+	return issues
+}
+
+func (s *Service) findInterfaceById(id string) (*Interface, bool) {
+	for _, candidate := range s.Interfaces {
+		if candidate.Id == id {
+			return candidate, true
+		}
+	}
+	return nil, false
+}
+
+// connectInterface connects a call to the interface of the called service that it targets, if any.
+func connectInterface(call *Call) []Issue {
+	if call.InterfaceId == "" || call.Service == nil {
+		return []Issue{}
+	}
+	iface, found := call.Service.findInterfaceById(call.InterfaceId)
+	if !found {
+		return []Issue{*NodeError(fmt.Sprintf("Service '%v' has no interface '%v'", call.Service.Id,
+			call.InterfaceId), call.node)}
+	}
+	call.Interface = iface
+	return []Issue{}
+}
+
+// validateInterfacesAreCalled warns about interfaces that no call targets, unless their service is deprecated.
+func (s ServiceValidator) validateInterfacesAreCalled(model *ArchitectureModel) []Issue {
+	calls := make([]*Call, 0)
+	for _, externalSystem := range model.ExternalSystems {
+		calls = append(calls, externalSystem.Calls...)
+	}
+	for _, service := range model.Services {
+		calls = append(calls, service.Calls...)
+	}
+	called := make(map[*Interface]bool)
+	for _, call := range calls {
+		if call.Interface != nil {
+			called[call.Interface] = true
+		}
+	}
+	issues := make([]Issue, 0)
+	for _, service := range model.Services {
+		if service.State == Deprecated {
+			continue
+		}
+		for _, iface := range service.Interfaces {
+			if !called[iface] {
+				issues = append(issues, *NodeWarning(fmt.Sprintf("Interface '%v' of service '%v' is never called",
+					iface.Id, service.Id), iface.node))
+			}
+		}
+	}
+	return issues
+}
@@ -0,0 +1,507 @@
+package main
+
+import (
+	"encoding/json"
+)
+
+// The version of the JSON encoding of the model. The minor version is increased when fields are added; the major
+// version when fields are removed or their meaning changes. Consumers must ignore fields they don't know.
+const jsonFormatVersion = "1.9"
+
+// JsonModel is the JSON encoding of a linted and connected ArchitectureModel. References between elements are
+// encoded as IDs rather than pointers, so the model can be handed to other processes.
+type JsonModel struct {
+	FormatVersion   string               `json:"formatVersion"`
+	Version         string               `json:"version"`
+	System          JsonSystem           `json:"system"`
+	Personas        []JsonPersona        `json:"personas"`
+	ExternalSystems []JsonExternalSystem `json:"externalSystems"`
+	Services        []JsonService        `json:"services"`
+	Databases       []JsonDatabase       `json:"databases"`
+	Queues          []JsonDataStore      `json:"queues"`
+	Technologies    []JsonTechnology     `json:"technologies"`
+	Workflows       []JsonWorkflow       `json:"workflows"`
+	Styles          *Theme               `json:"styles"`
+	Teams           []JsonTeam           `json:"teams"`
+	Events          []JsonEvent          `json:"events"`
+	Environments    []JsonEnvironment    `json:"environments"`
+}
+
+// JsonExtensions are the tags and properties of an element or call.
+type JsonExtensions struct {
+	Tags       []string          `json:"tags,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+func jsonExtensionsOf(extensions *Extensions) JsonExtensions {
+	result := JsonExtensions{Tags: extensions.Tags}
+	if len(extensions.Properties) > 0 {
+		result.Properties = extensions.Properties
+	}
+	return result
+}
+
+type JsonTeam struct {
+	Id          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Contact     string            `json:"contact,omitempty"`
+	Links       map[string]string `json:"links,omitempty"`
+	JsonExtensions
+}
+
+type JsonSystem struct {
+	Id   string `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
+type JsonPersona struct {
+	Id          string     `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Uses        []JsonUsed `json:"uses"`
+	JsonExtensions
+}
+
+type JsonUsed struct {
+	ExternalSystem string `json:"externalSystem,omitempty"`
+	Form           string `json:"form,omitempty"`
+	View           string `json:"view,omitempty"`
+	Description    string `json:"description,omitempty"`
+	DataFlow       string `json:"dataFlow"`
+}
+
+type JsonExternalSystem struct {
+	Id          string     `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Type        string     `json:"type,omitempty"`
+	System      string     `json:"system,omitempty"`
+	Owner       string     `json:"owner,omitempty"`
+	Calls       []JsonCall `json:"calls"`
+	JsonExtensions
+}
+
+type JsonCall struct {
+	Service        string   `json:"service,omitempty"`
+	ExternalSystem string   `json:"externalSystem,omitempty"`
+	Interface      string   `json:"interface,omitempty"`
+	Description    string   `json:"description,omitempty"`
+	DataFlow       string   `json:"dataFlow"`
+	Technologies   []string `json:"technologies"`
+	JsonExtensions
+}
+
+type JsonService struct {
+	Id           string             `json:"id"`
+	Name         string             `json:"name"`
+	Description  string             `json:"description,omitempty"`
+	State        string             `json:"state"`
+	Owner        string             `json:"owner,omitempty"`
+	Technologies []string           `json:"technologies"`
+	DataStores   []JsonDataStoreUse `json:"dataStores"`
+	Forms        []JsonForm         `json:"forms"`
+	Commands     []JsonCommand      `json:"commands"`
+	Interfaces   []JsonInterface    `json:"interfaces"`
+	Calls        []JsonCall         `json:"calls"`
+	Publishes    []string           `json:"publishes"`
+	Subscribes   []string           `json:"subscribes"`
+	Repository   string             `json:"repository,omitempty"`
+	JsonExtensions
+}
+
+type JsonForm struct {
+	JsonEvolvableName
+	Owner string `json:"owner,omitempty"`
+	JsonExtensions
+}
+
+type JsonCommand struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	JsonExtensions
+}
+
+type JsonInterface struct {
+	Id           string   `json:"id"`
+	Name         string   `json:"name"`
+	Description  string   `json:"description,omitempty"`
+	Type         string   `json:"type"`
+	Spec         string   `json:"spec,omitempty"`
+	Version      string   `json:"version,omitempty"`
+	Technologies []string `json:"technologies"`
+	JsonExtensions
+}
+
+type JsonDataStoreUse struct {
+	Database    string `json:"database,omitempty"`
+	Queue       string `json:"queue,omitempty"`
+	Description string `json:"description,omitempty"`
+	DataFlow    string `json:"dataFlow"`
+}
+
+// JsonEvolvableName encodes forms and views, which have nothing but an ID, a name, and a state.
+type JsonEvolvableName struct {
+	Id    string `json:"id"`
+	Name  string `json:"name"`
+	State string `json:"state"`
+}
+
+type JsonDataStore struct {
+	Id              string   `json:"id"`
+	Name            string   `json:"name"`
+	Description     string   `json:"description,omitempty"`
+	State           string   `json:"state"`
+	Owner           string   `json:"owner,omitempty"`
+	Technologies    []string `json:"technologies"`
+	ApiTechnologies []string `json:"apiTechnologies"`
+	JsonExtensions
+}
+
+type JsonDatabase struct {
+	JsonDataStore
+	Views []JsonEvolvableName `json:"views"`
+}
+
+type JsonTechnology struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Quadrant    string `json:"quadrant"`
+	Ring        string `json:"ring"`
+	JsonExtensions
+}
+
+type JsonEvent struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Schema      string `json:"schema,omitempty"`
+	Queue       string `json:"queue"`
+	JsonExtensions
+}
+
+type JsonEnvironment struct {
+	Id              string               `json:"id"`
+	Name            string               `json:"name"`
+	Description     string               `json:"description,omitempty"`
+	DeploymentNodes []JsonDeploymentNode `json:"deploymentNodes"`
+	JsonExtensions
+}
+
+type JsonDeploymentNode struct {
+	Id              string               `json:"id"`
+	Name            string               `json:"name"`
+	Description     string               `json:"description,omitempty"`
+	Technologies    []string             `json:"technologies"`
+	DeploymentNodes []JsonDeploymentNode `json:"deploymentNodes"`
+	Instances       []JsonInstance       `json:"instances"`
+	JsonExtensions
+}
+
+type JsonInstance struct {
+	Service  string `json:"service,omitempty"`
+	Database string `json:"database,omitempty"`
+	Queue    string `json:"queue,omitempty"`
+	Replicas int    `json:"replicas"`
+}
+
+type JsonWorkflow struct {
+	Id          string     `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	TopLevel    bool       `json:"topLevel"`
+	Steps       []JsonStep `json:"steps"`
+	JsonExtensions
+}
+
+type JsonStep struct {
+	Performer      string `json:"performer"`
+	Description    string `json:"description,omitempty"`
+	Form           string `json:"form,omitempty"`
+	View           string `json:"view,omitempty"`
+	Command        string `json:"command,omitempty"`
+	Event          string `json:"event,omitempty"`
+	Service        string `json:"service,omitempty"`
+	ExternalSystem string `json:"externalSystem,omitempty"`
+}
+
+func NewJsonModel(model *ArchitectureModel) JsonModel {
+	result := JsonModel{
+		FormatVersion:   jsonFormatVersion,
+		Version:         model.Version,
+		System:          JsonSystem{model.System.Id, model.System.Name},
+		Personas:        make([]JsonPersona, 0),
+		ExternalSystems: make([]JsonExternalSystem, 0),
+		Services:        make([]JsonService, 0),
+		Databases:       make([]JsonDatabase, 0),
+		Queues:          make([]JsonDataStore, 0),
+		Technologies:    make([]JsonTechnology, 0),
+		Workflows:       make([]JsonWorkflow, 0),
+		Styles:          model.theme(),
+		Teams:           make([]JsonTeam, 0),
+		Events:          make([]JsonEvent, 0),
+		Environments:    make([]JsonEnvironment, 0),
+	}
+	for _, persona := range model.Personas {
+		result.Personas = append(result.Personas, jsonPersonaOf(persona))
+	}
+	for _, externalSystem := range model.ExternalSystems {
+		result.ExternalSystems = append(result.ExternalSystems, jsonExternalSystemOf(externalSystem))
+	}
+	for _, service := range model.Services {
+		result.Services = append(result.Services, jsonServiceOf(service))
+	}
+	for _, database := range model.Databases {
+		result.Databases = append(result.Databases, jsonDatabaseOf(database))
+	}
+	for _, queue := range model.Queues {
+		result.Queues = append(result.Queues, jsonDataStoreOf(queue))
+	}
+	for _, technology := range model.Technologies {
+		result.Technologies = append(result.Technologies, jsonTechnologyOf(technology))
+	}
+	for _, workflow := range model.Workflows {
+		result.Workflows = append(result.Workflows, jsonWorkflowOf(workflow))
+	}
+	for _, team := range model.Teams {
+		result.Teams = append(result.Teams, jsonTeamOf(team))
+	}
+	for _, event := range model.Events {
+		result.Events = append(result.Events, JsonEvent{Id: event.Id, Name: event.Name, Description: event.Description,
+			Schema: event.Schema, Queue: event.QueueId, JsonExtensions: jsonExtensionsOf(&event.Extensions)})
+	}
+	for _, environment := range model.Environments {
+		result.Environments = append(result.Environments, jsonEnvironmentOf(environment))
+	}
+	return result
+}
+
+func jsonPersonaOf(persona *Persona) JsonPersona {
+	result := JsonPersona{Id: persona.Id, Name: persona.Name, Description: persona.Description, Uses: make([]JsonUsed, 0),
+		JsonExtensions: jsonExtensionsOf(&persona.Extensions)}
+	for _, used := range persona.Uses {
+		result.Uses = append(result.Uses, JsonUsed{
+			ExternalSystem: used.ExternalSystemId,
+			Form:           used.FormId,
+			View:           used.ViewId,
+			Description:    used.Description,
+			DataFlow:       used.DataFlow.String(),
+		})
+	}
+	return result
+}
+
+func jsonExternalSystemOf(externalSystem *ExternalSystem) JsonExternalSystem {
+	return JsonExternalSystem{
+		Id:             externalSystem.Id,
+		Name:           externalSystem.Name,
+		Description:    externalSystem.Description,
+		Type:           externalSystem.Type,
+		System:         externalSystem.SystemId,
+		Owner:          externalSystem.OwnerId,
+		Calls:          jsonCallsOf(externalSystem.Calls),
+		JsonExtensions: jsonExtensionsOf(&externalSystem.Extensions),
+	}
+}
+
+func jsonCallsOf(calls []*Call) []JsonCall {
+	result := make([]JsonCall, 0)
+	for _, call := range calls {
+		result = append(result, JsonCall{
+			Service:        call.ServiceId,
+			ExternalSystem: call.ExternalSystemId,
+			Interface:      call.InterfaceId,
+			Description:    call.Description,
+			DataFlow:       call.DataFlow.String(),
+			Technologies:   jsonTechnologyIdsOf(call.Technologies),
+			JsonExtensions: jsonExtensionsOf(&call.Extensions),
+		})
+	}
+	return result
+}
+
+func jsonTechnologyIdsOf(technologies []*Technology) []string {
+	result := make([]string, 0)
+	for _, technology := range technologies {
+		result = append(result, technology.Id)
+	}
+	return result
+}
+
+func jsonEventIdsOf(events []*Event) []string {
+	result := make([]string, 0)
+	for _, event := range events {
+		result = append(result, event.Id)
+	}
+	return result
+}
+
+func jsonServiceOf(service *Service) JsonService {
+	result := JsonService{
+		Id:             service.Id,
+		Name:           service.Name,
+		Description:    service.Description,
+		State:          service.State.Id(),
+		Owner:          service.OwnerId,
+		Technologies:   jsonTechnologyIdsOf(service.Technologies),
+		DataStores:     make([]JsonDataStoreUse, 0),
+		Forms:          make([]JsonForm, 0),
+		Commands:       make([]JsonCommand, 0),
+		Interfaces:     make([]JsonInterface, 0),
+		Calls:          jsonCallsOf(service.Calls),
+		Publishes:      jsonEventIdsOf(service.PublishedEvents),
+		Subscribes:     jsonEventIdsOf(service.SubscribedEvents),
+		Repository:     service.Repository,
+		JsonExtensions: jsonExtensionsOf(&service.Extensions),
+	}
+	for _, use := range service.DataStores {
+		result.DataStores = append(result.DataStores, JsonDataStoreUse{
+			Database:    use.DatabaseId,
+			Queue:       use.QueueId,
+			Description: use.Description,
+			DataFlow:    use.DataFlow.String(),
+		})
+	}
+	for _, form := range service.Forms {
+		result.Forms = append(result.Forms, JsonForm{JsonEvolvableName{form.Id, form.Name, form.State.Id()}, form.OwnerId,
+			jsonExtensionsOf(&form.Extensions)})
+	}
+	for _, command := range service.Commands {
+		result.Commands = append(result.Commands, JsonCommand{Id: command.Id, Name: command.Name,
+			Description: command.Description, JsonExtensions: jsonExtensionsOf(&command.Extensions)})
+	}
+	for _, iface := range service.Interfaces {
+		result.Interfaces = append(result.Interfaces, JsonInterface{
+			Id:             iface.Id,
+			Name:           iface.Name,
+			Description:    iface.Description,
+			Type:           iface.Type,
+			Spec:           iface.Spec,
+			Version:        iface.Version,
+			Technologies:   jsonTechnologyIdsOf(iface.Technologies),
+			JsonExtensions: jsonExtensionsOf(&iface.Extensions),
+		})
+	}
+	return result
+}
+
+func jsonDataStoreOf(dataStore *DataStore) JsonDataStore {
+	return JsonDataStore{
+		Id:              dataStore.Id,
+		Name:            dataStore.Name,
+		Description:     dataStore.Description,
+		State:           dataStore.State.Id(),
+		Owner:           dataStore.OwnerId,
+		Technologies:    jsonTechnologyIdsOf(dataStore.Technologies),
+		ApiTechnologies: jsonTechnologyIdsOf(dataStore.ApiTechnologies),
+		JsonExtensions:  jsonExtensionsOf(&dataStore.Extensions),
+	}
+}
+
+func jsonDatabaseOf(database *Database) JsonDatabase {
+	result := JsonDatabase{JsonDataStore: jsonDataStoreOf(&database.DataStore), Views: make([]JsonEvolvableName, 0)}
+	for _, view := range database.Views {
+		result.Views = append(result.Views, JsonEvolvableName{view.Id, view.Name, view.State.Id()})
+	}
+	return result
+}
+
+func jsonTechnologyOf(technology *Technology) JsonTechnology {
+	return JsonTechnology{
+		Id:             technology.Id,
+		Name:           technology.Name,
+		Description:    technology.Description,
+		Quadrant:       technology.Quadrant.String(),
+		Ring:           technology.Ring.String(),
+		JsonExtensions: jsonExtensionsOf(&technology.Extensions),
+	}
+}
+
+func jsonTeamOf(team *Team) JsonTeam {
+	result := JsonTeam{Id: team.Id, Name: team.Name, Description: team.Description, Contact: team.Contact,
+		JsonExtensions: jsonExtensionsOf(&team.Extensions)}
+	if len(team.Links) > 0 {
+		result.Links = make(map[string]string)
+		for _, link := range team.Links {
+			result.Links[link.Name] = link.Url
+		}
+	}
+	return result
+}
+
+func jsonWorkflowOf(workflow *Workflow) JsonWorkflow {
+	result := JsonWorkflow{
+		Id:             workflow.Id,
+		Name:           workflow.Name,
+		Description:    workflow.Description,
+		TopLevel:       workflow.TopLevel,
+		Steps:          make([]JsonStep, 0),
+		JsonExtensions: jsonExtensionsOf(&workflow.Extensions),
+	}
+	for _, step := range workflow.Steps {
+		result.Steps = append(result.Steps, JsonStep{
+			Performer:      step.PerformerId,
+			Description:    step.Description,
+			Form:           step.FormId,
+			View:           step.View,
+			Command:        step.CommandId,
+			Event:          step.EventId,
+			Service:        step.ServiceId,
+			ExternalSystem: step.ExternalSystemId,
+		})
+	}
+	return result
+}
+
+func jsonEnvironmentOf(environment *Environment) JsonEnvironment {
+	return JsonEnvironment{
+		Id:              environment.Id,
+		Name:            environment.Name,
+		Description:     environment.Description,
+		DeploymentNodes: jsonDeploymentNodesOf(environment.DeploymentNodes),
+		JsonExtensions:  jsonExtensionsOf(&environment.Extensions),
+	}
+}
+
+func jsonDeploymentNodesOf(deploymentNodes []*DeploymentNode) []JsonDeploymentNode {
+	result := make([]JsonDeploymentNode, 0)
+	for _, deploymentNode := range deploymentNodes {
+		jsonDeploymentNode := JsonDeploymentNode{
+			Id:              deploymentNode.Id,
+			Name:            deploymentNode.Name,
+			Description:     deploymentNode.Description,
+			Technologies:    jsonTechnologyIdsOf(deploymentNode.Technologies),
+			DeploymentNodes: jsonDeploymentNodesOf(deploymentNode.DeploymentNodes),
+			Instances:       make([]JsonInstance, 0),
+			JsonExtensions:  jsonExtensionsOf(&deploymentNode.Extensions),
+		}
+		for _, instance := range deploymentNode.Instances {
+			jsonDeploymentNode.Instances = append(jsonDeploymentNode.Instances, JsonInstance{
+				Service:  instance.ServiceId,
+				Database: instance.DatabaseId,
+				Queue:    instance.QueueId,
+				Replicas: instance.Replicas,
+			})
+		}
+		result = append(result, jsonDeploymentNode)
+	}
+	return result
+}
+
+type jsonExporter struct {
+}
+
+func NewJsonExporter() TextExporter {
+	return jsonExporter{}
+}
+
+func (j jsonExporter) export(model ArchitectureModel, printer *Printer) error {
+	data, err := json.MarshalIndent(NewJsonModel(&model), "", "  ")
+	if err != nil {
+		return err
+	}
+	printer.PrintLn(string(data))
+	return nil
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const jsonDefinition = `version: 1.0.0
+
+system:
+  name: Shop
+
+personas:
+  customer:
+    uses:
+      - form: order
+        dataFlow: send
+
+teams:
+  sales:
+    name: Sales
+
+services:
+  api:
+    owner: sales
+    technologies: go
+    forms:
+      - order
+    commands:
+      - place
+    publishes:
+      - placed
+    calls:
+      - externalSystem: bank
+        dataFlow: send
+    dataStores:
+      - database: orders
+      - queue: events
+        dataFlow: send
+
+externalSystems:
+  bank:
+    type: payment
+
+databases:
+  orders:
+    views:
+      - orderList
+
+queues:
+  events: {}
+
+events:
+  placed:
+    queue: events
+
+technologies:
+  go:
+    name: Go
+    quadrant: languagesAndFrameworks
+
+workflows:
+  checkout:
+    steps:
+      - performer: customer
+        form: order
+      - performer: order
+        command: place
+      - performer: api
+        event: placed
+
+environments:
+  production:
+    deploymentNodes:
+      cluster:
+        instances:
+          - service: api
+            replicas: 2
+`
+
+func TestJsonModel(t *testing.T) {
+	model, issues := LintText(jsonDefinition)
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+	jsonModel := NewJsonModel(model)
+	// The styles are those of the default theme.
+	jsonModel.Styles = nil
+
+	data, err := json.MarshalIndent(jsonModel, "", "  ")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != expectedJson {
+		t.Errorf("Expected:\n%v\nbut got:\n%v", expectedJson, string(data))
+	}
+}
+
+// expectedJson pins the field names of the JSON encoding, and that references are encoded as IDs. Changing it requires
+// a new jsonFormatVersion.
+const expectedJson = `{
+  "formatVersion": "1.9",
+  "version": "1.0.0",
+  "system": {
+    "name": "Shop"
+  },
+  "personas": [
+    {
+      "id": "customer",
+      "name": "Customer",
+      "uses": [
+        {
+          "form": "order",
+          "dataFlow": "send"
+        }
+      ]
+    }
+  ],
+  "externalSystems": [
+    {
+      "id": "bank",
+      "name": "Bank",
+      "type": "payment",
+      "calls": []
+    }
+  ],
+  "services": [
+    {
+      "id": "api",
+      "name": "Api",
+      "state": "ok",
+      "owner": "sales",
+      "technologies": [
+        "go"
+      ],
+      "dataStores": [
+        {
+          "database": "orders",
+          "dataFlow": "bidirectional"
+        },
+        {
+          "queue": "events",
+          "dataFlow": "send"
+        }
+      ],
+      "forms": [
+        {
+          "id": "order",
+          "name": "order",
+          "state": "ok"
+        }
+      ],
+      "commands": [
+        {
+          "id": "place",
+          "name": "place"
+        }
+      ],
+      "interfaces": [],
+      "calls": [
+        {
+          "externalSystem": "bank",
+          "dataFlow": "send",
+          "technologies": []
+        }
+      ],
+      "publishes": [
+        "placed"
+      ],
+      "subscribes": []
+    }
+  ],
+  "databases": [
+    {
+      "id": "orders",
+      "name": "Orders",
+      "state": "ok",
+      "technologies": [],
+      "apiTechnologies": [],
+      "views": [
+        {
+          "id": "orderList",
+          "name": "orderList",
+          "state": "ok"
+        }
+      ]
+    }
+  ],
+  "queues": [
+    {
+      "id": "events",
+      "name": "Events",
+      "state": "ok",
+      "technologies": [],
+      "apiTechnologies": []
+    }
+  ],
+  "technologies": [
+    {
+      "id": "go",
+      "name": "Go",
+      "quadrant": "languagesAndFrameworks",
+      "ring": "adopt"
+    }
+  ],
+  "workflows": [
+    {
+      "id": "checkout",
+      "name": "Checkout",
+      "topLevel": true,
+      "steps": [
+        {
+          "performer": "customer",
+          "form": "order"
+        },
+        {
+          "performer": "order",
+          "command": "place"
+        },
+        {
+          "performer": "api",
+          "event": "placed"
+        }
+      ]
+    }
+  ],
+  "styles": null,
+  "teams": [
+    {
+      "id": "sales",
+      "name": "Sales"
+    }
+  ],
+  "events": [
+    {
+      "id": "placed",
+      "name": "Placed",
+      "queue": "events"
+    }
+  ],
+  "environments": [
+    {
+      "id": "production",
+      "name": "Production",
+      "deploymentNodes": [
+        {
+          "id": "cluster",
+          "name": "Cluster",
+          "technologies": [],
+          "deploymentNodes": [],
+          "instances": [
+            {
+              "service": "api",
+              "replicas": 2
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}`
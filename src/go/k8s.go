@@ -0,0 +1,264 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// kubernetesObject is the part of a Kubernetes object that matters for importing.
+type kubernetesObject struct {
+	Kind     string `yaml:"kind"`
+	Metadata struct {
+		Name      string `yaml:"name"`
+		Namespace string `yaml:"namespace"`
+	} `yaml:"metadata"`
+	Spec yaml.Node `yaml:"spec"`
+}
+
+type kubernetesWorkloadSpec struct {
+	Template struct {
+		Metadata struct {
+			Labels map[string]string `yaml:"labels"`
+		} `yaml:"metadata"`
+		Spec struct {
+			Containers []struct {
+				Image string `yaml:"image"`
+			} `yaml:"containers"`
+		} `yaml:"spec"`
+	} `yaml:"template"`
+}
+
+type kubernetesServiceSpec struct {
+	Selector map[string]string `yaml:"selector"`
+}
+
+type kubernetesIngressBackend struct {
+	Service struct {
+		Name string `yaml:"name"`
+	} `yaml:"service"`
+	// ServiceName is how backends referred to services before networking.k8s.io/v1.
+	ServiceName string `yaml:"serviceName"`
+}
+
+type kubernetesIngressSpec struct {
+	DefaultBackend *kubernetesIngressBackend `yaml:"defaultBackend"`
+	Backend        *kubernetesIngressBackend `yaml:"backend"`
+	Rules          []struct {
+		Http struct {
+			Paths []struct {
+				Backend kubernetesIngressBackend `yaml:"backend"`
+			} `yaml:"paths"`
+		} `yaml:"http"`
+	} `yaml:"rules"`
+}
+
+type kubernetesPodSelector struct {
+	MatchLabels map[string]string `yaml:"matchLabels"`
+}
+
+type kubernetesNetworkPolicySpec struct {
+	PodSelector kubernetesPodSelector `yaml:"podSelector"`
+	Egress      []struct {
+		To []struct {
+			PodSelector *kubernetesPodSelector `yaml:"podSelector"`
+		} `yaml:"to"`
+	} `yaml:"egress"`
+}
+
+// kubernetesWorkload is a Deployment or StatefulSet, with the labels of its pods.
+type kubernetesWorkload struct {
+	id        string
+	namespace string
+	labels    map[string]string
+}
+
+// ImportKubernetes creates a draft model from the Kubernetes manifests in a directory and its subdirectories.
+func ImportKubernetes(directory string) (*ImportedModel, error) {
+	manifests := make([][]byte, 0)
+	err := filepath.WalkDir(directory, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		extension := filepath.Ext(path)
+		if entry.IsDir() || (extension != ".yaml" && extension != ".yml") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		manifests = append(manifests, data)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return importKubernetes(manifests...)
+}
+
+// importKubernetes turns Deployments into services and StatefulSets into databases or queues when their image is known,
+// and into services otherwise. An Ingress becomes an external system that calls the workloads behind its backends, and
+// the egress rules of a NetworkPolicy become calls or data store uses between the workloads it selects. Elements are
+// tagged with their namespace.
+func importKubernetes(manifests ...[]byte) (*ImportedModel, error) {
+	objects := make([]kubernetesObject, 0)
+	for _, manifest := range manifests {
+		decoder := yaml.NewDecoder(bytes.NewReader(manifest))
+		for {
+			var object kubernetesObject
+			err := decoder.Decode(&object)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid Kubernetes manifest: %v", err)
+			}
+			if object.Metadata.Namespace == "" {
+				object.Metadata.Namespace = "default"
+			}
+			objects = append(objects, object)
+		}
+	}
+	sort.SliceStable(objects, func(i, j int) bool {
+		return objects[i].Metadata.Name < objects[j].Metadata.Name
+	})
+	result := NewImportedModel()
+	workloads := make([]kubernetesWorkload, 0)
+	for _, object := range objects {
+		if object.Kind != "Deployment" && object.Kind != "StatefulSet" {
+			continue
+		}
+		var spec kubernetesWorkloadSpec
+		if err := object.Spec.Decode(&spec); err != nil {
+			return nil, fmt.Errorf("invalid %v '%v': %v", object.Kind, object.Metadata.Name, err)
+		}
+		image := ""
+		if containers := spec.Template.Spec.Containers; len(containers) > 0 {
+			image = containers[0].Image
+		}
+		if object.Kind == "Deployment" {
+			// Deployments are stateless, so whatever image they run, they're not a data store.
+			image = ""
+		}
+		id := object.Metadata.Name
+		result.addContainer(id, image)
+		result.tag(id, object.Metadata.Namespace)
+		workloads = append(workloads, kubernetesWorkload{id, object.Metadata.Namespace, spec.Template.Metadata.Labels})
+	}
+	selectorsByService := make(map[string]map[string]string)
+	for _, object := range objects {
+		if object.Kind != "Service" {
+			continue
+		}
+		var spec kubernetesServiceSpec
+		if err := object.Spec.Decode(&spec); err != nil {
+			return nil, fmt.Errorf("invalid Service '%v': %v", object.Metadata.Name, err)
+		}
+		selectorsByService[object.Metadata.Namespace+"/"+object.Metadata.Name] = spec.Selector
+	}
+	for _, object := range objects {
+		var err error
+		switch object.Kind {
+		case "Ingress":
+			err = importIngress(object, selectorsByService, workloads, result)
+		case "NetworkPolicy":
+			err = importNetworkPolicy(object, workloads, result)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func importIngress(object kubernetesObject, selectorsByService map[string]map[string]string,
+	workloads []kubernetesWorkload, result *ImportedModel) error {
+	var spec kubernetesIngressSpec
+	if err := object.Spec.Decode(&spec); err != nil {
+		return fmt.Errorf("invalid Ingress '%v': %v", object.Metadata.Name, err)
+	}
+	backends := make([]*kubernetesIngressBackend, 0)
+	for _, backend := range []*kubernetesIngressBackend{spec.DefaultBackend, spec.Backend} {
+		if backend != nil {
+			backends = append(backends, backend)
+		}
+	}
+	for _, rule := range spec.Rules {
+		for index := range rule.Http.Paths {
+			backends = append(backends, &rule.Http.Paths[index].Backend)
+		}
+	}
+	id := object.Metadata.Name
+	if _, found := result.ExternalSystems[id]; !found && result.kindOf(id) != "" {
+		id += "-ingress"
+	}
+	result.ExternalSystems[id] = &ImportedExternalSystem{Type: "ingress"}
+	result.tag(id, object.Metadata.Namespace)
+	for _, backend := range backends {
+		serviceName := backend.Service.Name
+		if serviceName == "" {
+			serviceName = backend.ServiceName
+		}
+		selector, found := selectorsByService[object.Metadata.Namespace+"/"+serviceName]
+		if !found || len(selector) == 0 {
+			continue
+		}
+		for _, workload := range selectedWorkloads(workloads, object.Metadata.Namespace, selector) {
+			result.use(id, workload.id, fmt.Sprintf("Imported from ingress %v", object.Metadata.Name))
+		}
+	}
+	return nil
+}
+
+func importNetworkPolicy(object kubernetesObject, workloads []kubernetesWorkload, result *ImportedModel) error {
+	var spec kubernetesNetworkPolicySpec
+	if err := object.Spec.Decode(&spec); err != nil {
+		return fmt.Errorf("invalid NetworkPolicy '%v': %v", object.Metadata.Name, err)
+	}
+	description := fmt.Sprintf("Imported from network policy %v", object.Metadata.Name)
+	for _, from := range selectedWorkloads(workloads, object.Metadata.Namespace, spec.PodSelector.MatchLabels) {
+		for _, egress := range spec.Egress {
+			for _, peer := range egress.To {
+				// Peers without a pod selector allow traffic to IP blocks or whole namespaces, which says nothing
+				// about which workloads are called.
+				if peer.PodSelector == nil {
+					continue
+				}
+				for _, to := range selectedWorkloads(workloads, object.Metadata.Namespace, peer.PodSelector.MatchLabels) {
+					result.use(from.id, to.id, description)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// selectedWorkloads returns the workloads in a namespace whose pods have all the labels of a selector. An empty
+// selector selects all workloads in the namespace.
+func selectedWorkloads(workloads []kubernetesWorkload, namespace string,
+	selector map[string]string) []kubernetesWorkload {
+	result := make([]kubernetesWorkload, 0)
+	for _, workload := range workloads {
+		if workload.namespace != namespace {
+			continue
+		}
+		selected := true
+		for key, value := range selector {
+			if workload.labels[key] != value {
+				selected = false
+				break
+			}
+		}
+		if selected {
+			result = append(result, workload)
+		}
+	}
+	return result
+}
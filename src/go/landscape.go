@@ -0,0 +1,270 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Landscape is a set of models whose systems call each other. An external system in one model can stand for the
+// system of another model by referring to that system's ID.
+type Landscape struct {
+	Models []*ArchitectureModel
+	// links maps external systems to the models whose systems they stand for.
+	links map[*ExternalSystem]*ArchitectureModel
+}
+
+// LintLandscape lints the given model files and links them into a landscape. When any of the files can't be read or
+// refers to an unknown system, the landscape is nil and the issues are returned by file name.
+func LintLandscape(fileNames []string) (*Landscape, map[string][]Issue) {
+	models := make([]*ArchitectureModel, 0, len(fileNames))
+	for _, fileName := range fileNames {
+		model, issues := LintFile(fileName)
+		if model == nil {
+			return nil, map[string][]Issue{fileName: issues}
+		}
+		models = append(models, model)
+	}
+	landscape, issuesByModel := newLandscape(models)
+	result := make(map[string][]Issue)
+	for index, issues := range issuesByModel {
+		if len(issues) > 0 {
+			result[fileNames[index]] = issues
+		}
+	}
+	if len(result) > 0 {
+		return nil, result
+	}
+	return landscape, result
+}
+
+// newLandscape links the external systems of the models to the models whose systems they stand for. It returns the
+// issues for each model.
+func newLandscape(models []*ArchitectureModel) (*Landscape, [][]Issue) {
+	result := &Landscape{models, make(map[*ExternalSystem]*ArchitectureModel)}
+	issues := make([][]Issue, len(models))
+	modelsById := make(map[string]*ArchitectureModel)
+	for index, model := range models {
+		issues[index] = make([]Issue, 0)
+		if _, found := modelsById[model.System.Id]; found {
+			issues[index] = append(issues[index], *FileError(fmt.Sprintf("System '%v' is defined in more than one model",
+				model.System.Id)))
+		} else {
+			modelsById[model.System.Id] = model
+		}
+	}
+	for index, model := range models {
+		for _, externalSystem := range model.ExternalSystems {
+			if externalSystem.SystemId == "" {
+				continue
+			}
+			linked, found := modelsById[externalSystem.SystemId]
+			if found && linked != model {
+				result.links[externalSystem] = linked
+			} else if !found {
+				issues[index] = append(issues[index], *NodeError(fmt.Sprintf("Unknown system '%v'",
+					externalSystem.SystemId), externalSystem.node))
+			} else {
+				issues[index] = append(issues[index], *NodeError(fmt.Sprintf("External system '%v' can't be its own system",
+					externalSystem.Id), externalSystem.node))
+			}
+		}
+	}
+	return result, issues
+}
+
+// findModelById returns the model whose system has the given ID.
+func (l *Landscape) findModelById(id string) (*ArchitectureModel, bool) {
+	for _, model := range l.Models {
+		if model.System.Id == id {
+			return model, true
+		}
+	}
+	return nil, false
+}
+
+// findElement finds an element by a reference like billing/service:api, where billing is the ID of a system. Without
+// a system, the element must be found in exactly one model.
+func (l *Landscape) findElement(ref string) (*ArchitectureModel, interface{}, error) {
+	systemId, elementRef, found := strings.Cut(ref, "/")
+	if found {
+		model, found := l.findModelById(systemId)
+		if !found {
+			return nil, nil, fmt.Errorf("unknown system '%v'", systemId)
+		}
+		element, err := model.findElement(elementRef)
+		return model, element, err
+	}
+	var result *ArchitectureModel
+	var element interface{}
+	for _, model := range l.Models {
+		candidate, err := model.findElement(ref)
+		if err != nil {
+			continue
+		}
+		if result != nil {
+			return nil, nil, fmt.Errorf("ambiguous element '%v': use <system>/%v, e.g. %v/%v", ref, ref,
+				result.System.Id, ref)
+		}
+		result = model
+		element = candidate
+	}
+	if result == nil {
+		return nil, nil, fmt.Errorf("unknown element '%v'", ref)
+	}
+	return result, element, nil
+}
+
+// nodeIdOf returns the ID of the landscape node that represents an element of a model: personas and unlinked external
+// systems represent themselves, linked external systems the system they stand for, and everything else the system of
+// the model.
+func (l *Landscape) nodeIdOf(model *ArchitectureModel, element interface{}) string {
+	switch e := element.(type) {
+	case *Persona:
+		return e.Id
+	case *ExternalSystem:
+		if linked, found := l.links[e]; found {
+			return linked.System.Id
+		}
+		return e.Id
+	default:
+		return model.System.Id
+	}
+}
+
+// usages returns the relationships between the nodes of the landscape. Relationships between elements that the same
+// nodes represent are combined, keeping the description of the first one.
+func (l *Landscape) usages() []usage {
+	result := make([]usage, 0)
+	found := make(map[string]bool)
+	for _, model := range l.Models {
+		for _, relationship := range model.Relationships() {
+			from := l.nodeIdOf(model, relationship.From)
+			to := l.nodeIdOf(model, relationship.To)
+			key := from + " -> " + to
+			if from == to || found[key] {
+				continue
+			}
+			found[key] = true
+			_, byPersona := relationship.From.(*Persona)
+			result = append(result, usage{user: from, used: to, description: relationship.Description, byPersona: byPersona,
+				dataFlow: Bidirectional})
+		}
+	}
+	return result
+}
+
+// PrintLandscape prints the landscape as a Structurizr workspace with a system landscape view. Each model's system
+// becomes a software system, and personas and external systems that occur in multiple models are printed once.
+func PrintLandscape(landscape *Landscape, printer *Printer) {
+	c := c4Exporter{}
+	printer.PrintLn("workspace {")
+	printer.Start()
+	printer.PrintLn("model {")
+	printer.Start()
+	printed := make(map[string]bool)
+	for _, model := range landscape.Models {
+		for _, persona := range model.Personas {
+			if printed[persona.Id] {
+				continue
+			}
+			printed[persona.Id] = true
+			printer.PrintLn(persona.Id, " = person \"", persona.Name, "\" {")
+			printer.Start()
+			c.printDescription(persona, printer)
+			c.printTags(&persona.Extensions, printer)
+			c.printProperties(&persona.Extensions, printer)
+			printer.End()
+			printer.PrintLn("}")
+		}
+	}
+	for _, model := range landscape.Models {
+		printer.PrintLn(model.System.Id, " = softwareSystem \"", model.System.Name, "\" {")
+		printer.Start()
+		printer.PrintLn("tags \"", c4TagsByKind["system"], "\"")
+		printer.End()
+		printer.PrintLn("}")
+		printed[model.System.Id] = true
+	}
+	for _, model := range landscape.Models {
+		for _, externalSystem := range model.ExternalSystems {
+			if _, linked := landscape.links[externalSystem]; linked || printed[externalSystem.Id] {
+				continue
+			}
+			printed[externalSystem.Id] = true
+			c.printExternalSystemsOf([]*ExternalSystem{externalSystem}, printer)
+		}
+	}
+	c.printRelationships(landscape.usages(), printer)
+	printer.End()
+	printer.PrintLn("}")
+	printer.PrintLn("views {")
+	printer.Start()
+	printer.PrintLn("systemLandscape \"landscape\" {")
+	printer.Start()
+	printer.PrintLn("include *")
+	printer.PrintLn("autolayout")
+	printer.End()
+	printer.PrintLn("}")
+	if len(landscape.Models) > 0 {
+		c.printStyles(landscape.Models[0].theme(), printer)
+	}
+	printer.End()
+	printer.PrintLn("}")
+	printer.End()
+	printer.PrintLn("}")
+}
+
+// SystemImpact is the impact of an element on one of the systems in a landscape.
+type SystemImpact struct {
+	Model *ArchitectureModel
+	*Impact
+}
+
+// AnalyzeLandscapeImpact analyzes the impact of an element on its own system, and then on the systems that call that
+// system. A calling system is affected when the external systems that stand for it in the analyzed system are
+// affected, or, when the analyzed system doesn't know about its caller, whenever the analyzed system is affected.
+// Within a calling system, the impact is that of the external system that stands for the analyzed system.
+func AnalyzeLandscapeImpact(landscape *Landscape, model *ArchitectureModel, element interface{}) []*SystemImpact {
+	result := []*SystemImpact{{model, AnalyzeImpact(model, element)}}
+	analyzed := map[*ArchitectureModel]bool{model: true}
+	for index := 0; index < len(result); index++ {
+		current := result[index]
+		for _, caller := range landscape.Models {
+			if analyzed[caller] || !landscape.isAffectedCaller(current, caller) {
+				continue
+			}
+			for _, externalSystem := range caller.ExternalSystems {
+				if landscape.links[externalSystem] == current.Model {
+					analyzed[caller] = true
+					result = append(result, &SystemImpact{caller, AnalyzeImpact(caller, externalSystem)})
+					break
+				}
+			}
+		}
+	}
+	return result
+}
+
+func (l *Landscape) isAffectedCaller(impact *SystemImpact, caller *ArchitectureModel) bool {
+	known := false
+	for _, externalSystem := range impact.Model.ExternalSystems {
+		if l.links[externalSystem] == caller {
+			known = true
+			if impact.isAffected(externalSystem) {
+				return true
+			}
+		}
+	}
+	return !known
+}
+
+// PrintLandscapeImpact prints the impact on each affected system.
+func PrintLandscapeImpact(impacts []*SystemImpact, printer *Printer) {
+	for index, impact := range impacts {
+		if index > 0 {
+			printer.NewLine()
+		}
+		printer.PrintLn("System ", impact.Model.System.Id, " (", impact.Model.System.Name, ")")
+		impact.Print(printer)
+	}
+}
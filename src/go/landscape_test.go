@@ -0,0 +1,160 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const billingDefinition = `system:
+  id: billing
+  name: Billing
+
+services:
+  invoices:
+    dataStores:
+      - database: invoices
+  reports: {}
+
+databases:
+  invoices: {}
+`
+
+const shopDefinition = `system:
+  id: shop
+  name: Shop
+
+personas:
+  customer:
+    uses:
+      - form: cart
+
+externalSystems:
+  billing:
+    system: billing
+  payments:
+    name: Payment provider
+
+services:
+  checkout:
+    forms:
+      - cart
+    calls:
+      - externalSystem: billing
+        description: Creates invoices
+      - externalSystem: payments
+`
+
+func lintLandscape(t *testing.T, definitions ...string) (*Landscape, [][]Issue) {
+	models := make([]*ArchitectureModel, 0)
+	for _, definition := range definitions {
+		model, issues := LintText(definition)
+		if hasIssue(issues, hasError("")) {
+			t.Fatalf("Invalid model: %+v", issues)
+		}
+		models = append(models, model)
+	}
+	return newLandscape(models)
+}
+
+func TestLandscape(t *testing.T) {
+	landscape, issues := lintLandscape(t, billingDefinition, shopDefinition)
+	if len(issues[0])+len(issues[1]) > 0 {
+		t.Fatalf("Invalid landscape: %+v", issues)
+	}
+
+	billing := landscape.Models[0]
+	shop := landscape.Models[1]
+	if billing.System.Id != "billing" || landscape.links[shop.ExternalSystems[0]] != billing ||
+		len(landscape.links) != 1 {
+		t.Errorf("External system not linked: %+v", landscape.links)
+	}
+}
+
+func TestInvalidLandscape(t *testing.T) {
+	_, issues := lintLandscape(t, shopDefinition)
+	if !hasIssue(issues[0], hasError("Unknown system 'billing'")) {
+		t.Errorf("Missing error for unknown system: %+v", issues)
+	}
+
+	_, issues = lintLandscape(t, billingDefinition, billingDefinition)
+	if !hasIssue(issues[1], hasError("System 'billing' is defined in more than one model")) {
+		t.Errorf("Missing error for duplicate system: %+v", issues)
+	}
+
+	_, issues = lintLandscape(t, strings.Replace(shopDefinition, "system: billing", "system: shop", 1))
+	if !hasIssue(issues[0], hasError("External system 'billing' can't be its own system")) {
+		t.Errorf("Missing error for self reference: %+v", issues)
+	}
+}
+
+func TestPrintLandscape(t *testing.T) {
+	landscape, _ := lintLandscape(t, billingDefinition, shopDefinition)
+	printer := NewPrinter()
+
+	PrintLandscape(landscape, printer)
+
+	expected := `workspace {
+    model {
+        customer = person "Customer" {
+        }
+        billing = softwareSystem "Billing" {
+            tags "System of Interest"
+        }
+        shop = softwareSystem "Shop" {
+            tags "System of Interest"
+        }
+        payments = softwareSystem "Payment provider" {
+            tags "External System"
+        }
+        customer -> shop {
+            tags "Using"
+        }
+        shop -> billing "Creates invoices"
+        shop -> payments
+    }
+    views {
+        systemLandscape "landscape" {
+            include *
+            autolayout
+        }
+`
+	if !strings.HasPrefix(printer.String(), expected) {
+		t.Errorf("Expected:\n%v\nbut got:\n%v", expected, printer.String())
+	}
+}
+
+func TestLandscapeImpact(t *testing.T) {
+	landscape, _ := lintLandscape(t, billingDefinition, shopDefinition)
+	model, database, err := landscape.findElement("billing/database:invoices")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	impacts := AnalyzeLandscapeImpact(landscape, model, database)
+
+	if len(impacts) != 2 || impacts[0].Model != landscape.Models[0] || impacts[1].Model != landscape.Models[1] {
+		t.Fatalf("Invalid impacts: %+v", impacts)
+	}
+	checkout, _ := impacts[1].Model.findServiceById("checkout")
+	if !impacts[1].isAffected(checkout) || len(impacts[1].Personas) != 1 {
+		t.Errorf("Caller isn't affected: %+v", impacts[1].Affected)
+	}
+}
+
+func TestLandscapeImpactOnKnownCallers(t *testing.T) {
+	billing := billingDefinition + `
+externalSystems:
+  shop:
+    system: shop
+    calls:
+      - service: invoices
+`
+	landscape, _ := lintLandscape(t, billing, shopDefinition)
+	model, reports, _ := landscape.findElement("service:reports")
+
+	impacts := AnalyzeLandscapeImpact(landscape, model, reports)
+
+	if len(impacts) != 1 {
+		t.Errorf("Caller that doesn't use the element is affected: %+v", impacts)
+	}
+}
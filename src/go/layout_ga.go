@@ -1,8 +1,10 @@
 package main
 
 import (
+	"image"
 	"math"
 	"math/rand"
+	"sort"
 )
 
 func NewEvolutionaryLayoutEngine() LayoutEngine {
@@ -26,9 +28,15 @@ const (
 	weightSymmetricConnectors = 0.15
 	weightEmptyRowsAndColumns = 0.3
 	weightNodeTypes           = 0.2
+
+	// Number of mini-grid cells along each side of a grid cell
+	layoutCellSize = 12
 )
 
 func (e evolutionaryLayoutEngine) layOut(diagram *Diagram) *DiagramLayout {
+	if len(diagram.Shapes) == 0 {
+		return &DiagramLayout{make(map[*Shape]image.Rectangle), make(map[*Connection][]image.Point)}
+	}
 	size := calcGridSize(diagram)
 	ga := NewGeneticAlgorithm[*DiagramGene](
 		layoutGaPoolSize,
@@ -48,9 +56,60 @@ func (e evolutionaryLayoutEngine) layOut(diagram *Diagram) *DiagramLayout {
 	return toLayout(ga.best())
 }
 
+// toLayout places each shape in the middle of its grid cell, in mini-grid coordinates, after removing the rows and
+// columns that have no shapes.
 func toLayout(genome *Genome[*DiagramGene]) *DiagramLayout {
-	// TODO: Implement
-	return nil
+	result := &DiagramLayout{make(map[*Shape]image.Rectangle), make(map[*Connection][]image.Point)}
+	columns, rows := compactedColumnsAndRows(genome)
+	for _, gene := range genome.Genes {
+		if gene.isNode() {
+			shape := gene.shape()
+			cell := gene.cell()
+			x := columns[cell.X]*layoutCellSize + (layoutCellSize-shape.Size.Width)/2
+			y := rows[cell.Y]*layoutCellSize + (layoutCellSize-shape.Size.Height)/2
+			result.Shapes[shape] = image.Rect(x, y, x+shape.Size.Width, y+shape.Size.Height)
+		}
+	}
+	for _, gene := range genome.Genes {
+		if gene.isEdge() {
+			// TODO: Follow the path of the edge, once mutation introduces bends
+			connection := gene.connection()
+			result.Connections[connection] = []image.Point{centerOf(result.Shapes[connection.Start]),
+				centerOf(result.Shapes[connection.End])}
+		}
+	}
+	return result
+}
+
+// compactedColumnsAndRows maps the columns and rows of the grid that have shapes to consecutive indexes.
+func compactedColumnsAndRows(genome *Genome[*DiagramGene]) (map[int]int, map[int]int) {
+	usedColumns := make(map[int]bool)
+	usedRows := make(map[int]bool)
+	for _, gene := range genome.Genes {
+		if gene.isNode() {
+			cell := gene.cell()
+			usedColumns[cell.X] = true
+			usedRows[cell.Y] = true
+		}
+	}
+	return compacted(usedColumns), compacted(usedRows)
+}
+
+func compacted(used map[int]bool) map[int]int {
+	indexes := make([]int, 0, len(used))
+	for index := range used {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	result := make(map[int]int)
+	for _, index := range indexes {
+		result[index] = len(result)
+	}
+	return result
+}
+
+func centerOf(rectangle image.Rectangle) image.Point {
+	return rectangle.Min.Add(rectangle.Max).Div(2)
 }
 
 func calcGridSize(diagram *Diagram) Size {
@@ -74,15 +133,19 @@ func mutateNode(gene *DiagramGene, genome *Genome[*DiagramGene]) *DiagramGene {
 }
 
 func randomEmptyPositionIn(genome *Genome[*DiagramGene]) int {
-	max := genome.Genes[0].context.size.area()
-	options := zeroTo(max)
+	occupied := make(map[int]bool)
 	for _, gene := range genome.Genes {
 		if gene.isNode() {
-			max--
-			options[gene.gridPosition] = options[max]
+			occupied[gene.gridPosition] = true
 		}
 	}
-	return options[randomInt(max)]
+	options := make([]int, 0)
+	for position := 0; position < genome.Genes[0].context.size.area(); position++ {
+		if !occupied[position] {
+			options = append(options, position)
+		}
+	}
+	return options[randomInt(len(options))]
 }
 
 func randomInt(max int) int {
@@ -119,14 +182,38 @@ func partialFitnessFunctions() []PartialFitnessFunction[*DiagramGene] {
 	}
 }
 
+// calcEdgeCrossings returns the ratio of pairs of edges that don't cross. Edges that share a shape can't cross.
 func calcEdgeCrossings(genome *Genome[*DiagramGene]) Fitness {
-	// TODO: Implement
-	return 0.0
+	return calcEdgePairs(genome, func(edge1 segment, edge2 segment) bool {
+		return edge1.crosses(edge2)
+	})
 }
 
+// calcEdgeOverlaps returns the ratio of pairs of edges that don't overlap, i.e. that don't run along the same line for
+// part of the way.
 func calcEdgeOverlaps(genome *Genome[*DiagramGene]) Fitness {
-	// TODO: Implement
-	return 0.0
+	return calcEdgePairs(genome, func(edge1 segment, edge2 segment) bool {
+		return edge1.overlaps(edge2)
+	})
+}
+
+// calcEdgePairs returns the ratio of pairs of edges for which the given predicate doesn't hold.
+// TODO: Use the paths of the edges, once mutation introduces bends
+func calcEdgePairs(genome *Genome[*DiagramGene], predicate func(segment, segment) bool) Fitness {
+	edges := edgesOf(genome)
+	pairs := len(edges) * (len(edges) - 1) / 2
+	if pairs == 0 {
+		return 1.0
+	}
+	count := 0
+	for i := 0; i < len(edges); i++ {
+		for j := i + 1; j < len(edges); j++ {
+			if predicate(edges[i], edges[j]) {
+				count++
+			}
+		}
+	}
+	return 1.0 - Fitness(count)/Fitness(pairs)
 }
 
 func calcSymmetricConnectors(genome *Genome[*DiagramGene]) Fitness {
@@ -134,9 +221,11 @@ func calcSymmetricConnectors(genome *Genome[*DiagramGene]) Fitness {
 	return 0.0
 }
 
+// calcEmptyRowsAndColumns returns the ratio of rows and columns in the grid that have no shapes.
 func calcEmptyRowsAndColumns(genome *Genome[*DiagramGene]) Fitness {
-	// TODO: Implement
-	return 0.0
+	size := genome.Genes[0].context.size
+	columns, rows := compactedColumnsAndRows(genome)
+	return 1.0 - Fitness(len(columns)+len(rows))/Fitness(size.Width+size.Height)
 }
 
 func calcNodeTypes(genome *Genome[*DiagramGene]) Fitness {
@@ -144,6 +233,71 @@ func calcNodeTypes(genome *Genome[*DiagramGene]) Fitness {
 	return 0.0
 }
 
+// segment is a straight line between the centers of two grid cells.
+type segment struct {
+	from image.Point
+	to   image.Point
+}
+
+// edgesOf returns the edges of a genome as straight lines between the cells of the shapes they connect, except for
+// edges that start and end at the same shape.
+func edgesOf(genome *Genome[*DiagramGene]) []segment {
+	cells := make(map[*Shape]image.Point)
+	for _, gene := range genome.Genes {
+		if gene.isNode() {
+			cells[gene.shape()] = gene.cell()
+		}
+	}
+	result := make([]segment, 0)
+	for _, gene := range genome.Genes {
+		if gene.isEdge() {
+			connection := gene.connection()
+			if connection.Start != connection.End {
+				result = append(result, segment{cells[connection.Start], cells[connection.End]})
+			}
+		}
+	}
+	return result
+}
+
+func (s segment) crosses(other segment) bool {
+	return orientation(s.from, s.to, other.from)*orientation(s.from, s.to, other.to) < 0 &&
+		orientation(other.from, other.to, s.from)*orientation(other.from, other.to, s.to) < 0
+}
+
+func (s segment) overlaps(other segment) bool {
+	if orientation(s.from, s.to, other.from) != 0 || orientation(s.from, s.to, other.to) != 0 {
+		return false
+	}
+	if s.from.X == s.to.X {
+		return intervalsOverlap(s.from.Y, s.to.Y, other.from.Y, other.to.Y)
+	}
+	return intervalsOverlap(s.from.X, s.to.X, other.from.X, other.to.X)
+}
+
+// orientation returns whether the point c lies to the left (1) or the right (-1) of the line from a to b, or on it (0).
+func orientation(a image.Point, b image.Point, c image.Point) int {
+	product := (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+	switch {
+	case product > 0:
+		return 1
+	case product < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+func intervalsOverlap(start1 int, end1 int, start2 int, end2 int) bool {
+	if start1 > end1 {
+		start1, end1 = end1, start1
+	}
+	if start2 > end2 {
+		start2, end2 = end2, start2
+	}
+	return start1 < end2 && start2 < end1
+}
+
 type connector struct {
 	side  Side
 	index int
@@ -179,6 +333,11 @@ func (d *DiagramGene) shape() *Shape {
 	return d.context.diagram.Shapes[d.shapeIndex]
 }
 
+// cell returns the column and row in the grid of the shape of a node gene.
+func (d *DiagramGene) cell() image.Point {
+	return image.Point{X: d.gridPosition % d.context.size.Width, Y: d.gridPosition / d.context.size.Width}
+}
+
 func (d *DiagramGene) isEdge() bool {
 	return d.connectionIndex >= 0
 }
@@ -208,7 +367,7 @@ func createDiagramGenome(size Size, diagram *Diagram) *Genome[*DiagramGene] {
 		connection := diagram.Connections[index]
 		path := defaultPathFor(connection)
 		connectionGene := DiagramGene{context, -1, -1, index, path}
-		genes[index] = &connectionGene
+		genes[len(diagram.Shapes)+index] = &connectionGene
 	}
 	return &Genome[*DiagramGene]{genes, 0.0}
 }
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"image"
+	"math"
+	"testing"
+)
+
+func TestEvolutionaryLayout(t *testing.T) {
+	shapes := make([]*Shape, 0)
+	for _, id := range []string{"dev", "web", "api", "db", "mail"} {
+		shapes = append(shapes, &Shape{Id: id, Text: id, Size: Size{6, 3}})
+	}
+	connections := []*Connection{
+		{Start: shapes[0], End: shapes[1]},
+		{Start: shapes[1], End: shapes[2]},
+		{Start: shapes[2], End: shapes[3]},
+		{Start: shapes[2], End: shapes[4]},
+	}
+	diagram := &Diagram{shapes, connections}
+
+	layout := NewEvolutionaryLayoutEngine().layOut(diagram)
+
+	if len(layout.Shapes) != len(shapes) {
+		t.Fatalf("Expected %v shapes, but got %v", len(shapes), len(layout.Shapes))
+	}
+	for _, shape := range shapes {
+		rectangle := layout.Shapes[shape]
+		if rectangle.Dx() != shape.Size.Width || rectangle.Dy() != shape.Size.Height {
+			t.Errorf("Shape %v has size %v", shape.Id, rectangle.Size())
+		}
+		for _, other := range shapes {
+			if other != shape && rectangle.Overlaps(layout.Shapes[other]) {
+				t.Errorf("Shape %v overlaps %v", shape.Id, other.Id)
+			}
+		}
+	}
+	for _, connection := range connections {
+		points := layout.Connections[connection]
+		if len(points) != 2 || !points[0].In(layout.Shapes[connection.Start]) ||
+			!points[1].In(layout.Shapes[connection.End]) {
+			t.Errorf("Connection from %v to %v isn't connected: %v", connection.Start.Id, connection.End.Id, points)
+		}
+	}
+}
+
+func TestEdgeCrossings(t *testing.T) {
+	crossing := segment{image.Pt(0, 0), image.Pt(2, 2)}
+	if !crossing.crosses(segment{image.Pt(0, 2), image.Pt(2, 0)}) {
+		t.Errorf("Diagonals should cross")
+	}
+	if crossing.crosses(segment{image.Pt(2, 2), image.Pt(4, 0)}) {
+		t.Errorf("Edges that share an end shouldn't cross")
+	}
+	if !crossing.overlaps(segment{image.Pt(1, 1), image.Pt(3, 3)}) {
+		t.Errorf("Edges on the same line should overlap")
+	}
+	if crossing.overlaps(segment{image.Pt(2, 2), image.Pt(3, 3)}) {
+		t.Errorf("Edges that only share an end shouldn't overlap")
+	}
+}
+
+// newGenomeWithShapesAt creates a genome for a diagram on a 3x3 grid with the shapes at the given positions, where the
+// first shape is connected to the second, and the third to the fourth.
+func newGenomeWithShapesAt(positions ...int) *Genome[*DiagramGene] {
+	shapes := make([]*Shape, 0)
+	for range positions {
+		shapes = append(shapes, &Shape{Size: Size{6, 3}})
+	}
+	connections := make([]*Connection, 0)
+	for index := 0; index+1 < len(shapes); index += 2 {
+		connections = append(connections, &Connection{Start: shapes[index], End: shapes[index+1]})
+	}
+	genome := createDiagramGenome(Size{3, 3}, &Diagram{shapes, connections})
+	for index, position := range positions {
+		genome.Genes[index].gridPosition = position
+	}
+	return genome
+}
+
+func TestCreateDiagramGenome(t *testing.T) {
+	genome := newGenomeWithShapesAt(0, 1, 2, 3)
+
+	shapes := make(map[*Shape]bool)
+	connections := make(map[*Connection]bool)
+	for index, gene := range genome.Genes {
+		if gene == nil {
+			t.Fatalf("Missing gene %v", index)
+		}
+		if gene.isNode() {
+			shapes[gene.shape()] = true
+		} else if gene.isEdge() {
+			connections[gene.connection()] = true
+		}
+	}
+	if len(shapes) != 4 || len(connections) != 2 {
+		t.Errorf("Invalid genes: %v shapes and %v connections", len(shapes), len(connections))
+	}
+}
+
+func TestRandomEmptyPosition(t *testing.T) {
+	genome := newGenomeWithShapesAt(0, 1, 2, 3, 4, 5, 6, 8)
+
+	for i := 0; i < 10; i++ {
+		if position := randomEmptyPositionIn(genome); position != 7 {
+			t.Fatalf("Expected the only empty position, but got %v", position)
+		}
+	}
+}
+
+func TestLayoutFitness(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		genome   *Genome[*DiagramGene]
+		fitness  func(*Genome[*DiagramGene]) Fitness
+		expected Fitness
+	}{
+		{"crossing edges", newGenomeWithShapesAt(0, 8, 2, 6), calcEdgeCrossings, 0.0},
+		{"parallel edges", newGenomeWithShapesAt(0, 2, 6, 8), calcEdgeCrossings, 1.0},
+		{"overlapping edges", newGenomeWithShapesAt(0, 2, 1, 2), calcEdgeOverlaps, 0.0},
+		{"separate edges", newGenomeWithShapesAt(0, 2, 6, 8), calcEdgeOverlaps, 1.0},
+		{"one row", newGenomeWithShapesAt(0, 1), calcEmptyRowsAndColumns, 0.5},
+		{"all rows and columns", newGenomeWithShapesAt(0, 4, 8), calcEmptyRowsAndColumns, 0.0},
+	} {
+		if actual := test.fitness(test.genome); math.Abs(actual-test.expected) > 1e-9 {
+			t.Errorf("Expected fitness %v for %v, but got %v", test.expected, test.name, actual)
+		}
+	}
+}
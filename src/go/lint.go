@@ -8,14 +8,20 @@ import (
 
 var readers = map[string]ModelPartReader{
 	"databases":         DatabaseReader{},
+	"environments":      EnvironmentReader{},
+	"events":            EventReader{},
 	"externalSystems":   ExternalSystemReader{},
+	"metrics":           MetricsReader{},
 	"personas":          PersonaReader{},
 	"queues":            QueueReader{},
 	"services":          ServiceReader{},
+	"styles":            StylesReader{},
 	"system":            SystemReader{},
 	"technologyBundles": TechnologyBundleReader{},
+	"teams":             TeamReader{},
 	"technologies":      TechnologyReader{},
 	"version":           VersionReader{},
+	"views":             DiagramViewReader{},
 	"workflows":         WorkflowReader{},
 }
 
@@ -25,15 +31,22 @@ var connectors = []Connector{
 	DatabaseConnector{},
 	QueueConnector{},
 	ServiceConnector{},
+	EventConnector{},
+	DeploymentConnector{},
 	ExternalSystemConnector{},
 	PersonaConnector{},
+	OwnerConnector{},
 	WorkflowCollector{},
+	DiagramViewConnector{},
 }
 
 var validators = []Validator{
 	DatabaseValidator{},
 	DataStoreValidator{},
+	DeploymentValidator{},
 	ExternalSystemValidator{},
+	MetricsValidator{},
+	OwnerValidator{},
 	PersonaValidator{},
 	ServiceValidator{},
 }
@@ -61,7 +74,7 @@ func lint(definition string, fileName string) (model *ArchitectureModel, issues
 		if exists {
 			issues = append(issues, reader.read(child, fileName, model)...)
 		} else {
-			issues = append(issues, *NodeWarning(fmt.Sprint("Unknown top-level element: ", tag), child))
+			issues = append(issues, *unknownTopLevelElement(tag, child))
 		}
 	}
 	for tag, reader := range readers {
@@ -80,6 +93,18 @@ func lint(definition string, fileName string) (model *ArchitectureModel, issues
 	return
 }
 
+func unknownTopLevelElement(tag string, node *yaml.Node) *Issue {
+	message := fmt.Sprint("Unknown top-level element: ", tag)
+	tags := make([]string, 0, len(readers))
+	for known := range readers {
+		tags = append(tags, known)
+	}
+	if suggestion := closestTo(tag, tags); suggestion != "" {
+		message = fmt.Sprintf("%v: did you mean '%v'?", message, suggestion)
+	}
+	return NodeWarning(message, node)
+}
+
 func invalidYaml(message string) []Issue {
 	return []Issue{*FileError(fmt.Sprintf("Invalid YAML: %v", message))}
 }
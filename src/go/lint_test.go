@@ -352,7 +352,7 @@ services:
 
 	model, issues := LintText(definition)
 
-	if len(issues) > 0 {
+	if hasIssue(issues, hasError("")) {
 		t.Errorf("Got issues: %+v", issues)
 	}
 	used := model.Personas[0].Uses[0]
@@ -881,7 +881,8 @@ func TestTechnologyBundle(t *testing.T) {
     - ui
     - thymeleaf
   ui:
-    - thymeleaf
+    technologies:
+      - thymeleaf
   server:
     - java
     - spring
@@ -932,6 +933,11 @@ func TestInvalidTechnologyBundle(t *testing.T) {
 		{definition: `technologyBundles:
   foo:
     bar: baz
+`, error: "Missing required field technologies"},
+		{definition: `technologyBundles:
+  foo:
+    technologies:
+      bar: baz
 `, error: "technologies must be a sequence"},
 		{definition: `technologyBundles:
   foo:
@@ -1308,3 +1314,757 @@ func testExample(t *testing.T, fileName string) {
 		t.Fatalf("No model for example %v", fileName)
 	}
 }
+
+func TestMetricThresholds(t *testing.T) {
+	assertWarningsForInvalidDefinitions(t, []InvalidDefinition{
+		{definition: `metrics:
+  fanOut: 1
+
+services:
+  ape:
+    calls:
+      - service: bear
+      - service: cheetah
+  bear:
+    description: foo
+  cheetah:
+    description: bar
+`, error: "Service 'ape' has fanOut 2, which exceeds the threshold of 1"},
+		{definition: `metrics:
+  fanIn: 0
+
+services:
+  ape:
+    dataStores:
+      - queue: events
+        dataFlow: send
+  bear:
+    dataStores:
+      - queue: events
+        dataFlow: receive
+
+queues:
+  events:
+    description: foo
+`, error: "Service 'ape' has fanIn 1, which exceeds the threshold of 0"},
+	})
+	assertErrorsForInvalidDefinitions(t, []InvalidDefinition{
+		{definition: `metrics: 3`, error: "Expected a map"},
+		{definition: `metrics:
+  ape: 3`, error: "Unknown metric ape"},
+		{definition: `metrics:
+  fanIn: many`, error: "fanIn must be a number, not 'many'"},
+		{definition: `metrics:
+  fanIn:
+    - 3`, error: "fanIn must be a number, not a sequence"},
+	})
+}
+
+func TestInvalidDiagramView(t *testing.T) {
+	assertErrorsForInvalidDefinitions(t, []InvalidDefinition{
+		{definition: `views: 3`, error: "Expected a map"},
+		{definition: `views:
+  ape:
+    include:
+      - bear`, error: "Invalid include: must be one of"},
+		{definition: `views:
+  ape:
+    states:
+      - broken`, error: "Invalid states: must be one of"},
+		{definition: `views:
+  ape:
+    focus: service:bear`, error: "Invalid focus: unknown service 'bear'"},
+		{definition: `views:
+  ape:
+    hops: -1`, error: "hops must be a whole number that isn't negative"},
+		{definition: `views:
+  ape:
+    technologies:
+      - cheetah`, error: "Unknown technology 'cheetah'"},
+	})
+}
+
+func TestStyles(t *testing.T) {
+	model, issues := LintText(`styles:
+  elements:
+    externalSystem:
+      background: white
+  types:
+    saas:
+      stroke: red
+  states:
+    legacy:
+      background: orange
+
+externalSystems:
+  crm:
+    type: saas
+
+services:
+  api:
+    state: legacy
+`)
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+
+	crm := model.theme().StyleOf(model.ExternalSystems[0])
+	if crm.Background != "white" || crm.Stroke != "red" || crm.Shape != "RoundedBox" {
+		t.Errorf("Invalid style for external system: %+v", crm)
+	}
+	api := model.theme().StyleOf(model.Services[0])
+	if api.Background != "orange" || api.Stroke != "black" {
+		t.Errorf("Invalid style for service: %+v", api)
+	}
+}
+
+func TestInvalidStyles(t *testing.T) {
+	assertErrorsForInvalidDefinitions(t, []InvalidDefinition{
+		{definition: `styles: 3`, error: "Expected a map"},
+		{definition: `styles:
+  elements:
+    workflow:
+      background: white`, error: "Invalid elements: must be one of"},
+		{definition: `styles:
+  states:
+    broken:
+      background: white`, error: "Invalid states: must be one of"},
+		{definition: `styles:
+  elements:
+    service:
+      shape: Star`, error: "Invalid shape: must be one of"},
+		{definition: `styles:
+  elements:
+    service:
+      fontSize: -1`, error: "fontSize must be a positive whole number"},
+	})
+}
+
+func TestTeams(t *testing.T) {
+	model, issues := LintText(`teams:
+  core:
+    name: Core team
+    contact: core@example.com
+    links:
+      wiki: https://wiki.example.com/core
+
+services:
+  api:
+    owner: core
+    forms:
+      subscribe:
+        state: ok
+  legacy:
+    state: deprecated
+  worker:
+    description: foo
+
+databases:
+  subscriptions:
+    owner: core
+`)
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+
+	team := model.Teams[0]
+	if team.Name != "Core team" || team.Contact != "core@example.com" || len(team.Links) != 1 ||
+		team.Links[0].Url != "https://wiki.example.com/core" {
+		t.Errorf("Invalid team: %+v", team)
+	}
+	if model.Services[0].Owner != team || model.Databases[0].Owner != team {
+		t.Errorf("Owners not connected")
+	}
+	if ownerOf(model.Services[0].Forms[0]) != team {
+		t.Errorf("Form isn't owned by the owner of its service")
+	}
+	if !hasIssue(issues, hasWarning("Service 'worker' has no owner")) {
+		t.Errorf("Missing warning for service without owner: %+v", issues)
+	}
+	if hasIssue(issues, hasWarning("Service 'legacy' has no owner")) {
+		t.Errorf("Deprecated service requires owner")
+	}
+}
+
+func TestOwnerRequiredWithoutTeams(t *testing.T) {
+	_, issues := LintText(`services:
+  api:
+    description: foo
+`)
+
+	if !hasIssue(issues, hasWarning("Service 'api' has no owner")) {
+		t.Errorf("Missing warning for service without owner: %+v", issues)
+	}
+}
+
+func TestInvalidOwner(t *testing.T) {
+	assertErrorsForInvalidDefinitions(t, []InvalidDefinition{
+		{definition: `teams: 3`, error: "Expected a map"},
+		{definition: `services:
+  api:
+    owner: core`, error: "Unknown team 'core'"},
+		{definition: `queues:
+  events:
+    owner: core`, error: "Unknown team 'core'"},
+		{definition: `teams:
+  core:
+    links: 3`, error: "Expected a map"},
+	})
+}
+
+func TestExtensions(t *testing.T) {
+	model, issues := LintText(`externalSystems:
+  crm:
+    tags:
+      - saas
+    properties:
+      vendor: ACME
+      contract: 2024-17
+    calls:
+      - service: api
+        tags:
+          - nightly
+
+services:
+  api:
+    technologys:
+      - java
+`)
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+
+	crm := model.ExternalSystems[0]
+	if !equalStrings(crm.Tags, []string{"saas"}) || crm.Properties["vendor"] != "ACME" ||
+		crm.Properties["contract"] != "2024-17" {
+		t.Errorf("Invalid extensions: %+v", crm.Extensions)
+	}
+	if !equalStrings(crm.Calls[0].Tags, []string{"nightly"}) {
+		t.Errorf("Invalid extensions of call: %+v", crm.Calls[0].Extensions)
+	}
+	if !hasIssue(issues, hasWarning("Unknown field 'technologys'")) {
+		t.Errorf("Missing warning for unknown field: %+v", issues)
+	}
+}
+
+func TestDotExportOfPropertiesDoesntOverrideStyle(t *testing.T) {
+	model, issues := LintText(`services:
+  api:
+    properties:
+      color: red
+      label: Fake
+`)
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+	printer := NewPrinter()
+
+	err := NewDotExporter().export(*model, printer)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	dot := printer.String()
+	if strings.Contains(dot, `,"color"=`) || strings.Contains(dot, `,"label"=`) ||
+		!strings.Contains(dot, `"property.color"="red","property.label"="Fake",label="Api"`) {
+		t.Errorf("Properties override attributes:\n%v", dot)
+	}
+}
+
+func TestExportOfExtensionsWithQuotes(t *testing.T) {
+	model, issues := LintText(`services:
+  api:
+    tags:
+      - 'a"b'
+    properties:
+      'path\to': 'say "hi"'
+`)
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+
+	for _, test := range []struct {
+		exporter TextExporter
+		expected []string
+	}{
+		{NewDotExporter(), []string{`tags="a\"b"`, `"property.path\\to"="say \"hi\""`}},
+		{NewC4Exporter(), []string{`"a\"b"`, `"path\\to" "say \"hi\""`}},
+	} {
+		printer := NewPrinter()
+		err := test.exporter.export(*model, printer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, expected := range test.expected {
+			if !strings.Contains(printer.String(), expected) {
+				t.Errorf("Missing %v in:\n%v", expected, printer.String())
+			}
+		}
+	}
+}
+
+func TestInvalidExtensions(t *testing.T) {
+	assertErrorsForInvalidDefinitions(t, []InvalidDefinition{
+		{definition: `services:
+  api:
+    tags: foo`, error: "tags must be a sequence"},
+		{definition: `services:
+  api:
+    properties:
+      - foo`, error: "Expected a map"},
+		{definition: `services:
+  api:
+    properties:
+      foo:
+        - bar`, error: "foo must be a string, not a sequence"},
+	})
+}
+
+func TestUnknownFields(t *testing.T) {
+	for _, tc := range []struct {
+		definition string
+		warning    string
+	}{
+		{definition: `services:
+  api:
+    technologys: java`, warning: "Unknown field 'technologys': did you mean 'technologies'?"},
+		{definition: `services:
+  api:
+    dataStores:
+      - database: db
+        dataflow: send`, warning: "Unknown field 'dataflow': did you mean 'dataFlow'?"},
+		{definition: `services:
+  api:
+    calls:
+      - service: foo
+        descriptin: bar`, warning: "Unknown field 'descriptin': did you mean 'description'?"},
+		{definition: `services:
+  api:
+    forms:
+      bar:
+        stat: ok`, warning: "Unknown field 'stat': did you mean 'state'?"},
+		{definition: `databases:
+  db:
+    view:
+      - foo`, warning: "Unknown field 'view': did you mean 'views'?"},
+		{definition: `queues:
+  q:
+    views:
+      - foo`, warning: "Unknown field 'views': use tags or properties for custom data"},
+		{definition: `personas:
+  user:
+    uses:
+      - form: foo
+        extenalSystem: bar`, warning: "Unknown field 'extenalSystem': did you mean 'externalSystem'?"},
+		{definition: `externalSystems:
+  crm:
+    typ: central`, warning: "Unknown field 'typ': did you mean 'type'?"},
+		{definition: `workflows:
+  flow:
+    steps:
+      - performer: user
+        comand: foo`, warning: "Unknown field 'comand': did you mean 'command'?"},
+		{definition: `workflows:
+  flow:
+    step: []`, warning: "Unknown field 'step': did you mean 'steps'?"},
+		{definition: `technologies:
+  java:
+    quadrnt: languagesAndFrameworks`, warning: "Unknown field 'quadrnt': did you mean 'quadrant'?"},
+		{definition: `technologyBundles:
+  server:
+    technologies:
+      - java
+    technologys:
+      - spring`, warning: "Unknown field 'technologys': did you mean 'technologies'?"},
+		{definition: `views:
+  main:
+    hop: 2`, warning: "Unknown field 'hop': did you mean 'hops'?"},
+		{definition: `styles:
+  elements:
+    service:
+      backgroud: red`, warning: "Unknown field 'backgroud': did you mean 'background'?"},
+		{definition: `system:
+  naem: foo`, warning: "Unknown field 'naem': did you mean 'name'?"},
+		{definition: `service:
+  api: {}`, warning: "Unknown top-level element: service: did you mean 'services'?"},
+		{definition: `personas:
+  user:
+    uses:
+      - form: foo
+        color: red`, warning: "Unknown field 'color'"},
+	} {
+		_, issues := LintText(tc.definition)
+
+		if !hasIssue(issues, hasWarning(tc.warning)) {
+			t.Errorf("Missing warning '%v' for %v: %+v", tc.warning, tc.definition, issues)
+		}
+	}
+}
+
+const eventsDefinition = `services:
+  auth:
+    publishes:
+      - registered
+    dataStores:
+      - queue: events
+        dataFlow: send
+  mailer:
+    subscribes:
+      - registered
+    dataStores:
+      - queue: events
+        dataFlow: receive
+
+queues:
+  events:
+    name: Domain events
+
+events:
+  registered:
+    description: A guest registered
+    schema: schemas/registered.json
+    queue: events
+
+workflows:
+  register:
+    steps:
+      - performer: auth
+        event: registered
+`
+
+func TestEvents(t *testing.T) {
+	model, issues := LintText(eventsDefinition)
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+
+	event := model.Events[0]
+	if event.Name != "Registered" || event.Description != "A guest registered" ||
+		event.Schema != "schemas/registered.json" || event.Queue != model.Queues[0] {
+		t.Errorf("Invalid event: %+v", event)
+	}
+	auth, _ := model.findServiceById("auth")
+	mailer, _ := model.findServiceById("mailer")
+	if len(auth.PublishedEvents) != 1 || auth.PublishedEvents[0] != event || len(mailer.SubscribedEvents) != 1 ||
+		mailer.SubscribedEvents[0] != event {
+		t.Errorf("Services not connected to events")
+	}
+	if len(event.Publishers) != 1 || event.Publishers[0] != auth || len(event.Subscribers) != 1 ||
+		event.Subscribers[0] != mailer {
+		t.Errorf("Events not connected to services: %+v", event)
+	}
+	if model.Workflows[0].Steps[0].Event != event {
+		t.Errorf("Step not connected to event")
+	}
+}
+
+func TestInvalidEvents(t *testing.T) {
+	assertErrorsForInvalidDefinitions(t, []InvalidDefinition{
+		{definition: `events: 3`, error: "Expected a map"},
+		{definition: `events:
+  registered:
+    name: Registered`, error: "Missing required field queue"},
+		{definition: `events:
+  registered:
+    queue: events`, error: "Unknown queue 'events'"},
+		{definition: `services:
+  api:
+    publishes: registered`, error: "publishes must be a sequence"},
+		{definition: `services:
+  api:
+    subscribes:
+      - registered`, error: "Unknown event 'registered'"},
+		{definition: strings.Replace(eventsDefinition, "dataFlow: send", "dataFlow: receive", 1),
+			error: "Service 'auth' publishes event 'registered', but doesn't send to queue 'events'"},
+		{definition: strings.Replace(eventsDefinition, "dataFlow: receive", "dataFlow: send", 1),
+			error: "Service 'mailer' subscribes to event 'registered', but doesn't receive from queue 'events'"},
+		{definition: strings.Replace(eventsDefinition, "event: registered", "event: unregistered", 1),
+			error: "Unknown event 'unregistered'"},
+		{definition: strings.Replace(eventsDefinition, "performer: auth", "performer: mailer", 1),
+			error: "Service 'mailer' doesn't publish event 'registered'"},
+	})
+}
+
+const commandsDefinition = `services:
+  gateway:
+    forms:
+      - registration
+    calls:
+      - service: auth
+  auth:
+    commands:
+      register:
+        name: Register guest
+        description: Creates an account
+  mailer:
+    commands:
+      - sendMail
+
+workflows:
+  register:
+    steps:
+      - performer: registration
+        command: register
+`
+
+func TestCommands(t *testing.T) {
+	model, issues := LintText(commandsDefinition)
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+
+	auth, _ := model.findServiceById("auth")
+	command := auth.Commands[0]
+	if command.Name != "Register guest" || command.Description != "Creates an account" || command.HandledBy != auth {
+		t.Errorf("Invalid command: %+v", command)
+	}
+	mailer, _ := model.findServiceById("mailer")
+	if len(mailer.Commands) != 1 || mailer.Commands[0].Id != "sendMail" || mailer.Commands[0].HandledBy != mailer {
+		t.Errorf("Invalid commands: %+v", mailer.Commands)
+	}
+	if model.Workflows[0].Steps[0].Command != command {
+		t.Errorf("Step not connected to command")
+	}
+}
+
+func TestInvalidCommands(t *testing.T) {
+	definition := commandsDefinition
+	assertErrorsForInvalidDefinitions(t, []InvalidDefinition{
+		{definition: `services:
+  api:
+    commands: 3`, error: "commands must be a sequence"},
+		{definition: strings.Replace(definition, "command: register", "command: unregister", 1),
+			error: "Unknown command 'unregister'"},
+		{definition: strings.Replace(definition, "command: register", "command: sendMail", 1),
+			error: "Command 'sendMail' is handled by service 'mailer', which 'registration' doesn't call"},
+		{definition: strings.Replace(definition, "      - sendMail", "      - register", 1),
+			error: "Command 'register' is already handled by service"},
+	})
+}
+
+const deploymentDefinition = `services:
+  api:
+    dataStores:
+      - database: customers
+      - queue: events
+        dataFlow: send
+
+databases:
+  customers: {}
+
+queues:
+  events: {}
+
+technologies:
+  kubernetes:
+    quadrant: platforms
+
+environments:
+  production:
+    deploymentNodes:
+      cloud:
+        name: Cloud
+        deploymentNodes:
+          cluster:
+            technologies: kubernetes
+            instances:
+              - service: api
+                replicas: 3
+              - queue: events
+        instances:
+          - database: customers
+`
+
+func TestDeployment(t *testing.T) {
+	model, issues := LintText(deploymentDefinition)
+	if hasIssue(issues, hasError("")) || hasIssue(issues, hasWarning("deployed")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+
+	environment := model.Environments[0]
+	if environment.Name != "Production" || len(environment.DeploymentNodes) != 1 {
+		t.Fatalf("Invalid environment: %+v", environment)
+	}
+	cloud := environment.DeploymentNodes[0]
+	if len(cloud.DeploymentNodes) != 1 || len(cloud.Instances) != 1 || cloud.Instances[0].Database != model.Databases[0] ||
+		cloud.Instances[0].Replicas != 1 {
+		t.Fatalf("Invalid deployment node: %+v", cloud)
+	}
+	cluster := cloud.DeploymentNodes[0]
+	api, _ := model.findServiceById("api")
+	if len(cluster.Technologies) != 1 || cluster.Technologies[0] != model.Technologies[0] ||
+		cluster.Instances[0].Service != api || cluster.Instances[0].Replicas != 3 ||
+		cluster.Instances[1].Queue != model.Queues[0] {
+		t.Errorf("Invalid deployment node: %+v", cluster)
+	}
+	if len(environment.Instances()) != 3 {
+		t.Errorf("Invalid instances: %+v", environment.Instances())
+	}
+}
+
+func TestInvalidDeployment(t *testing.T) {
+	assertErrorsForInvalidDefinitions(t, []InvalidDefinition{
+		{definition: `environments: 3`, error: "Expected a map"},
+		{definition: `environments:
+  production:
+    deploymentNodes: 3`, error: "Expected a map"},
+		{definition: `environments:
+  production:
+    deploymentNodes:
+      vm:
+        instances:
+          - replicas: 2`, error: "An instance must be either a service, a database, or a queue"},
+		{definition: `environments:
+  production:
+    deploymentNodes:
+      vm:
+        instances:
+          - service: api
+            queue: events`, error: "An instance must be either a service, a database, or a queue"},
+		{definition: strings.Replace(deploymentDefinition, "replicas: 3", "replicas: 0", 1),
+			error: "replicas must be a positive whole number"},
+		{definition: strings.Replace(deploymentDefinition, "- service: api", "- service: web", 1),
+			error: "Unknown service 'web'"},
+		{definition: strings.Replace(deploymentDefinition, "- database: customers", "- database: orders", 1),
+			error: "Unknown database 'orders'"},
+		{definition: strings.Replace(deploymentDefinition, "- queue: events", "- queue: jobs", 1),
+			error: "Unknown queue 'jobs'"},
+		{definition: strings.Replace(deploymentDefinition, "technologies: kubernetes", "technologies: docker", 1),
+			error: "Unknown technology 'docker'"},
+	})
+	assertWarningsForInvalidDefinitions(t, []InvalidDefinition{
+		{definition: strings.Replace(deploymentDefinition, "              - queue: events\n", "", 1),
+			error: "Queue 'events' isn't deployed in environment 'production'"},
+	})
+}
+
+func TestUndeployedElementWarningPointsAtElement(t *testing.T) {
+	_, issues := LintText(strings.Replace(deploymentDefinition, "              - queue: events\n", "", 1))
+
+	found := false
+	for _, issue := range issues {
+		if issue.Message == "Queue 'events' isn't deployed in environment 'production'" {
+			found = true
+			if issue.Line != 12 {
+				t.Errorf("Warning should point at the queue, but is at line %v", issue.Line)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("Missing warning for queue that isn't deployed: %+v", issues)
+	}
+}
+
+const interfacesDefinition = `services:
+  gateway:
+    calls:
+      - service: api
+        interface: orders
+      - service: api
+        interface: admin
+        technologies: grpc
+  api:
+    interfaces:
+      orders:
+        name: Orders API
+        type: rest
+        spec: specs/orders.yaml
+        version: 2.1.0
+        technologies: http
+      admin:
+        type: grpc
+
+technologies:
+  http:
+    name: HTTP
+    quadrant: platforms
+  grpc:
+    name: gRPC
+    quadrant: platforms
+`
+
+func TestInterfaces(t *testing.T) {
+	model, issues := LintText(interfacesDefinition)
+	if hasIssue(issues, hasError("")) || hasIssue(issues, hasWarning("never called")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+
+	api, _ := model.findServiceById("api")
+	orders, _ := api.findInterfaceById("orders")
+	if orders.Name != "Orders API" || orders.Type != "rest" || orders.Spec != "specs/orders.yaml" ||
+		orders.Version != "2.1.0" || orders.ImplementedBy != api || len(orders.Technologies) != 1 {
+		t.Errorf("Invalid interface: %+v", orders)
+	}
+	gateway, _ := model.findServiceById("gateway")
+	if gateway.Calls[0].Interface != orders || gateway.Calls[0].UsedTechnologies()[0].Name != "HTTP" ||
+		gateway.Calls[1].UsedTechnologies()[0].Name != "gRPC" {
+		t.Errorf("Invalid calls: %+v", gateway.Calls)
+	}
+	printer := NewPrinter()
+	_ = NewC4Exporter().export(*model, printer)
+	if !strings.Contains(printer.String(), `gateway -> api "" "HTTP"`) {
+		t.Errorf("Missing technology of interface in relationship:\n%v", printer.String())
+	}
+}
+
+func TestDotLabelsOnlyCalls(t *testing.T) {
+	model, issues := LintText(`services:
+  gateway:
+    calls:
+      - service: api
+        technologies: http
+  api:
+    dataStores:
+      - database: orders
+
+databases:
+  orders:
+    apiTechnologies: sql
+
+technologies:
+  http:
+    name: HTTP
+    quadrant: platforms
+  sql:
+    name: SQL
+    quadrant: languagesAndFrameworks
+`)
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+	printer := NewPrinter()
+
+	err := NewDotExporter().export(*model, printer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{`gateway -> api [dir=both,label="HTTP"]`, `api -> orders_db [dir=both]`} {
+		if !strings.Contains(printer.String(), expected) {
+			t.Errorf("Missing %v in:\n%v", expected, printer.String())
+		}
+	}
+}
+
+func TestInvalidInterfaces(t *testing.T) {
+	assertErrorsForInvalidDefinitions(t, []InvalidDefinition{
+		{definition: `services:
+  api:
+    interfaces: 3`, error: "Expected a map"},
+		{definition: strings.Replace(interfacesDefinition, "type: rest", "type: soap", 1),
+			error: "Invalid type: must be one of"},
+		{definition: strings.Replace(interfacesDefinition, "        type: grpc\n", "", 1),
+			error: "Missing required field type"},
+		{definition: strings.Replace(interfacesDefinition, "interface: orders", "interface: payments", 1),
+			error: "Service 'api' has no interface 'payments'"},
+		{definition: `externalSystems:
+  slack:
+    calls:
+      - externalSystem: jira
+        interface: rest`, error: "Only a call to a service can specify an interface"},
+	})
+	assertWarningsForInvalidDefinitions(t, []InvalidDefinition{
+		{definition: strings.Replace(interfacesDefinition, "interface: admin", "interface: orders", 1),
+			error: "Interface 'admin' of service 'api' is never called"},
+	})
+}
@@ -3,36 +3,141 @@ package main
 import (
 	"flag"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sort"
+	"strings"
 )
 
+// builtInCommands are the commands that aren't exporter plugins.
+var builtInCommands = []string{"asyncapi", "c4", "deployment", "dfd", "dot", "drift", "eventmodel", "explorer",
+	"impact", "import", "json", "landscape", "lint", "markdown", "metrics", "owners", "query", "scan-tech", "site",
+	"template", "verify-traces"}
+
 func main() {
 	var command string
 	var fileName string
+	var directory string
+	var modelFileName string
+	var mappingFileName string
 	var output string
 	var workflow string
+	var templateName string
+	var query string
+	var format string
+	var element string
+	var view string
+	var theme string
+	var environment string
+	var filter ViewFilter
+	var include, exclude, types, states, technologies, tags string
 
-	flag.StringVar(&command, "c", "lint", "Command.")
-	flag.StringVar(&fileName, "f", "", "Name of model file")
+	flag.StringVar(&command, "c", "lint", "Command: "+strings.Join(builtInCommands, ", ")+", or the format of an "+
+		"exporter plugin")
+	flag.StringVar(&fileName, "f", "", "Name of model file, or comma-separated names for landscape and impact")
+	flag.StringVar(&directory, "d", "", "Name of directory to import, like one with Kubernetes manifests")
+	flag.StringVar(&modelFileName, "m", "", "Name of model file to compare an import with, for drift")
 	flag.StringVar(&output, "o", "", "Name of output file")
 	flag.StringVar(&workflow, "w", "", "ID of workflow")
+	flag.StringVar(&templateName, "t", "", "Name of template file, or of built-in template (dfd, dot), or of spans "+
+		"file for verify-traces")
+	flag.StringVar(&mappingFileName, "mapping", "", "Name of file that maps names in traces to elements, for "+
+		"verify-traces, or dependencies to technologies, for scan-tech")
+	flag.StringVar(&query, "q", "", "Query to run")
+	flag.StringVar(&format, "format", "", "Format of results: table or json for query, markdown or csv for metrics")
+	flag.StringVar(&element, "e", "", "ID of element to analyze, optionally prefixed with its kind, like service:api")
+	flag.StringVar(&theme, "theme", "", "Theme file with styles that override those in the model")
+	flag.StringVar(&environment, "environment", "", "ID of environment to export, if the model has more than one")
+	flag.StringVar(&view, "view", "", "ID of view in the model to export")
+	flag.StringVar(&filter.Focus, "focus", "", "Only export the element with this ID and its neighbors")
+	flag.IntVar(&filter.Hops, "hops", defaultHops, "Number of relationships between the focus and its neighbors")
+	flag.StringVar(&include, "include", "", "Only export elements of these kinds (comma-separated)")
+	flag.StringVar(&exclude, "exclude", "", "Don't export elements of these kinds (comma-separated)")
+	flag.StringVar(&types, "type", "", "Only export external systems of these types (comma-separated)")
+	flag.StringVar(&states, "state", "", "Only export elements in these states (comma-separated)")
+	flag.StringVar(&technologies, "technology", "", "Only export elements that use these technologies (comma-separated)")
+	flag.StringVar(&tags, "tag", "", "Only export elements with any of these tags (comma-separated)")
 	flag.Parse()
+	source := flag.Arg(0)
+	if flag.NArg() > 1 {
+		// Allow flags after the source, like in: -c import compose -f docker-compose.yml
+		_ = flag.CommandLine.Parse(flag.Args()[1:])
+	}
+	input := fileName
+	if directory != "" {
+		input = directory
+	}
+	filter.IncludeKinds = SplitList(include)
+	filter.ExcludeKinds = SplitList(exclude)
+	filter.Types = SplitList(types)
+	filter.States = SplitList(states)
+	filter.Technologies = SplitList(technologies)
+	filter.Tags = SplitList(tags)
+	if err := filter.Validate(); err != nil {
+		fmt.Println(err)
+		return
+	}
 
 	switch command {
+	case "asyncapi":
+		export(fileName, theme, view, filter, NewAsyncApiExporter(), output)
 	case "c4":
-		export(fileName, NewC4Exporter(), output)
+		export(fileName, theme, view, filter, NewC4Exporter(), output)
+	case "deployment":
+		export(fileName, theme, view, filter, NewDeploymentExporter(environment), output)
 	case "dfd":
-		export(fileName, NewDfdExporter(), output)
+		export(fileName, theme, view, filter, NewDfdExporter(), output)
+	case "drift":
+		driftOf(source, input, modelFileName, output)
 	case "eventmodel":
 		if workflow == "" {
 			flag.PrintDefaults()
 			return
 		}
-		export(fileName, NewEventModelExporter(workflow), output)
+		export(fileName, theme, view, filter, NewEventModelExporter(workflow), output)
+	case "explorer":
+		export(fileName, theme, view, filter, NewExplorerExporter(), output)
 	case "dot":
-		export(fileName, NewDotExporter(), output)
+		export(fileName, theme, view, filter, NewDotExporter(), output)
+	case "template":
+		exporter, err := NewTemplateExporter(templateName)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		export(fileName, theme, view, filter, exporter, output)
+	case "json":
+		export(fileName, theme, view, filter, NewJsonExporter(), output)
+	case "impact":
+		impactOf(fileName, element, output)
+	case "import":
+		importModel(source, input, output)
+	case "markdown":
+		export(fileName, theme, view, filter, NewMarkdownExporter(), output)
+	case "landscape":
+		landscapeOf(fileName, output)
 	case "lint":
 		lintFile(fileName)
+	case "metrics":
+		metricsOf(fileName, format, output)
+	case "owners":
+		ownersOf(fileName, format, output)
+	case "query":
+		queryFile(fileName, query, format, output)
+	case "site":
+		siteOf(fileName, theme, output)
+	case "scan-tech":
+		scanTechnologies(fileName, mappingFileName, output)
+	case "verify-traces":
+		verifyTraces(fileName, templateName, mappingFileName, output)
+	default:
+		exporter, err := NewPluginExporter(command)
+		if err != nil {
+			fmt.Println(err)
+			fmt.Println("The built-in commands are:", strings.Join(builtInCommands, ", "))
+			return
+		}
+		export(fileName, theme, view, filter, exporter, output)
 	}
 }
 
@@ -49,6 +154,271 @@ func lintFile(fileName string) {
 	}
 }
 
+func queryFile(fileName string, text string, format string, output string) {
+	if fileName == "" || text == "" {
+		flag.PrintDefaults()
+		return
+	}
+	query, err := ParseQuery(text)
+	if err != nil {
+		fmt.Printf("Invalid query: %v\n", err)
+		return
+	}
+	model, issues := LintFile(fileName)
+	if model == nil {
+		listIssues(fileName, issues)
+		return
+	}
+	printer := NewPrinter()
+	err = PrintQueryResults(query.Run(model), format, printer)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	writeOutput(printer, output)
+}
+
+func impactOf(fileName string, ref string, output string) {
+	if fileName == "" || ref == "" {
+		flag.PrintDefaults()
+		return
+	}
+	if fileNames := SplitList(fileName); len(fileNames) > 1 {
+		landscapeImpactOf(fileNames, ref)
+		return
+	}
+	model, issues := LintFile(fileName)
+	if model == nil {
+		listIssues(fileName, issues)
+		return
+	}
+	element, err := model.findElement(ref)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	printer := NewPrinter()
+	AnalyzeImpact(model, element).Print(printer)
+	fmt.Print(printer.String())
+	if output != "" {
+		err = Export(*model, NewImpactExporter(ref, output), output)
+		if err != nil {
+			fmt.Println(err)
+		}
+	}
+}
+
+func importModel(source string, input string, output string) {
+	importer, found := importers[source]
+	if !found || input == "" || output == "" {
+		fmt.Printf("Usage: -c import <%v> -f <file> | -d <directory> -o <model>\n",
+			strings.Join(sortedKeys(importers), "|"))
+		flag.PrintDefaults()
+		return
+	}
+	model, err := importer(input)
+	if err == nil {
+		err = WriteImportedModel(model, output)
+	}
+	if err != nil {
+		fmt.Println(err)
+	} else if len(model.Warnings) > 0 {
+		listIssues(input, model.Warnings)
+	}
+}
+
+func driftOf(source string, input string, modelFileName string, output string) {
+	importer, found := importers[source]
+	if !found || input == "" || modelFileName == "" {
+		fmt.Printf("Usage: -c drift <%v> -f <file> | -d <directory> -m <model>\n",
+			strings.Join(sortedKeys(importers), "|"))
+		flag.PrintDefaults()
+		return
+	}
+	model, issues := LintFile(modelFileName)
+	if model == nil {
+		listIssues(modelFileName, issues)
+		return
+	}
+	imported, err := importer(input)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	printer := NewPrinter()
+	FindDrift(model, imported).Print(source, printer)
+	writeOutput(printer, output)
+}
+
+func verifyTraces(fileName string, spansFileName string, mappingFileName string, output string) {
+	if fileName == "" || spansFileName == "" {
+		flag.PrintDefaults()
+		return
+	}
+	model, issues := LintFile(fileName)
+	if model == nil {
+		listIssues(fileName, issues)
+		return
+	}
+	spans, err := ReadSpans(spansFileName)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	mapping, err := ReadMapping(mappingFileName)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	report, err := VerifyTraces(model, spans, mapping)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	printer := NewPrinter()
+	report.Print(printer)
+	writeOutput(printer, output)
+}
+
+func siteOf(fileName string, theme string, output string) {
+	if fileName == "" || output == "" {
+		flag.PrintDefaults()
+		return
+	}
+	model, issues := LintFile(fileName)
+	if model == nil {
+		listIssues(fileName, issues)
+		return
+	}
+	if theme != "" {
+		issues = model.ReadThemeFile(theme)
+		if len(issues) > 0 {
+			listIssues(theme, issues)
+			return
+		}
+	}
+	err := WriteSite(model, output)
+	if err != nil {
+		fmt.Println(err)
+	}
+}
+
+func scanTechnologies(fileName string, mappingFileName string, output string) {
+	if fileName == "" {
+		flag.PrintDefaults()
+		return
+	}
+	model, issues := LintFile(fileName)
+	if model == nil {
+		listIssues(fileName, issues)
+		return
+	}
+	mapping, err := ReadMapping(mappingFileName)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	scan, err := ScanTechnologies(model, filepath.Dir(fileName), mapping)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	printer := NewPrinter()
+	scan.Print(printer)
+	writeOutput(printer, output)
+}
+
+func landscapeImpactOf(fileNames []string, ref string) {
+	landscape, issues := LintLandscape(fileNames)
+	if landscape == nil {
+		listLandscapeIssues(issues)
+		return
+	}
+	model, element, err := landscape.findElement(ref)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	printer := NewPrinter()
+	PrintLandscapeImpact(AnalyzeLandscapeImpact(landscape, model, element), printer)
+	fmt.Print(printer.String())
+}
+
+func landscapeOf(fileName string, output string) {
+	if fileName == "" || output == "" {
+		flag.PrintDefaults()
+		return
+	}
+	landscape, issues := LintLandscape(SplitList(fileName))
+	if landscape == nil {
+		listLandscapeIssues(issues)
+		return
+	}
+	printer := NewPrinter()
+	PrintLandscape(landscape, printer)
+	writeOutput(printer, output)
+}
+
+func listLandscapeIssues(issuesByFileName map[string][]Issue) {
+	fileNames := make([]string, 0, len(issuesByFileName))
+	for fileName := range issuesByFileName {
+		fileNames = append(fileNames, fileName)
+	}
+	sort.Strings(fileNames)
+	for _, fileName := range fileNames {
+		listIssues(fileName, issuesByFileName[fileName])
+	}
+}
+
+func metricsOf(fileName string, format string, output string) {
+	if fileName == "" {
+		flag.PrintDefaults()
+		return
+	}
+	model, issues := LintFile(fileName)
+	if model == nil {
+		listIssues(fileName, issues)
+		return
+	}
+	printer := NewPrinter()
+	err := ComputeMetrics(model).Print(format, printer)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	writeOutput(printer, output)
+}
+
+func ownersOf(fileName string, format string, output string) {
+	if fileName == "" {
+		flag.PrintDefaults()
+		return
+	}
+	model, issues := LintFile(fileName)
+	if model == nil {
+		listIssues(fileName, issues)
+		return
+	}
+	printer := NewPrinter()
+	err := PrintOwners(model, format, printer)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	writeOutput(printer, output)
+}
+
+func writeOutput(printer *Printer, output string) {
+	if output == "" {
+		fmt.Print(printer.String())
+		return
+	}
+	err := os.WriteFile(output, []byte(printer.String()), 0666)
+	if err != nil {
+		fmt.Println(err)
+	}
+}
+
 func listIssues(fileName string, issues []Issue) {
 	fmt.Printf("Issues for %v\n", fileName)
 	sort.Slice(issues, func(i, j int) bool {
@@ -60,7 +430,7 @@ func listIssues(fileName string, issues []Issue) {
 	}
 }
 
-func export(input string, exporter TextExporter, output string) {
+func export(input string, theme string, view string, filter ViewFilter, exporter TextExporter, output string) {
 	if input == "" || output == "" {
 		flag.PrintDefaults()
 		return
@@ -70,7 +440,27 @@ func export(input string, exporter TextExporter, output string) {
 		listIssues(input, issues)
 		return
 	}
-	err := Export(*model, exporter, output)
+	if theme != "" {
+		issues = model.ReadThemeFile(theme)
+		if len(issues) > 0 {
+			listIssues(theme, issues)
+			return
+		}
+	}
+	if view != "" {
+		diagramView, found := model.findDiagramViewById(view)
+		if !found {
+			fmt.Printf("Unknown view '%v'\n", view)
+			return
+		}
+		filter = diagramView.Filter
+	}
+	slice, err := filter.Apply(*model)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	err = Export(slice, exporter, output)
 	if err != nil {
 		fmt.Println(err)
 	}
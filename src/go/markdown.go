@@ -0,0 +1,423 @@
+package main
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type markdownExporter struct {
+}
+
+// NewMarkdownExporter creates an exporter that documents the model in Markdown, for people who don't read YAML. The
+// diagrams are Mermaid code blocks, which many Markdown viewers render.
+func NewMarkdownExporter() TextExporter {
+	return markdownExporter{}
+}
+
+func (m markdownExporter) export(model ArchitectureModel, printer *Printer) error {
+	m.printOverview(&model, printer)
+	m.printPersonas(model.Personas, printer)
+	m.printExternalSystems(model.ExternalSystems, printer)
+	m.printServices(model.Services, printer)
+	m.printDataStores(&model, printer)
+	m.printTechnologies(model.Technologies, printer)
+	m.printWorkflows(&model, printer)
+	return nil
+}
+
+func (m markdownExporter) printOverview(model *ArchitectureModel, printer *Printer) {
+	printer.PrintLn("# ", model.System.Name)
+	printer.NewLine()
+	counts := make([]string, 0)
+	for _, count := range []struct {
+		number   int
+		singular string
+		plural   string
+	}{
+		{len(model.Personas), "persona", "personas"},
+		{len(model.ExternalSystems), "external system", "external systems"},
+		{len(model.Services), "service", "services"},
+		{len(model.Databases), "database", "databases"},
+		{len(model.Queues), "queue", "queues"},
+		{len(model.Workflows), "workflow", "workflows"},
+	} {
+		switch count.number {
+		case 0:
+		case 1:
+			counts = append(counts, "1 "+count.singular)
+		default:
+			counts = append(counts, strconv.Itoa(count.number)+" "+count.plural)
+		}
+	}
+	if len(counts) == 0 {
+		printer.PrintLn("The system is empty.")
+		printer.NewLine()
+		return
+	}
+	printer.PrintLn("The system has ", joinEnumeration(counts), ".")
+	printer.NewLine()
+	m.printFlowchart(model, printer)
+}
+
+// joinEnumeration joins values like a, b, and c.
+func joinEnumeration(values []string) string {
+	switch len(values) {
+	case 1:
+		return values[0]
+	case 2:
+		return values[0] + " and " + values[1]
+	default:
+		return strings.Join(values[:len(values)-1], ", ") + ", and " + values[len(values)-1]
+	}
+}
+
+// printFlowchart prints a Mermaid flowchart with all elements and relationships, in the styles of the model. Arrows
+// point in the direction of the data flow.
+func (m markdownExporter) printFlowchart(model *ArchitectureModel, printer *Printer) {
+	theme := model.theme()
+	printer.PrintLn("```mermaid")
+	printer.PrintLn("flowchart LR")
+	printer.Start()
+	nodes := make([]interface{}, 0)
+	for _, element := range model.Elements() {
+		if kindOf(element) == "persona" || kindOf(element) == "externalSystem" || kindOf(element) == "service" ||
+			kindOf(element) == "database" || kindOf(element) == "queue" {
+			nodes = append(nodes, element)
+		}
+	}
+	for _, node := range nodes {
+		opening, closing := m.shapeOf(node)
+		printer.PrintLn(mermaidIdOf(node), opening, mermaidText(nameOf(node)), closing)
+	}
+	for _, relationship := range model.Relationships() {
+		from := mermaidIdOf(relationship.From)
+		to := mermaidIdOf(containerOf(relationship.To))
+		label := ""
+		if relationship.Description != "" {
+			label = "|" + mermaidText(relationship.Description) + "|"
+		}
+		switch relationship.DataFlow {
+		case Send:
+			printer.PrintLn(from, " -->", label, " ", to)
+		case Receive:
+			printer.PrintLn(to, " -->", label, " ", from)
+		default:
+			printer.PrintLn(from, " <-->", label, " ", to)
+		}
+	}
+	for _, node := range nodes {
+		if style := m.styleOf(theme.StyleOf(node)); style != "" {
+			printer.PrintLn("style ", mermaidIdOf(node), " ", style)
+		}
+	}
+	printer.End()
+	printer.PrintLn("```")
+	printer.NewLine()
+}
+
+// shapeOf returns the Mermaid delimiters of the shape of a node, which depends on the kind of element rather than its
+// style, since Mermaid doesn't have all the shapes that styles allow.
+func (m markdownExporter) shapeOf(element interface{}) (string, string) {
+	switch element.(type) {
+	case *Persona:
+		return "([", "])"
+	case *ExternalSystem:
+		return "(", ")"
+	case *Database:
+		return "[(", ")]"
+	case *DataStore:
+		return "[[", "]]"
+	default:
+		return "[", "]"
+	}
+}
+
+func (m markdownExporter) styleOf(style Style) string {
+	properties := make([]string, 0)
+	if style.Background != "" {
+		properties = append(properties, "fill:"+style.Background)
+	}
+	if style.Stroke != "" {
+		properties = append(properties, "stroke:"+style.Stroke)
+	}
+	if style.Color != "" {
+		properties = append(properties, "color:"+style.Color)
+	}
+	return strings.Join(properties, ",")
+}
+
+// mermaidIdOf returns the ID of the Mermaid node of an element, which has only letters, digits, and underscores.
+func mermaidIdOf(element interface{}) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, kindOf(element)+"_"+idOf(element))
+}
+
+// mermaidText quotes text for use in a Mermaid label.
+func mermaidText(text string) string {
+	return "\"" + strings.NewReplacer("\"", "#quot;", "\n", " ").Replace(text) + "\""
+}
+
+// markdownCell escapes text for use in a cell of a Markdown table.
+func markdownCell(text string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(text)
+}
+
+func (m markdownExporter) printTable(header []string, rows [][]string, printer *Printer) {
+	printer.PrintLn("| ", strings.Join(header, " | "), " |")
+	printer.PrintLn("|", strings.Repeat("---|", len(header)))
+	for _, row := range rows {
+		cells := make([]string, 0, len(row))
+		for _, cell := range row {
+			cells = append(cells, markdownCell(cell))
+		}
+		printer.PrintLn("| ", strings.Join(cells, " | "), " |")
+	}
+	printer.NewLine()
+}
+
+func (m markdownExporter) printPersonas(personas []*Persona, printer *Printer) {
+	if len(personas) == 0 {
+		return
+	}
+	printer.PrintLn("## Personas")
+	printer.NewLine()
+	rows := make([][]string, 0)
+	for _, persona := range personas {
+		uses := make([]string, 0)
+		for _, used := range persona.Uses {
+			uses = append(uses, nameOf(used.Used()))
+		}
+		rows = append(rows, []string{persona.Name, persona.Description, strings.Join(uses, ", ")})
+	}
+	m.printTable([]string{"Persona", "Description", "Uses"}, rows, printer)
+}
+
+func (m markdownExporter) printExternalSystems(externalSystems []*ExternalSystem, printer *Printer) {
+	if len(externalSystems) == 0 {
+		return
+	}
+	printer.PrintLn("## External systems")
+	printer.NewLine()
+	rows := make([][]string, 0)
+	for _, externalSystem := range externalSystems {
+		rows = append(rows, []string{externalSystem.Name, externalSystem.Type, externalSystem.Description,
+			ownerNameOf(externalSystem)})
+	}
+	m.printTable([]string{"External system", "Type", "Description", "Owner"}, rows, printer)
+}
+
+func (m markdownExporter) printServices(services []*Service, printer *Printer) {
+	if len(services) == 0 {
+		return
+	}
+	printer.PrintLn("## Services")
+	printer.NewLine()
+	rows := make([][]string, 0)
+	for _, service := range services {
+		rows = append(rows, []string{service.Name, service.Description, joinTechnologies(service.Technologies),
+			service.State.Id(), ownerNameOf(service)})
+	}
+	m.printTable([]string{"Service", "Description", "Technologies", "State", "Owner"}, rows, printer)
+}
+
+// printDataStores prints the databases and queues, with the services that use them.
+func (m markdownExporter) printDataStores(model *ArchitectureModel, printer *Printer) {
+	if len(model.Databases) == 0 && len(model.Queues) == 0 {
+		return
+	}
+	printer.PrintLn("## Data stores")
+	printer.NewLine()
+	users := make(map[interface{}][]string)
+	for _, relationship := range model.Relationships() {
+		if relationship.Kind() == "dataStore" {
+			to := containerOf(relationship.To)
+			users[to] = append(users[to], nameOf(relationship.From))
+		}
+	}
+	rows := make([][]string, 0)
+	for _, database := range model.Databases {
+		rows = append(rows, []string{database.Name, "database", database.Description,
+			joinTechnologies(technologiesOf(database)), database.State.Id(), strings.Join(users[database], ", ")})
+	}
+	for _, queue := range model.Queues {
+		rows = append(rows, []string{queue.Name, "queue", queue.Description, joinTechnologies(technologiesOf(queue)),
+			queue.State.Id(), strings.Join(users[queue], ", ")})
+	}
+	m.printTable([]string{"Data store", "Kind", "Description", "Technologies", "State", "Used by"}, rows, printer)
+}
+
+// printTechnologies prints the technologies like a technology radar, grouped by quadrant and then by ring.
+func (m markdownExporter) printTechnologies(technologies []*Technology, printer *Printer) {
+	if len(technologies) == 0 {
+		return
+	}
+	printer.PrintLn("## Technology radar")
+	printer.NewLine()
+	sorted := append(make([]*Technology, 0, len(technologies)), technologies...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Quadrant != sorted[j].Quadrant {
+			return sorted[i].Quadrant < sorted[j].Quadrant
+		}
+		return ringOrder(sorted[i].Ring) < ringOrder(sorted[j].Ring)
+	})
+	rows := make([][]string, 0)
+	for _, technology := range sorted {
+		rows = append(rows, []string{technology.Name, technology.Quadrant.String(), technology.Ring.String(),
+			technology.Description})
+	}
+	m.printTable([]string{"Technology", "Quadrant", "Ring", "Description"}, rows, printer)
+}
+
+// ringOrder returns the position of a ring on a technology radar, from adopt in the middle to hold at the edge.
+func ringOrder(ring Ring) int {
+	switch ring {
+	case Adopt:
+		return 0
+	case Trial:
+		return 1
+	case Assess:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// printWorkflows prints the top-level workflows as numbered steps, with a Mermaid sequence diagram.
+func (m markdownExporter) printWorkflows(model *ArchitectureModel, printer *Printer) {
+	if len(model.Workflows) == 0 {
+		return
+	}
+	printer.PrintLn("## Workflows")
+	printer.NewLine()
+	for _, workflow := range model.Workflows {
+		if !workflow.TopLevel {
+			continue
+		}
+		printer.PrintLn("### ", workflow.Name)
+		printer.NewLine()
+		if workflow.Description != "" {
+			printer.PrintLn(workflow.Description)
+			printer.NewLine()
+		}
+		if len(workflow.Steps) == 0 {
+			printer.PrintLn("The workflow has no steps.")
+			printer.NewLine()
+			continue
+		}
+		for index, step := range workflow.Steps {
+			printer.PrintLn(index+1, ". **", nameOf(step.Performer), "** ", actionOf(step))
+		}
+		printer.NewLine()
+		m.printSequenceDiagram(model, workflow, printer)
+	}
+}
+
+// actionOf describes what the performer of a step does, like "uses form Register" or "calls service API: Validates".
+// A step with several actions, like a form that issues a command, describes all of them. Elements that aren't in the
+// model, like free-form commands, are described by their IDs.
+func actionOf(step *Step) string {
+	actions := make([]string, 0)
+	switch {
+	case step.Form != nil:
+		actions = append(actions, "uses form "+step.Form.Name)
+	case step.FormId != "":
+		actions = append(actions, "uses form "+step.FormId)
+	}
+	if step.View != "" {
+		actions = append(actions, "reads view "+step.View)
+	}
+	switch {
+	case step.Command != nil:
+		actions = append(actions, "issues command "+step.Command.Name+" to "+step.Command.HandledBy.Name)
+	case step.CommandId != "":
+		actions = append(actions, "issues command "+step.CommandId)
+	}
+	switch {
+	case step.Event != nil:
+		actions = append(actions, "publishes event "+step.Event.Name)
+	case step.EventId != "":
+		actions = append(actions, "publishes event "+step.EventId)
+	}
+	switch {
+	case step.Service != nil:
+		actions = append(actions, "calls "+step.Service.Name)
+	case step.ServiceId != "":
+		actions = append(actions, "calls "+step.ServiceId)
+	case step.ExternalSystem != nil:
+		actions = append(actions, "calls "+step.ExternalSystem.Name)
+	case step.ExternalSystemId != "":
+		actions = append(actions, "calls "+step.ExternalSystemId)
+	}
+	result := strings.Join(actions, " and ")
+	if step.Description != "" {
+		result += ": " + step.Description
+	}
+	return result
+}
+
+// targetOf returns the element that the performer of a step interacts with, or nil if there is none, like for an
+// event.
+func targetOf(model *ArchitectureModel, step *Step) interface{} {
+	switch {
+	case step.Form != nil:
+		return step.Form.ImplementedBy
+	case step.View != "":
+		for _, database := range model.Databases {
+			if database.hasView(step.View) {
+				return database
+			}
+		}
+	case step.Command != nil:
+		return step.Command.HandledBy
+	case step.Service != nil:
+		return step.Service
+	case step.ExternalSystem != nil:
+		return step.ExternalSystem
+	}
+	return nil
+}
+
+func (m markdownExporter) printSequenceDiagram(model *ArchitectureModel, workflow *Workflow, printer *Printer) {
+	printer.PrintLn("```mermaid")
+	printer.PrintLn("sequenceDiagram")
+	printer.Start()
+	participants := make(map[string]bool)
+	for _, step := range workflow.Steps {
+		for _, element := range []interface{}{containerOf(step.Performer), targetOf(model, step)} {
+			if element == nil || participants[mermaidIdOf(element)] {
+				continue
+			}
+			participants[mermaidIdOf(element)] = true
+			kind := "participant"
+			if _, ok := element.(*Persona); ok {
+				kind = "actor"
+			}
+			printer.PrintLn(kind, " ", mermaidIdOf(element), " as ", strings.ReplaceAll(nameOf(element), ";", ","))
+		}
+	}
+	for _, step := range workflow.Steps {
+		performer := mermaidIdOf(containerOf(step.Performer))
+		message := strings.ReplaceAll(actionOf(step), ";", ",")
+		if target := targetOf(model, step); target != nil {
+			printer.PrintLn(performer, "->>", mermaidIdOf(target), ": ", message)
+		} else {
+			printer.PrintLn("Note over ", performer, ": ", message)
+		}
+	}
+	printer.End()
+	printer.PrintLn("```")
+	printer.NewLine()
+}
+
+func ownerNameOf(element interface{}) string {
+	if owner := ownerOf(element); owner != nil {
+		return owner.Name
+	}
+	return ""
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const markdownDefinition = `system:
+  name: Shop
+personas:
+  customer:
+    description: Buys | sells
+    uses:
+      - form: order
+externalSystems:
+  bank:
+    type: central
+services:
+  api:
+    state: legacy
+    technologies: [go]
+    forms: [order]
+    calls:
+      - externalSystem: bank
+        description: Pays "now"
+    dataStores:
+      - database: orders
+        dataFlow: send
+      - queue: events
+databases:
+  orders:
+    views: [recent]
+queues:
+  events: {}
+technologies:
+  go:
+    name: Go
+    quadrant: languagesAndFrameworks
+  cobol:
+    quadrant: languagesAndFrameworks
+    ring: hold
+workflows:
+  buy:
+    description: Buying things
+    steps:
+      - performer: customer
+        form: order
+        description: Orders
+      - performer: api
+        externalSystem: bank
+      - performer: customer
+        view: recent
+`
+
+func TestMarkdownExporter(t *testing.T) {
+	model, issues := LintText(markdownDefinition)
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+	printer := NewPrinter()
+
+	err := NewMarkdownExporter().export(*model, printer)
+
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	expected := `# Shop
+
+The system has 1 persona, 1 external system, 1 service, 1 database, 1 queue, and 1 workflow.
+
+` + "```" + `mermaid
+flowchart LR
+    persona_customer(["Customer"])
+    externalSystem_bank("Bank")
+    service_api["Api"]
+    database_orders[("Orders")]
+    queue_events[["Events"]]
+    persona_customer <--> service_api
+    service_api <-->|"Pays #quot;now#quot;"| externalSystem_bank
+    service_api --> database_orders
+    service_api <--> queue_events
+    style persona_customer fill:GhostWhite,stroke:#3966a0
+    style externalSystem_bank fill:#a2c4c9,stroke:black
+    style service_api fill:#e69238,stroke:black
+    style database_orders fill:#b6d7a8,stroke:black
+    style queue_events fill:#b6d7a8,stroke:black
+` + "```" + `
+
+## Personas
+
+| Persona | Description | Uses |
+|---|---|---|
+| Customer | Buys \| sells | order |
+
+## External systems
+
+| External system | Type | Description | Owner |
+|---|---|---|---|
+| Bank | central |  |  |
+
+## Services
+
+| Service | Description | Technologies | State | Owner |
+|---|---|---|---|---|
+| Api |  | Go | legacy |  |
+
+## Data stores
+
+| Data store | Kind | Description | Technologies | State | Used by |
+|---|---|---|---|---|---|
+| Orders | database |  |  | ok | Api |
+| Events | queue |  |  | ok | Api |
+
+## Technology radar
+
+| Technology | Quadrant | Ring | Description |
+|---|---|---|---|
+| Go | languagesAndFrameworks | adopt |  |
+| Cobol | languagesAndFrameworks | hold |  |
+
+## Workflows
+
+### Buy
+
+Buying things
+
+1. **Customer** uses form order: Orders
+2. **Api** calls Bank
+3. **Customer** reads view recent
+
+` + "```" + `mermaid
+sequenceDiagram
+    actor persona_customer as Customer
+    participant service_api as Api
+    participant externalSystem_bank as Bank
+    participant database_orders as Orders
+    persona_customer->>service_api: uses form order: Orders
+    service_api->>externalSystem_bank: calls Bank
+    persona_customer->>database_orders: reads view recent
+` + "```" + `
+
+`
+	if printer.String() != expected {
+		t.Errorf("Expected:\n%v\nbut got:\n%v", expected, printer.String())
+	}
+}
+
+func TestMarkdownSequenceDiagramShowsCommandHandler(t *testing.T) {
+	model, issues := LintText(`services:
+  gateway:
+    forms: [registration]
+    calls:
+      - service: auth
+  auth:
+    commands: [register]
+workflows:
+  register:
+    steps:
+      - performer: registration
+        command: register
+`)
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+	printer := NewPrinter()
+
+	err := NewMarkdownExporter().export(*model, printer)
+
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	expected := "    service_gateway->>service_auth: issues command register to Auth\n"
+	if !strings.Contains(printer.String(), expected) {
+		t.Errorf("Expected:\n%v\nin:\n%v", expected, printer.String())
+	}
+}
+
+func TestMarkdownWorkflowWithFreeFormCommand(t *testing.T) {
+	model, issues := LintText(`personas:
+  customer:
+    uses:
+      - form: order
+services:
+  api:
+    forms: [order]
+workflows:
+  buy:
+    steps:
+      - performer: customer
+        form: order
+      - performer: order
+        command: placeOrder
+      - performer: api
+        event: orderPlaced
+`)
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+	printer := NewPrinter()
+
+	err := NewMarkdownExporter().export(*model, printer)
+
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	for _, expected := range []string{
+		"2. **order** issues command placeOrder\n",
+		"3. **Api** publishes event orderPlaced\n",
+		"    Note over service_api: issues command placeOrder\n",
+		"    Note over service_api: publishes event orderPlaced\n",
+	} {
+		if !strings.Contains(printer.String(), expected) {
+			t.Errorf("Missing %v in:\n%v", expected, printer.String())
+		}
+	}
+}
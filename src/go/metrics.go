@@ -0,0 +1,310 @@
+package main
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ServiceMetrics measures how a service is coupled to the rest of the system. A service depends on the services and
+// external systems it calls, and on the services that write to the data stores it reads from.
+type ServiceMetrics struct {
+	Service *Service
+	// FanIn is the number of services and external systems that depend on the service (afferent coupling, Ca).
+	FanIn int
+	// FanOut is the number of services and external systems the service depends on (efferent coupling, Ce).
+	FanOut int
+	// Instability is Ce/(Ca+Ce), or NaN for a service that isn't coupled at all.
+	Instability  float64
+	Technologies int
+	Workflows    int
+}
+
+type SystemMetrics struct {
+	Services                    []*ServiceMetrics
+	StronglyConnectedComponents int
+	// AveragePathLength is the average length of the shortest dependency path between two services, over all pairs
+	// of services where one depends on the other.
+	AveragePathLength float64
+}
+
+func ComputeMetrics(model *ArchitectureModel) *SystemMetrics {
+	dependencies := dependenciesOf(model)
+	dependents := make(map[interface{}]map[interface{}]bool)
+	for from, tos := range dependencies {
+		for to := range tos {
+			if dependents[to] == nil {
+				dependents[to] = make(map[interface{}]bool)
+			}
+			dependents[to][from] = true
+		}
+	}
+	result := &SystemMetrics{Services: make([]*ServiceMetrics, 0)}
+	for _, service := range model.Services {
+		metrics := &ServiceMetrics{
+			Service:      service,
+			FanIn:        len(dependents[service]),
+			FanOut:       len(dependencies[service]),
+			Instability:  math.NaN(),
+			Technologies: len(service.Technologies),
+			Workflows:    numWorkflowsInvolving(model, service),
+		}
+		if metrics.FanIn+metrics.FanOut > 0 {
+			metrics.Instability = float64(metrics.FanOut) / float64(metrics.FanIn+metrics.FanOut)
+		}
+		result.Services = append(result.Services, metrics)
+	}
+	graph := serviceGraphOf(model, dependencies)
+	result.StronglyConnectedComponents = numStronglyConnectedComponents(model.Services, graph)
+	result.AveragePathLength = averagePathLength(model.Services, graph)
+	return result
+}
+
+// dependenciesOf returns, for each service and external system, the services and external systems it depends on.
+func dependenciesOf(model *ArchitectureModel) map[interface{}]map[interface{}]bool {
+	result := make(map[interface{}]map[interface{}]bool)
+	addDependency := func(from interface{}, to interface{}) {
+		if from == to {
+			return
+		}
+		if result[from] == nil {
+			result[from] = make(map[interface{}]bool)
+		}
+		result[from][to] = true
+	}
+	for _, externalSystem := range model.ExternalSystems {
+		for _, call := range externalSystem.Calls {
+			addDependency(externalSystem, call.Callee())
+		}
+	}
+	for _, service := range model.Services {
+		for _, call := range service.Calls {
+			addDependency(service, call.Callee())
+		}
+	}
+	for _, reader := range model.Services {
+		for _, read := range reader.DataStores {
+			if read.DataFlow == Send {
+				continue
+			}
+			for _, writer := range model.Services {
+				for _, write := range writer.DataStores {
+					if write.DataFlow != Receive && write.DatabaseId == read.DatabaseId && write.QueueId == read.QueueId {
+						addDependency(reader, writer)
+					}
+				}
+			}
+		}
+	}
+	return result
+}
+
+func numWorkflowsInvolving(model *ArchitectureModel, service *Service) int {
+	involves := involvesCondition{reference{kindOf(service), service.Id}}
+	result := 0
+	for _, workflow := range model.Workflows {
+		if involves.matches(workflow, model) {
+			result++
+		}
+	}
+	return result
+}
+
+func serviceGraphOf(model *ArchitectureModel, dependencies map[interface{}]map[interface{}]bool) map[*Service][]*Service {
+	result := make(map[*Service][]*Service)
+	for _, service := range model.Services {
+		result[service] = make([]*Service, 0)
+		for _, dependency := range model.Services {
+			if dependencies[service][dependency] {
+				result[service] = append(result[service], dependency)
+			}
+		}
+	}
+	return result
+}
+
+// numStronglyConnectedComponents uses Tarjan's algorithm to count the groups of services that all depend on each
+// other. A service that isn't part of a cycle is a component by itself.
+func numStronglyConnectedComponents(services []*Service, graph map[*Service][]*Service) int {
+	index := 0
+	indices := make(map[*Service]int)
+	lowLinks := make(map[*Service]int)
+	onStack := make(map[*Service]bool)
+	stack := make([]*Service, 0)
+	result := 0
+	var connect func(service *Service)
+	connect = func(service *Service) {
+		indices[service] = index
+		lowLinks[service] = index
+		index++
+		stack = append(stack, service)
+		onStack[service] = true
+		for _, dependency := range graph[service] {
+			if _, visited := indices[dependency]; !visited {
+				connect(dependency)
+				lowLinks[service] = minOf(lowLinks[service], lowLinks[dependency])
+			} else if onStack[dependency] {
+				lowLinks[service] = minOf(lowLinks[service], indices[dependency])
+			}
+		}
+		if lowLinks[service] == indices[service] {
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				if top == service {
+					break
+				}
+			}
+			result++
+		}
+	}
+	for _, service := range services {
+		if _, visited := indices[service]; !visited {
+			connect(service)
+		}
+	}
+	return result
+}
+
+func minOf(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func averagePathLength(services []*Service, graph map[*Service][]*Service) float64 {
+	total := 0
+	numPaths := 0
+	for _, start := range services {
+		distances := map[*Service]int{start: 0}
+		todo := []*Service{start}
+		for len(todo) > 0 {
+			current := todo[0]
+			todo = todo[1:]
+			for _, next := range graph[current] {
+				if _, found := distances[next]; !found {
+					distances[next] = distances[current] + 1
+					total += distances[next]
+					numPaths++
+					todo = append(todo, next)
+				}
+			}
+		}
+	}
+	if numPaths == 0 {
+		return 0
+	}
+	return float64(total) / float64(numPaths)
+}
+
+// Print prints the metrics as a Markdown table, or as CSV.
+func (m *SystemMetrics) Print(format string, printer *Printer) error {
+	header := []string{"Service", "Fan-in", "Fan-out", "Instability", "Technologies", "Workflows"}
+	rows := make([][]string, 0)
+	for _, metrics := range m.Services {
+		instability := ""
+		if !math.IsNaN(metrics.Instability) {
+			instability = strconv.FormatFloat(metrics.Instability, 'f', 2, 64)
+		}
+		rows = append(rows, []string{metrics.Service.Id, strconv.Itoa(metrics.FanIn), strconv.Itoa(metrics.FanOut),
+			instability, strconv.Itoa(metrics.Technologies), strconv.Itoa(metrics.Workflows)})
+	}
+	switch format {
+	case "", "markdown":
+		printer.PrintLn("| ", strings.Join(header, " | "), " |")
+		printer.PrintLn("|", strings.Join([]string{"---", "--:", "--:", "--:", "--:", "--:"}, "|"), "|")
+		for _, row := range rows {
+			printer.PrintLn("| ", strings.Join(row, " | "), " |")
+		}
+		printer.NewLine()
+		printer.PrintLn("Strongly connected components: ", m.StronglyConnectedComponents)
+		printer.PrintLn("Average path length: ", strconv.FormatFloat(m.AveragePathLength, 'f', 2, 64))
+	case "csv":
+		printer.PrintLn(strings.Join(header, ","))
+		for _, row := range rows {
+			printer.PrintLn(strings.Join(row, ","))
+		}
+		// The system-wide metrics follow as a separate table, after an empty line
+		printer.NewLine()
+		printer.PrintLn("Metric,Value")
+		printer.PrintLn("Strongly connected components,", m.StronglyConnectedComponents)
+		printer.PrintLn("Average path length,", strconv.FormatFloat(m.AveragePathLength, 'f', 2, 64))
+	default:
+		return fmt.Errorf("unknown format '%v': must be one of 'markdown' or 'csv'", format)
+	}
+	return nil
+}
+
+var metricNames = []string{"fanIn", "fanOut", "instability", "technologies", "workflows"}
+
+func (m *ServiceMetrics) valueOf(metric string) float64 {
+	switch metric {
+	case "fanIn":
+		return float64(m.FanIn)
+	case "fanOut":
+		return float64(m.FanOut)
+	case "instability":
+		return m.Instability
+	case "technologies":
+		return float64(m.Technologies)
+	case "workflows":
+		return float64(m.Workflows)
+	default:
+		panic(fmt.Sprintf("Unknown metric: %v", metric))
+	}
+}
+
+type MetricsReader struct {
+}
+
+func (m MetricsReader) read(node *yaml.Node, _ string, model *ArchitectureModel) []Issue {
+	model.MetricThresholds = make(map[string]float64)
+	if node == nil {
+		return []Issue{}
+	}
+	thresholds, issue := toMap(node)
+	if issue != nil {
+		return []Issue{*issue}
+	}
+	issues := make([]Issue, 0)
+	for metric, thresholdNode := range thresholds {
+		if hasDifferentValueThan(metric, metricNames) {
+			issues = append(issues, *NodeError(fmt.Sprintf("Unknown metric %v: must be one of %v", metric,
+				stringsIn(metricNames)), thresholdNode))
+			continue
+		}
+		threshold, issue := toNumber(thresholdNode, metric)
+		if issue != nil {
+			issues = append(issues, *issue)
+		} else {
+			model.MetricThresholds[metric] = threshold
+		}
+	}
+	return issues
+}
+
+type MetricsValidator struct {
+}
+
+func (m MetricsValidator) validate(model *ArchitectureModel) []Issue {
+	issues := make([]Issue, 0)
+	if len(model.MetricThresholds) == 0 {
+		return issues
+	}
+	for _, metrics := range ComputeMetrics(model).Services {
+		for _, metric := range metricNames {
+			threshold, found := model.MetricThresholds[metric]
+			value := metrics.valueOf(metric)
+			if found && value > threshold {
+				issues = append(issues, *NodeWarning(fmt.Sprintf("Service '%v' has %v %v, which exceeds the threshold of %v",
+					metrics.Service.Id, metric, strconv.FormatFloat(value, 'g', 3, 64),
+					strconv.FormatFloat(threshold, 'g', 3, 64)), metrics.Service.node))
+			}
+		}
+	}
+	return issues
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	definition := `services:
+  a:
+    calls:
+      - service: b
+  b:
+    calls:
+      - service: a
+      - service: c
+  c:
+    description: foo
+  d:
+    description: bar
+`
+	model, issues := LintText(definition)
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+
+	metrics := ComputeMetrics(model)
+
+	b := metrics.Services[1]
+	if b.FanIn != 1 || b.FanOut != 2 || math.Abs(b.Instability-2.0/3.0) > 1e-9 {
+		t.Errorf("Invalid metrics for b: %+v", b)
+	}
+	if !math.IsNaN(metrics.Services[3].Instability) {
+		t.Errorf("Uncoupled service has instability %v", metrics.Services[3].Instability)
+	}
+	if metrics.StronglyConnectedComponents != 3 {
+		t.Errorf("Invalid # strongly connected components: %v", metrics.StronglyConnectedComponents)
+	}
+	// a->b: 1, a->c: 2, b->a: 1, b->c: 1
+	if math.Abs(metrics.AveragePathLength-1.25) > 1e-9 {
+		t.Errorf("Invalid average path length: %v", metrics.AveragePathLength)
+	}
+
+	printer := NewPrinter()
+	err := metrics.Print("csv", printer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `Service,Fan-in,Fan-out,Instability,Technologies,Workflows
+a,1,1,0.50,0,0
+b,1,2,0.67,0,0
+c,1,0,0.00,0,0
+d,0,0,,0,0
+
+Metric,Value
+Strongly connected components,3
+Average path length,1.25
+`
+	if printer.String() != expected {
+		t.Errorf("Expected CSV:\n%v\nbut got:\n%v", expected, printer.String())
+	}
+}
+
+func TestMetricsOfSharedDataStores(t *testing.T) {
+	definition := `services:
+  writer:
+    dataStores:
+      - database: db
+        dataFlow: send
+  reader:
+    dataStores:
+      - database: db
+        dataFlow: receive
+  owner:
+    dataStores:
+      - database: db
+  unrelated:
+    dataStores:
+      - database: other
+
+databases:
+  db: {}
+  other: {}
+`
+	model, issues := LintText(definition)
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+
+	metrics := ComputeMetrics(model)
+
+	// The reader depends on both services that write, the owner only on the writer, and nobody on the reader.
+	for _, expected := range []struct {
+		id     string
+		fanIn  int
+		fanOut int
+	}{{"owner", 1, 1}, {"reader", 0, 2}, {"unrelated", 0, 0}, {"writer", 2, 0}} {
+		found := false
+		for _, actual := range metrics.Services {
+			if actual.Service.Id == expected.id {
+				found = true
+				if actual.FanIn != expected.fanIn || actual.FanOut != expected.fanOut {
+					t.Errorf("Invalid metrics for %v: %+v", expected.id, actual)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("Missing metrics for %v", expected.id)
+		}
+	}
+	if metrics.StronglyConnectedComponents != 4 {
+		t.Errorf("Invalid # strongly connected components: %v", metrics.StronglyConnectedComponents)
+	}
+}
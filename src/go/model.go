@@ -16,102 +16,21 @@ type ArchitectureModel struct {
 	Technologies      []*Technology
 	TechnologyBundles []*TechnologyBundle
 	Workflows         []*Workflow
+	MetricThresholds  map[string]float64
+	DiagramViews      []*DiagramView
+	Theme             *Theme
+	Teams             []*Team
+	Events            []*Event
+	Environments      []*Environment
 }
 
+// String returns the model as a Markdown document.
 func (model ArchitectureModel) String() string {
 	printer := NewPrinter()
-	model.writeSystem(printer)
-	printer.Start()
-
-	model.writePersonas(printer)
-	model.writeExternalSystems(printer)
-	model.writeServices(printer)
-	model.writeDatabases(printer)
-	model.writeQueues(printer)
-	model.writeWorkflows(printer)
-
-	printer.End()
+	_ = NewMarkdownExporter().export(model, printer)
 	return printer.String()
 }
 
-func (model ArchitectureModel) writeSystem(printer *Printer) {
-	printer.PrintLn(model.System.Name, ":")
-}
-
-func (model ArchitectureModel) writePersonas(printer *Printer) {
-	if len(model.Personas) == 0 {
-		return
-	}
-	printer.NewLine()
-	printer.PrintLn("Personas:")
-	for _, persona := range model.Personas {
-		printer.Print("- ")
-		persona.Print(printer)
-	}
-}
-
-func (model ArchitectureModel) writeExternalSystems(printer *Printer) {
-	if len(model.ExternalSystems) == 0 {
-		return
-	}
-	printer.NewLine()
-	printer.PrintLn("External systems:")
-	for _, externalSystem := range model.ExternalSystems {
-		printer.Print("- ")
-		externalSystem.Print(printer)
-	}
-}
-
-func (model ArchitectureModel) writeServices(printer *Printer) {
-	if len(model.Services) == 0 {
-		return
-	}
-	printer.NewLine()
-	printer.PrintLn("Services:")
-	for _, service := range model.Services {
-		printer.Print("- ")
-		service.Print(printer)
-	}
-}
-
-func (model ArchitectureModel) writeDatabases(printer *Printer) {
-	if len(model.Databases) == 0 {
-		return
-	}
-	printer.NewLine()
-	printer.PrintLn("Databases:")
-	for _, database := range model.Databases {
-		printer.Print("- ")
-		database.Print(printer)
-	}
-}
-
-func (model ArchitectureModel) writeQueues(printer *Printer) {
-	if len(model.Queues) == 0 {
-		return
-	}
-	printer.NewLine()
-	printer.PrintLn("Queues:")
-	for _, queue := range model.Queues {
-		printer.Print("- ")
-		queue.Print(printer)
-	}
-}
-
-func (model ArchitectureModel) writeWorkflows(printer *Printer) {
-	if len(model.Workflows) == 0 {
-		return
-	}
-	printer.NewLine()
-	printer.PrintLn("Workflows:")
-	for _, workflow := range model.Workflows {
-		if workflow.TopLevel {
-			printer.Print("- ")
-			workflow.Print(printer)
-		}
-	}
-}
-
 func (model ArchitectureModel) findPersonaById(id string) (*Persona, bool) {
 	for _, candidate := range model.Personas {
 		if candidate.Id == id {
@@ -139,6 +58,16 @@ func (model ArchitectureModel) findServiceById(id string) (*Service, bool) {
 	return nil, false
 }
 
+func (model ArchitectureModel) findFormById(id string) (*Form, bool) {
+	for _, service := range model.Services {
+		form, found := service.findFormById(id)
+		if found {
+			return form, true
+		}
+	}
+	return nil, false
+}
+
 func (model ArchitectureModel) findTechnologyBundleById(id string) (*TechnologyBundle, bool) {
 	for _, candidate := range model.TechnologyBundles {
 		if candidate.Id == id {
@@ -165,3 +94,12 @@ func (model ArchitectureModel) findDatabaseById(id string) (*Database, bool) {
 	}
 	return nil, false
 }
+
+func (model ArchitectureModel) findQueueById(id string) (*DataStore, bool) {
+	for _, candidate := range model.Queues {
+		if candidate.Id == id {
+			return candidate, true
+		}
+	}
+	return nil, false
+}
@@ -17,7 +17,11 @@ func namedObject(node *yaml.Node, id string, nameable Nameable) (map[string]*yam
 	nameable.setNode(node)
 	nameable.setId(id)
 	fields, _ := toMap(node)
-	return fields, setName(fields, nameable, id)
+	issues := setName(fields, nameable, id)
+	if extensible, ok := nameable.(Extensible); ok {
+		issues = append(issues, setExtensions(fields, extensible)...)
+	}
+	return fields, issues
 }
 
 func setName(fields map[string]*yaml.Node, nameable Nameable, id string) []Issue {
@@ -44,3 +48,21 @@ func friendlyNameFrom(value string) string {
 	}
 	return name
 }
+
+// idFromName turns a name into an ID in camel case, like paymentService for Payment Service or grpc for gRPC.
+func idFromName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var result strings.Builder
+	for index, word := range words {
+		if index == 0 {
+			result.WriteString(strings.ToLower(word))
+			continue
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		result.WriteString(string(runes))
+	}
+	return result.String()
+}
@@ -30,6 +30,8 @@ func (u *Used) setDataFlow(dataFlow DataFlow) {
 	u.DataFlow = dataFlow
 }
 
+var usedFields = []string{"externalSystem", "form", "view", "description", "dataFlow"}
+
 func (u *Used) read(node *yaml.Node) []Issue {
 	u.node = node
 	fields, issue := toMap(node)
@@ -40,6 +42,7 @@ func (u *Used) read(node *yaml.Node) []Issue {
 	issues = append(issues, u.readUsed(node, issue, fields)...)
 	issues = append(issues, setDescription(fields, u)...)
 	issues = append(issues, setDataFlow(node, fields, u)...)
+	issues = append(issues, checkFields(fields, usedFields)...)
 	return issues
 }
 
@@ -84,10 +87,7 @@ type Persona struct {
 	Name        string
 	Description string
 	Uses        []*Used
-}
-
-func (p *Persona) Print(printer *Printer) {
-	printer.PrintLn(p.Name)
+	Extensions
 }
 
 func (p *Persona) setNode(node *yaml.Node) {
@@ -110,6 +110,8 @@ func (p *Persona) setDescription(description string) {
 	p.Description = description
 }
 
+var personaFields = []string{"name", "description", "uses"}
+
 func (p *Persona) read(id string, node *yaml.Node) []Issue {
 	var fields map[string]*yaml.Node
 	fields, issues := namedObject(node, id, p)
@@ -128,7 +130,7 @@ func (p *Persona) read(id string, node *yaml.Node) []Issue {
 		uses = append(uses, &use)
 	}
 	p.Uses = uses
-	return issues
+	return append(issues, checkExtensibleFields(fields, personaFields)...)
 }
 
 type PersonaReader struct {
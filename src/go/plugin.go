@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// Exporters that aren't built in are looked up on the PATH as executables with this prefix.
+const pluginPrefix = "archmodel-export-"
+
+type pluginExporter struct {
+	path string
+}
+
+// NewPluginExporter finds the executable that exports to the given format. The executable receives the model on
+// stdin, encoded as a JsonModel, and must write its output to stdout.
+func NewPluginExporter(format string) (TextExporter, error) {
+	path, err := exec.LookPath(pluginPrefix + format)
+	if err != nil {
+		return nil, fmt.Errorf("unknown exporter '%v': %v", format, err)
+	}
+	return pluginExporter{path}, nil
+}
+
+func (p pluginExporter) export(model ArchitectureModel, printer *Printer) error {
+	input, err := json.Marshal(NewJsonModel(&model))
+	if err != nil {
+		return err
+	}
+	var output bytes.Buffer
+	cmd := exec.Command(p.path)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &output
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("exporter %v failed: %v", p.path, err)
+	}
+	printer.Print(output.String())
+	return nil
+}
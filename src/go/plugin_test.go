@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// installPlugin puts an exporter with the given shell script on a PATH that contains nothing else.
+func installPlugin(t *testing.T, format string, script string) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Plugins are shell scripts")
+	}
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, pluginPrefix+format), []byte("#!/bin/sh\n"+script+"\n"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+}
+
+func TestPluginExporter(t *testing.T) {
+	model, issues := LintText(jsonDefinition)
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+	// The plugin echoes its input, which is a single line without a newline, using only shell builtins.
+	installPlugin(t, "echo", "IFS= read -r line; printf '%s' \"$line\"")
+	exporter, err := NewPluginExporter("echo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	printer := NewPrinter()
+
+	err = exporter.export(*model, printer)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	var received JsonModel
+	if err := json.Unmarshal([]byte(printer.String()), &received); err != nil {
+		t.Fatalf("Plugin didn't receive JSON: %v\n%v", err, printer.String())
+	}
+	if received.FormatVersion != jsonFormatVersion || len(received.Services) != 1 ||
+		received.Services[0].Id != "api" {
+		t.Errorf("Invalid model: %+v", received)
+	}
+}
+
+func TestFailingPluginExporter(t *testing.T) {
+	model, _ := LintText(jsonDefinition)
+	installPlugin(t, "fail", "exit 3")
+	exporter, err := NewPluginExporter("fail")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = exporter.export(*model, NewPrinter())
+
+	if err == nil || !strings.Contains(err.Error(), "exit status 3") {
+		t.Errorf("Expected error but got %v", err)
+	}
+}
+
+func TestUnknownPluginExporter(t *testing.T) {
+	installPlugin(t, "known", "")
+
+	_, err := NewPluginExporter("unknown")
+
+	if err == nil || !strings.HasPrefix(err.Error(), "unknown exporter 'unknown'") {
+		t.Errorf("Expected error but got %v", err)
+	}
+}
@@ -0,0 +1,685 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Query selects elements or relationships from a connected model. A query has the form
+//
+//	<kind> [where <condition>]
+//
+// where kind is the kind of element to select, like service or database, relationship to select relationships, or *
+// to select all elements. See the README for the conditions.
+type Query struct {
+	kind      string
+	condition condition
+}
+
+type condition interface {
+	matches(item interface{}, model *ArchitectureModel) bool
+}
+
+var queryKinds = map[string]string{
+	"*":               "*",
+	"persona":         "persona",
+	"personas":        "persona",
+	"externalSystem":  "externalSystem",
+	"externalSystems": "externalSystem",
+	"service":         "service",
+	"services":        "service",
+	"form":            "form",
+	"forms":           "form",
+	"command":         "command",
+	"commands":        "command",
+	"database":        "database",
+	"databases":       "database",
+	"view":            "view",
+	"views":           "view",
+	"queue":           "queue",
+	"queues":          "queue",
+	"event":           "event",
+	"events":          "event",
+	"technology":      "technology",
+	"technologies":    "technology",
+	"workflow":        "workflow",
+	"workflows":       "workflow",
+	"relationship":    "relationship",
+	"relationships":   "relationship",
+}
+
+// ParseQuery parses the text of a query.
+func ParseQuery(text string) (*Query, error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return nil, err
+	}
+	parser := queryParser{tokens: tokens}
+	return parser.parseQuery()
+}
+
+// Run returns the elements or relationships in the model that match the query.
+func (q *Query) Run(model *ArchitectureModel) []interface{} {
+	candidates := make([]interface{}, 0)
+	if q.kind == "relationship" {
+		for _, relationship := range model.Relationships() {
+			candidates = append(candidates, relationship)
+		}
+	} else {
+		for _, element := range model.Elements() {
+			if q.kind == "*" || kindOf(element) == q.kind {
+				candidates = append(candidates, element)
+			}
+		}
+	}
+	result := make([]interface{}, 0)
+	for _, candidate := range candidates {
+		if q.condition == nil || q.condition.matches(candidate, model) {
+			result = append(result, candidate)
+		}
+	}
+	return result
+}
+
+type tokenType int
+
+const (
+	wordToken tokenType = iota
+	stringToken
+	symbolToken
+	endToken
+)
+
+type token struct {
+	kind  tokenType
+	value string
+}
+
+func tokenize(text string) ([]token, error) {
+	tokens := make([]token, 0)
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %v", i)
+			}
+			tokens = append(tokens, token{stringToken, string(runes[i+1 : end])})
+			i = end + 1
+		case r == '!' && i+1 < len(runes) && runes[i+1] == '=':
+			tokens = append(tokens, token{symbolToken, "!="})
+			i += 2
+		case strings.ContainsRune("()=~:,", r):
+			tokens = append(tokens, token{symbolToken, string(r)})
+			i++
+		case isWordRune(r):
+			end := i
+			for end < len(runes) && isWordRune(runes[end]) {
+				end++
+			}
+			tokens = append(tokens, token{wordToken, string(runes[i:end])})
+			i = end
+		default:
+			return nil, fmt.Errorf("unexpected '%c' at position %v", r, i)
+		}
+	}
+	return append(tokens, token{endToken, ""}), nil
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_-./*", r)
+}
+
+type queryParser struct {
+	tokens   []token
+	position int
+}
+
+func (p *queryParser) peek() token {
+	return p.tokens[p.position]
+}
+
+func (p *queryParser) next() token {
+	result := p.tokens[p.position]
+	if result.kind != endToken {
+		p.position++
+	}
+	return result
+}
+
+func (p *queryParser) isWord(value string) bool {
+	return p.peek().kind == wordToken && p.peek().value == value
+}
+
+func (p *queryParser) isSymbol(value string) bool {
+	return p.peek().kind == symbolToken && p.peek().value == value
+}
+
+func (p *queryParser) expectSymbol(value string) error {
+	if !p.isSymbol(value) {
+		return p.unexpected(fmt.Sprintf("'%v'", value))
+	}
+	p.next()
+	return nil
+}
+
+func (p *queryParser) unexpected(expected string) error {
+	actual := p.peek()
+	if actual.kind == endToken {
+		return fmt.Errorf("expected %v, but the query ended", expected)
+	}
+	return fmt.Errorf("expected %v, but got '%v'", expected, actual.value)
+}
+
+func (p *queryParser) parseQuery() (*Query, error) {
+	if p.peek().kind != wordToken {
+		return nil, p.unexpected("a kind of element")
+	}
+	kind, found := queryKinds[p.next().value]
+	if !found {
+		return nil, fmt.Errorf("unknown kind '%v'", p.tokens[p.position-1].value)
+	}
+	result := Query{kind: kind}
+	if p.isWord("where") {
+		p.next()
+		condition, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		result.condition = condition
+	}
+	if p.peek().kind != endToken {
+		return nil, p.unexpected("the end of the query")
+	}
+	return &result, nil
+}
+
+func (p *queryParser) parseOr() (condition, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isWord("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orCondition{left, right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (condition, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.isWord("and") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andCondition{left, right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseNot() (condition, error) {
+	if p.isWord("not") {
+		p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notCondition{operand}, nil
+	}
+	if p.isSymbol("(") {
+		p.next()
+		result, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return result, p.expectSymbol(")")
+	}
+	return p.parsePredicate()
+}
+
+func (p *queryParser) parsePredicate() (condition, error) {
+	if p.peek().kind != wordToken {
+		return nil, p.unexpected("a field or a relation")
+	}
+	name := p.next().value
+	if p.isSymbol("(") {
+		return p.parseRelation(name)
+	}
+	if !p.isSymbol("=") && !p.isSymbol("!=") && !p.isSymbol("~") {
+		return nil, p.unexpected("'=', '!=', or '~'")
+	}
+	operator := p.next().value
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	return fieldCondition{name, operator, value}, nil
+}
+
+func (p *queryParser) parseValue() (string, error) {
+	switch p.peek().kind {
+	case stringToken:
+		return p.next().value, nil
+	case wordToken:
+		value := p.next().value
+		if p.isSymbol(":") {
+			p.next()
+			if p.peek().kind != wordToken {
+				return "", p.unexpected("an ID")
+			}
+			value = value + ":" + p.next().value
+		}
+		return value, nil
+	default:
+		return "", p.unexpected("a value")
+	}
+}
+
+var relationKinds = map[string]bool{"uses": true, "calls": true, "dataStore": true}
+
+func (p *queryParser) parseRelation(name string) (condition, error) {
+	p.next()
+	kindToken := p.next()
+	kind, found := queryKinds[kindToken.value]
+	if kindToken.kind != wordToken || !found || kind == "*" || kind == "relationship" {
+		return nil, fmt.Errorf("expected a reference like service:api in %v(), but got '%v'", name, kindToken.value)
+	}
+	if err := p.expectSymbol(":"); err != nil {
+		return nil, err
+	}
+	if p.peek().kind != wordToken && p.peek().kind != stringToken {
+		return nil, p.unexpected("an ID")
+	}
+	target := reference{kind, p.next().value}
+	via := make(map[string]bool)
+	for p.isSymbol(",") {
+		p.next()
+		relationKind := p.next().value
+		if !relationKinds[relationKind] {
+			return nil, fmt.Errorf("unknown kind of relationship '%v': must be one of 'uses', 'calls', or 'dataStore'",
+				relationKind)
+		}
+		via[relationKind] = true
+	}
+	if err := p.expectSymbol(")"); err != nil {
+		return nil, err
+	}
+	switch name {
+	case "uses":
+		return usesCondition{target, func(*Relationship) bool { return true }}, nil
+	case "calls":
+		return usesCondition{target, func(r *Relationship) bool { return r.Kind() == "calls" }}, nil
+	case "reads":
+		return usesCondition{target, func(r *Relationship) bool { return r.DataFlow != Send }}, nil
+	case "writes":
+		return usesCondition{target, func(r *Relationship) bool { return r.DataFlow != Receive }}, nil
+	case "reaches":
+		return reachesCondition{target, via}, nil
+	case "involves":
+		return involvesCondition{target}, nil
+	default:
+		return nil, fmt.Errorf("unknown relation '%v'", name)
+	}
+}
+
+type andCondition struct {
+	left, right condition
+}
+
+func (c andCondition) matches(item interface{}, model *ArchitectureModel) bool {
+	return c.left.matches(item, model) && c.right.matches(item, model)
+}
+
+type orCondition struct {
+	left, right condition
+}
+
+func (c orCondition) matches(item interface{}, model *ArchitectureModel) bool {
+	return c.left.matches(item, model) || c.right.matches(item, model)
+}
+
+type notCondition struct {
+	operand condition
+}
+
+func (c notCondition) matches(item interface{}, model *ArchitectureModel) bool {
+	return !c.operand.matches(item, model)
+}
+
+type fieldCondition struct {
+	field    string
+	operator string
+	value    string
+}
+
+func (c fieldCondition) matches(item interface{}, _ *ArchitectureModel) bool {
+	values := fieldValuesOf(item, c.field)
+	switch c.operator {
+	case "=":
+		return contains(values, c.value)
+	case "!=":
+		return !contains(values, c.value)
+	default:
+		for _, value := range values {
+			if strings.Contains(strings.ToLower(value), strings.ToLower(c.value)) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldValuesOf returns the values of a field of an element or relationship. Fields may hold multiple values, like
+// technology, and may refer to fields of related elements, like technology.ring.
+func fieldValuesOf(item interface{}, field string) []string {
+	name, rest, nested := strings.Cut(field, ".")
+	if relationship, ok := item.(*Relationship); ok {
+		return relationshipFieldValuesOf(relationship, name, rest, nested)
+	}
+	switch name {
+	case "kind":
+		return []string{kindOf(item)}
+	case "id":
+		return []string{idOf(item)}
+	case "name":
+		return []string{nameOf(item)}
+	case "description":
+		if describable, ok := item.(Describable); ok {
+			return []string{describable.getDescription()}
+		}
+	case "state":
+		if state, found := stateOf(item); found {
+			return []string{state.Id()}
+		}
+	case "type":
+		if externalSystem, ok := item.(*ExternalSystem); ok {
+			return []string{externalSystem.Type}
+		}
+	case "quadrant":
+		if technology, ok := item.(*Technology); ok {
+			return []string{technology.Quadrant.String()}
+		}
+	case "ring":
+		if technology, ok := item.(*Technology); ok {
+			return []string{technology.Ring.String()}
+		}
+	case "technology":
+		return valuesOfTechnologies(technologiesOf(item), rest, nested)
+	case "owner":
+		if owner := ownerOf(item); owner != nil {
+			return []string{owner.Id}
+		}
+	case "tag", "property":
+		return valuesOfExtensions(extensionsOf(item), name, rest)
+	}
+	return []string{}
+}
+
+func valuesOfTechnologies(technologies []*Technology, field string, nested bool) []string {
+	if !nested {
+		field = "id"
+	}
+	result := make([]string, 0)
+	for _, technology := range technologies {
+		result = append(result, fieldValuesOf(technology, field)...)
+	}
+	return result
+}
+
+func relationshipFieldValuesOf(relationship *Relationship, name string, rest string, nested bool) []string {
+	switch name {
+	case "kind":
+		return []string{relationship.Kind()}
+	case "from", "to":
+		element := relationship.From
+		if name == "to" {
+			element = relationship.To
+		}
+		if nested {
+			return fieldValuesOf(element, rest)
+		}
+		return []string{refOf(element), idOf(element)}
+	case "description":
+		return []string{relationship.Description}
+	case "dataFlow":
+		return []string{relationship.DataFlow.String()}
+	case "technology":
+		return valuesOfTechnologies(relationship.Technologies, rest, nested)
+	case "tag", "property":
+		return valuesOfExtensions(relationship.Extensions, name, rest)
+	default:
+		return []string{}
+	}
+}
+
+// valuesOfExtensions returns the tags, or the value of the property with the given key.
+func valuesOfExtensions(extensions *Extensions, name string, key string) []string {
+	if extensions == nil {
+		return []string{}
+	}
+	if name == "tag" {
+		return extensions.Tags
+	}
+	if value, found := extensions.Properties[key]; found {
+		return []string{value}
+	}
+	return []string{}
+}
+
+type reference struct {
+	kind string
+	id   string
+}
+
+// isReferenceTo returns whether the element is the referenced element, or a form or view in it.
+func (r reference) isReferenceTo(element interface{}) bool {
+	if kindOf(element) == r.kind && idOf(element) == r.id {
+		return true
+	}
+	container := containerOf(element)
+	return container != element && kindOf(container) == r.kind && idOf(container) == r.id
+}
+
+type usesCondition struct {
+	target reference
+	accept func(relationship *Relationship) bool
+}
+
+func (c usesCondition) matches(item interface{}, model *ArchitectureModel) bool {
+	for _, relationship := range relationshipsFrom(model, item) {
+		if c.accept(relationship) && c.target.isReferenceTo(relationship.To) {
+			return true
+		}
+	}
+	return false
+}
+
+type reachesCondition struct {
+	target reference
+	via    map[string]bool
+}
+
+func (c reachesCondition) matches(item interface{}, model *ArchitectureModel) bool {
+	for _, reached := range reachableFrom(model, item, c.via) {
+		if c.target.isReferenceTo(reached) {
+			return true
+		}
+	}
+	return false
+}
+
+// reachableFrom returns all elements that can be reached from the given element by following relationships of the
+// given kinds, or of all kinds if none are given. Forms and views lead to their service and database.
+func reachableFrom(model *ArchitectureModel, start interface{}, via map[string]bool) []interface{} {
+	relationships := model.Relationships()
+	visited := map[interface{}]bool{start: true}
+	result := make([]interface{}, 0)
+	todo := []interface{}{start}
+	for len(todo) > 0 {
+		current := todo[0]
+		todo = todo[1:]
+		for _, relationship := range relationships {
+			if relationship.From != current || (len(via) > 0 && !via[relationship.Kind()]) {
+				continue
+			}
+			for _, next := range []interface{}{relationship.To, containerOf(relationship.To)} {
+				if !visited[next] {
+					visited[next] = true
+					result = append(result, next)
+					todo = append(todo, next)
+				}
+			}
+		}
+	}
+	return result
+}
+
+type involvesCondition struct {
+	target reference
+}
+
+func (c involvesCondition) matches(item interface{}, model *ArchitectureModel) bool {
+	workflow, ok := item.(*Workflow)
+	if !ok {
+		return false
+	}
+	for _, step := range workflow.Steps {
+		for _, element := range elementsOfStep(step) {
+			if c.target.isReferenceTo(element) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// elementsOfStep returns the performer of a step and the element the step acts on, if any.
+func elementsOfStep(step *Step) []interface{} {
+	result := make([]interface{}, 0)
+	for _, element := range []interface{}{step.Performer, step.Form, step.Service, step.ExternalSystem, step.Command,
+		step.Event} {
+		switch e := element.(type) {
+		case *Persona:
+			if e != nil {
+				result = append(result, e)
+			}
+		case *Form:
+			if e != nil {
+				result = append(result, e)
+			}
+		case *Service:
+			if e != nil {
+				result = append(result, e)
+			}
+		case *ExternalSystem:
+			if e != nil {
+				result = append(result, e)
+			}
+		case *Command:
+			if e != nil {
+				result = append(result, e)
+			}
+		case *Event:
+			if e != nil {
+				result = append(result, e)
+			}
+		}
+	}
+	return result
+}
+
+// PrintQueryResults prints the elements or relationships found by a query, either as a table or as JSON.
+func PrintQueryResults(results []interface{}, format string, printer *Printer) error {
+	rows := make([]map[string]string, 0)
+	for _, result := range results {
+		rows = append(rows, queryResultRow(result))
+	}
+	columns := []string{"kind", "id", "name", "state"}
+	if len(results) > 0 {
+		if _, ok := results[0].(*Relationship); ok {
+			columns = []string{"kind", "from", "to", "dataFlow", "description"}
+		}
+	}
+	switch format {
+	case "", "table":
+		printTable(columns, rows, printer)
+		return nil
+	case "json":
+		data, err := json.MarshalIndent(rows, "", "  ")
+		if err != nil {
+			return err
+		}
+		printer.PrintLn(string(data))
+		return nil
+	default:
+		return fmt.Errorf("unknown format '%v': must be one of 'table' or 'json'", format)
+	}
+}
+
+func queryResultRow(result interface{}) map[string]string {
+	if relationship, ok := result.(*Relationship); ok {
+		return map[string]string{
+			"kind":         relationship.Kind(),
+			"from":         refOf(relationship.From),
+			"to":           refOf(relationship.To),
+			"dataFlow":     relationship.DataFlow.String(),
+			"description":  relationship.Description,
+			"technologies": joinTechnologies(relationship.Technologies),
+		}
+	}
+	row := map[string]string{"kind": kindOf(result), "id": idOf(result), "name": nameOf(result)}
+	if state, found := stateOf(result); found {
+		row["state"] = state.Id()
+	}
+	return row
+}
+
+func printTable(columns []string, rows []map[string]string, printer *Printer) {
+	widths := make(map[string]int)
+	for _, column := range columns {
+		widths[column] = len(column)
+		for _, row := range rows {
+			if len(row[column]) > widths[column] {
+				widths[column] = len(row[column])
+			}
+		}
+	}
+	header := make(map[string]string)
+	for _, column := range columns {
+		header[column] = strings.ToUpper(column)
+	}
+	for _, row := range append([]map[string]string{header}, rows...) {
+		line := ""
+		for _, column := range columns {
+			line += fmt.Sprintf("%-*s  ", widths[column], row[column])
+		}
+		printer.PrintLn(strings.TrimRight(line, " "))
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"testing"
+)
+
+const queryDefinition = `personas:
+  dev:
+    uses:
+      - form: subscriptions
+  cs:
+    uses:
+      - view: requests
+        dataFlow: receive
+
+services:
+  api:
+    technologies: java
+    dataStores:
+      - database: subscriptions
+        dataFlow: send
+  console:
+    state: legacy
+    forms:
+      - subscriptions
+    calls:
+      - service: api
+  reporting:
+    technologies: cobol
+    tags:
+      - batch
+    properties:
+      costCenter: finance
+    dataStores:
+      - database: subscriptions
+        dataFlow: receive
+
+databases:
+  subscriptions:
+    views:
+      - requests
+
+technologies:
+  java:
+    quadrant: languagesAndFrameworks
+  cobol:
+    quadrant: languagesAndFrameworks
+    ring: hold
+
+workflows:
+  subscribe:
+    steps:
+      - performer: dev
+        form: subscriptions
+`
+
+func TestQueries(t *testing.T) {
+	model, issues := LintText(queryDefinition)
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+
+	cases := map[string][]string{
+		`service`:                                               {"service:api", "service:console", "service:reporting"},
+		`service where state = legacy`:                          {"service:console"},
+		`service where not state = legacy`:                      {"service:api", "service:reporting"},
+		`service where writes(database:subscriptions)`:          {"service:api"},
+		`service where reads(database:subscriptions)`:           {"service:reporting"},
+		`service where technology.ring = hold`:                  {"service:reporting"},
+		`service where calls(service:api) or technology = java`: {"service:api", "service:console"},
+		`persona where reaches(service:api)`:                    {"persona:dev"},
+		`persona where reaches(database:subscriptions, uses)`:   {"persona:cs"},
+		`persona where uses(service:console)`:                   {"persona:dev"},
+		`* where name ~ "SUBSCR"`:                               {"form:subscriptions", "database:subscriptions", "workflow:subscribe"},
+		`workflow where involves(service:console)`:              {"workflow:subscribe"},
+		`service where tag = batch`:                             {"service:reporting"},
+		`service where property.costCenter = finance`:           {"service:reporting"},
+	}
+	for text, expected := range cases {
+		query, err := ParseQuery(text)
+		if err != nil {
+			t.Fatalf("Failed to parse query `%v`: %v", text, err)
+		}
+
+		results := query.Run(model)
+
+		actual := make([]string, 0)
+		for _, result := range results {
+			actual = append(actual, refOf(result))
+		}
+		if !equalStrings(actual, expected) {
+			t.Errorf("Query `%v` returned %v instead of %v", text, actual, expected)
+		}
+	}
+}
+
+func equalStrings(actual []string, expected []string) bool {
+	if len(actual) != len(expected) {
+		return false
+	}
+	for index := range actual {
+		if actual[index] != expected[index] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRelationshipQuery(t *testing.T) {
+	model, _ := LintText(queryDefinition)
+	query, err := ParseQuery(`relationship where to = database:subscriptions and dataFlow != receive`)
+	if err != nil {
+		t.Fatalf("Failed to parse query: %v", err)
+	}
+
+	results := query.Run(model)
+
+	if len(results) != 1 {
+		t.Fatalf("Invalid # relationships: %v", len(results))
+	}
+	relationship := results[0].(*Relationship)
+	if relationship.From != model.Services[0] || relationship.Kind() != "dataStore" {
+		t.Errorf("Invalid relationship: %+v", relationship)
+	}
+}
+
+func TestInvalidQueries(t *testing.T) {
+	for _, text := range []string{
+		``,
+		`ape`,
+		`service where`,
+		`service where state`,
+		`service where state = `,
+		`service where calls(api)`,
+		`service where calls(service:api`,
+		`service where reaches(service:api, ape)`,
+		`service where ape(service:api)`,
+		`service where name = "api`,
+		`service state = ok`,
+	} {
+		_, err := ParseQuery(text)
+
+		if err == nil {
+			t.Errorf("No error for invalid query `%v`", text)
+		}
+	}
+}
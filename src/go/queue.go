@@ -8,7 +8,7 @@ type QueueReader struct {
 }
 
 func (q QueueReader) read(node *yaml.Node, _ string, model *ArchitectureModel) []Issue {
-	queues, issues := DataStoreReader{}.read(node)
+	queues, issues := DataStoreReader{dataStoreFields}.read(node)
 	model.Queues = queues
 	return issues
 }
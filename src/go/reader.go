@@ -3,6 +3,9 @@ package main
 import (
 	"fmt"
 	"gopkg.in/yaml.v3"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 type ModelPartReader interface {
@@ -81,6 +84,17 @@ func toString(node *yaml.Node, field string) (string, *Issue) {
 
 }
 
+func toNumber(node *yaml.Node, field string) (float64, *Issue) {
+	if node.Kind != yaml.ScalarNode {
+		return 0, NeedTypeError(field, node, "number")
+	}
+	result, err := strconv.ParseFloat(node.Value, 64)
+	if err != nil {
+		return 0, NodeError(fmt.Sprintf("%v must be a number, not '%v'", field, node.Value), node)
+	}
+	return result, nil
+}
+
 func enumFieldOf(owner *yaml.Node, fields map[string]*yaml.Node, field string, allowed []string, defaultValue string) (string, *Issue) {
 	value, found, issue := stringFieldOf(fields, field)
 	if issue != nil {
@@ -107,6 +121,87 @@ func hasDifferentValueThan(value string, allowed []string) bool {
 	return true
 }
 
+// checkFields warns about fields that aren't allowed, so that typos in field names don't go unnoticed.
+func checkFields(fields map[string]*yaml.Node, allowed []string) []Issue {
+	return checkFieldsWithHint(fields, allowed, "")
+}
+
+// checkFieldsWithHint warns about fields that aren't allowed. The warning suggests the allowed field that the unknown
+// field most likely is a typo of, or gives the hint when there's no such field.
+func checkFieldsWithHint(fields map[string]*yaml.Node, allowed []string, hint string) []Issue {
+	issues := make([]Issue, 0)
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !hasDifferentValueThan(name, allowed) {
+			continue
+		}
+		message := fmt.Sprintf("Unknown field '%v'", name)
+		if suggestion := closestTo(name, allowed); suggestion != "" {
+			message = fmt.Sprintf("%v: did you mean '%v'?", message, suggestion)
+		} else if hint != "" {
+			message = fmt.Sprintf("%v: %v", message, hint)
+		}
+		issues = append(issues, *NodeWarning(message, fields[name]))
+	}
+	return issues
+}
+
+// closestTo returns the candidate that is most similar to the value, ignoring case, or an empty string if none of the
+// candidates is similar enough to assume a typo.
+func closestTo(value string, candidates []string) string {
+	result := ""
+	maxDistance := len(value) / 3
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+	bestDistance := maxDistance + 1
+	for _, candidate := range candidates {
+		distance := editDistance(strings.ToLower(value), strings.ToLower(candidate))
+		if distance < bestDistance || distance == bestDistance && candidate < result {
+			result = candidate
+			bestDistance = distance
+		}
+	}
+	return result
+}
+
+// editDistance returns the number of characters to insert, delete, replace, or swap with their neighbor to turn one
+// string into the other.
+func editDistance(a, b string) int {
+	distances := make([][]int, len(a)+1)
+	for i := range distances {
+		distances[i] = make([]int, len(b)+1)
+		distances[i][0] = i
+	}
+	for j := range distances[0] {
+		distances[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			distance := distances[i-1][j-1] + cost
+			if distances[i-1][j]+1 < distance {
+				distance = distances[i-1][j] + 1
+			}
+			if distances[i][j-1]+1 < distance {
+				distance = distances[i][j-1] + 1
+			}
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] && distances[i-2][j-2]+1 < distance {
+				distance = distances[i-2][j-2] + 1
+			}
+			distances[i][j] = distance
+		}
+	}
+	return distances[len(a)][len(b)]
+}
+
 func stringsIn(values []string) string {
 	result := ""
 	for index, value := range values {
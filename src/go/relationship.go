@@ -0,0 +1,106 @@
+package main
+
+// Relationship is a directed connection between two model elements: a persona using something, a call, or a service
+// using a data store.
+type Relationship struct {
+	From         interface{}
+	To           interface{}
+	Description  string
+	DataFlow     DataFlow
+	Technologies []*Technology
+	// Extensions are those of the call, or nil for other relationships.
+	Extensions *Extensions
+}
+
+// Relationships returns all relationships in the model, in the order in which exporters print them: uses by personas,
+// calls by external systems, and calls and data store uses by services.
+func (model ArchitectureModel) Relationships() []*Relationship {
+	result := make([]*Relationship, 0)
+	for _, persona := range model.Personas {
+		for _, used := range persona.Uses {
+			to := used.Used()
+			if used.View != nil {
+				to = used.View
+			}
+			result = append(result, &Relationship{persona, to, used.Description, used.DataFlow, []*Technology{}, nil})
+		}
+	}
+	for _, externalSystem := range model.ExternalSystems {
+		for _, call := range externalSystem.Calls {
+			result = append(result, callRelationship(externalSystem, call))
+		}
+	}
+	for _, service := range model.Services {
+		for _, call := range service.Calls {
+			result = append(result, callRelationship(service, call))
+		}
+		for _, use := range service.DataStores {
+			result = append(result, dataStoreRelationship(service, use))
+		}
+	}
+	return result
+}
+
+// Kind returns uses for relationships that start at a persona, dataStore for relationships that end at a database or
+// queue, and calls for the rest.
+func (r *Relationship) Kind() string {
+	if _, found := r.From.(*Persona); found {
+		return "uses"
+	}
+	switch r.To.(type) {
+	case *Database, *DataStore:
+		return "dataStore"
+	default:
+		return "calls"
+	}
+}
+
+func relationshipsFrom(model *ArchitectureModel, element interface{}) []*Relationship {
+	result := make([]*Relationship, 0)
+	for _, relationship := range model.Relationships() {
+		if relationship.From == element {
+			result = append(result, relationship)
+		}
+	}
+	return result
+}
+
+func callRelationship(caller interface{}, call *Call) *Relationship {
+	return &Relationship{caller, call.Callee(), call.Description, call.DataFlow, call.UsedTechnologies(),
+		&call.Extensions}
+}
+
+func dataStoreRelationship(service *Service, use *DataStoreUse) *Relationship {
+	if use.Database != nil {
+		return &Relationship{service, use.Database, use.Description, use.DataFlow, use.Database.ApiTechnologies, nil}
+	}
+	return &Relationship{service, use.Queue, use.Description, use.DataFlow, use.Queue.ApiTechnologies, nil}
+}
+
+// nodeIdOf returns the ID of the diagram node that represents the given model element. Databases and queues get a
+// suffix, since their IDs may clash with those of services. Forms and commands are represented by the service that
+// implements them, and views by the database that holds them.
+func nodeIdOf(element interface{}) string {
+	switch e := element.(type) {
+	case *Persona:
+		return e.Id
+	case *ExternalSystem:
+		return e.Id
+	case *Service:
+		return e.Id
+	case *Form:
+		return e.ImplementedBy.Id
+	case *Command:
+		return e.HandledBy.Id
+	case *Database:
+		return e.Id + "_db"
+	case *View:
+		return e.On.Id + "_db"
+	case *DataStore:
+		return e.Id + "_q"
+	case *Technology:
+		return e.Id + "_tech"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,366 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// manifestParsers find the dependencies in the manifests of a repository, by the name of the manifest. Dependencies are
+// written the way the manifest has them, like github.com/spf13/cobra, org.springframework.boot:spring-boot, or react.
+var manifestParsers = map[string]func(data []byte) ([]string, error){
+	"go.mod":           goModDependencies,
+	"package.json":     packageJsonDependencies,
+	"pom.xml":          pomDependencies,
+	"build.gradle":     gradleDependencies,
+	"build.gradle.kts": gradleDependencies,
+	"requirements.txt": requirementsDependencies,
+	"Dockerfile":       dockerfileDependencies,
+}
+
+// languageManifests map manifests to the languages they're written for. They apply to models with a technology with
+// the ID or name of the language, unless the mapping has the manifest.
+var languageManifests = map[string]string{
+	"go.mod":           "go",
+	"package.json":     "javascript",
+	"pom.xml":          "java",
+	"build.gradle":     "java",
+	"build.gradle.kts": "kotlin",
+	"requirements.txt": "python",
+}
+
+// knownLanguages are programming languages, with their IDs normalized. They don't show up in manifests as
+// dependencies, so only manifests can tell which languages a repository uses.
+var knownLanguages = []string{"c", "c#", "c++", "csharp", "go", "golang", "java", "javascript", "kotlin", "php",
+	"python", "ruby", "rust", "scala", "swift", "typescript"}
+
+// ignoredDirectories hold dependencies or build output rather than manifests of the repository itself.
+var ignoredDirectories = []string{"node_modules", "vendor", "target"}
+
+// ScanRepository returns the dependencies in the manifests of a repository, in any of its directories. The names of the
+// manifests, like go.mod or Dockerfile, are dependencies as well, so that they can map to a language or a platform.
+func ScanRepository(directory string) ([]string, error) {
+	found := make(map[string]bool)
+	err := filepath.WalkDir(directory, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := entry.Name()
+		if entry.IsDir() {
+			if path != directory && (strings.HasPrefix(name, ".") || !hasDifferentValueThan(name, ignoredDirectories)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		manifest := manifestOf(name)
+		parser, known := manifestParsers[manifest]
+		if !known {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		dependencies, err := parser(data)
+		if err != nil {
+			return fmt.Errorf("invalid %v: %v", path, err)
+		}
+		found[manifest] = true
+		for _, dependency := range dependencies {
+			found[dependency] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sortedKeys(found), nil
+}
+
+// manifestOf returns the kind of manifest that a file is, which is the name of the file except for Dockerfiles, which
+// may also be named like Dockerfile.dev or api.Dockerfile.
+func manifestOf(name string) string {
+	if strings.HasPrefix(name, "Dockerfile.") || strings.HasSuffix(name, ".Dockerfile") {
+		return "Dockerfile"
+	}
+	return name
+}
+
+func goModDependencies(data []byte) ([]string, error) {
+	result := make([]string, 0)
+	inRequire := false
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.Contains(line, "// indirect") {
+			continue
+		}
+		fields := strings.Fields(strings.SplitN(line, "//", 2)[0])
+		switch {
+		case len(fields) == 0:
+		case inRequire && fields[0] == ")":
+			inRequire = false
+		case inRequire:
+			result = append(result, fields[0])
+		case fields[0] == "require" && len(fields) > 1:
+			if fields[1] == "(" {
+				inRequire = true
+			} else {
+				result = append(result, fields[1])
+			}
+		}
+	}
+	return result, nil
+}
+
+func packageJsonDependencies(data []byte) ([]string, error) {
+	var pkg struct {
+		Dependencies     map[string]string `json:"dependencies"`
+		DevDependencies  map[string]string `json:"devDependencies"`
+		PeerDependencies map[string]string `json:"peerDependencies"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, err
+	}
+	result := make([]string, 0)
+	for _, dependencies := range []map[string]string{pkg.Dependencies, pkg.DevDependencies, pkg.PeerDependencies} {
+		result = append(result, sortedKeys(dependencies)...)
+	}
+	return result, nil
+}
+
+type pomArtifact struct {
+	GroupId    string `xml:"groupId"`
+	ArtifactId string `xml:"artifactId"`
+}
+
+func pomDependencies(data []byte) ([]string, error) {
+	var pom struct {
+		Parent       pomArtifact   `xml:"parent"`
+		Dependencies []pomArtifact `xml:"dependencies>dependency"`
+		Plugins      []pomArtifact `xml:"build>plugins>plugin"`
+	}
+	if err := xml.Unmarshal(data, &pom); err != nil {
+		return nil, err
+	}
+	result := make([]string, 0)
+	for _, artifact := range append(append([]pomArtifact{pom.Parent}, pom.Dependencies...), pom.Plugins...) {
+		if artifact.ArtifactId != "" {
+			result = append(result, artifact.GroupId+":"+artifact.ArtifactId)
+		}
+	}
+	return result, nil
+}
+
+var (
+	gradleDependency = regexp.MustCompile(`^\s*\w+\s*\(?\s*['"]([^'":\s]+:[^'":\s]+)`)
+	gradlePlugin     = regexp.MustCompile(`^\s*id\s*\(?\s*['"]([^'"]+)['"]`)
+)
+
+// gradleDependencies finds the dependencies that are declared like implementation 'group:artifact:version' and the
+// plugins that are applied like id 'org.springframework.boot', in both the Groovy and the Kotlin syntax.
+func gradleDependencies(data []byte) ([]string, error) {
+	result := make([]string, 0)
+	for _, line := range strings.Split(string(data), "\n") {
+		if match := gradleDependency.FindStringSubmatch(line); match != nil {
+			result = append(result, match[1])
+		} else if match = gradlePlugin.FindStringSubmatch(line); match != nil {
+			result = append(result, match[1])
+		}
+	}
+	return result, nil
+}
+
+func requirementsDependencies(data []byte) ([]string, error) {
+	result := make([]string, 0)
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(strings.SplitN(line, "#", 2)[0])
+		if line == "" || strings.HasPrefix(line, "-") {
+			continue
+		}
+		if end := strings.IndexAny(line, "[<>=!~;@ "); end >= 0 {
+			line = line[:end]
+		}
+		result = append(result, strings.ToLower(line))
+	}
+	return result, nil
+}
+
+// dockerfileDependencies returns the base images of a Dockerfile, without their tags, and without the earlier stages
+// of a multi-stage build.
+func dockerfileDependencies(data []byte) ([]string, error) {
+	result := make([]string, 0)
+	stages := make(map[string]bool)
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !strings.EqualFold(fields[0], "FROM") {
+			continue
+		}
+		arguments := fields[1:]
+		for len(arguments) > 1 && strings.HasPrefix(arguments[0], "--") {
+			arguments = arguments[1:]
+		}
+		image := arguments[0]
+		if !stages[strings.ToLower(image)] && image != "scratch" {
+			image = strings.SplitN(image, "@", 2)[0]
+			if tag := strings.LastIndex(image, ":"); tag > strings.LastIndex(image, "/") {
+				image = image[:tag]
+			}
+			result = append(result, image)
+		}
+		if len(arguments) >= 3 && strings.EqualFold(arguments[1], "AS") {
+			stages[strings.ToLower(arguments[2])] = true
+		}
+	}
+	return result, nil
+}
+
+// TechnologyScan compares the technologies that services declare with those that their repositories use. Findings are
+// written like service:api: react.
+type TechnologyScan struct {
+	Undeclared []string
+	// Unused are the declared technologies that a scan could find, but didn't.
+	Unused []string
+	// OnHold are the used technologies that are in the hold ring.
+	OnHold []string
+}
+
+// ScanTechnologies scans the repositories of the services of a model, relative to the given directory. A dependency
+// maps to the technology that the mapping gives, where a name that ends in * matches all dependencies that start with
+// the rest of the name, or else to the technology whose ID or name matches the dependency or its last part after / or
+// :, ignoring case, dashes, underscores, and dots. Manifests map to their languages, unless the mapping has them.
+// Only technologies in the mapping and frameworks in the languagesAndFrameworks quadrant are reported as unused, since
+// other technologies, like techniques and languages without a manifest, don't show in manifests.
+func ScanTechnologies(model *ArchitectureModel, directory string, mapping map[string]string) (*TechnologyScan, error) {
+	scannable := make(map[*Technology]bool)
+	for _, dependency := range sortedKeys(mapping) {
+		if _, found := lookUpTechnology(model, mapping[dependency]); !found {
+			return nil, fmt.Errorf("invalid mapping for '%v': unknown technology '%v'", dependency, mapping[dependency])
+		}
+	}
+	mapping = withLanguageManifests(model, mapping)
+	for _, technology := range model.Technologies {
+		if technology.Quadrant == LanguagesAndFrameworks && !isLanguage(technology) {
+			scannable[technology] = true
+		}
+	}
+	for _, id := range mapping {
+		technology, _ := lookUpTechnology(model, id)
+		scannable[technology] = true
+	}
+	result := &TechnologyScan{make([]string, 0), make([]string, 0), make([]string, 0)}
+	for _, service := range model.Services {
+		if service.Repository == "" {
+			continue
+		}
+		repository := service.Repository
+		if !filepath.IsAbs(repository) {
+			repository = filepath.Join(directory, repository)
+		}
+		dependencies, err := ScanRepository(repository)
+		if err != nil {
+			return nil, fmt.Errorf("can't scan the repository of %v: %v", refOf(service), err)
+		}
+		used := make(map[*Technology]bool)
+		for _, dependency := range dependencies {
+			if technology := technologyOfDependency(model, dependency, mapping); technology != nil {
+				used[technology] = true
+			}
+		}
+		declared := make(map[*Technology]bool)
+		for _, technology := range service.Technologies {
+			declared[technology] = true
+			if scannable[technology] && !used[technology] {
+				result.Unused = append(result.Unused, refOf(service)+": "+technology.Id)
+			}
+		}
+		for technology := range used {
+			if !declared[technology] {
+				result.Undeclared = append(result.Undeclared, refOf(service)+": "+technology.Id)
+			}
+			if technology.Ring == Hold {
+				result.OnHold = append(result.OnHold, refOf(service)+": "+technology.Id)
+			}
+		}
+	}
+	sort.Strings(result.Undeclared)
+	sort.Strings(result.Unused)
+	sort.Strings(result.OnHold)
+	return result, nil
+}
+
+// withLanguageManifests returns the mapping with the manifests of the languages of the model that it doesn't have yet.
+func withLanguageManifests(model *ArchitectureModel, mapping map[string]string) map[string]string {
+	result := make(map[string]string)
+	for manifest, language := range languageManifests {
+		if technology := technologyNamed(model, language); technology != nil {
+			result[manifest] = technology.Id
+		}
+	}
+	for dependency, id := range mapping {
+		result[dependency] = id
+	}
+	return result
+}
+
+// isLanguage returns whether a technology is a programming language, which only a manifest can show.
+func isLanguage(technology *Technology) bool {
+	for _, language := range knownLanguages {
+		if normalizedId(technology.Id) == language || normalizedId(technology.Name) == language {
+			return true
+		}
+	}
+	return false
+}
+
+func technologyOfDependency(model *ArchitectureModel, dependency string, mapping map[string]string) *Technology {
+	if id, found := mapping[dependency]; found {
+		technology, _ := lookUpTechnology(model, id)
+		return technology
+	}
+	prefix := ""
+	for _, name := range sortedKeys(mapping) {
+		if strings.HasSuffix(name, "*") && strings.HasPrefix(dependency, strings.TrimSuffix(name, "*")) &&
+			len(name) > len(prefix) {
+			prefix = name
+		}
+	}
+	if prefix != "" {
+		technology, _ := lookUpTechnology(model, mapping[prefix])
+		return technology
+	}
+	names := []string{dependency}
+	if last := strings.LastIndexAny(dependency, "/:"); last >= 0 {
+		names = append(names, dependency[last+1:])
+	}
+	return technologyNamed(model, names...)
+}
+
+// technologyNamed returns the technology whose ID or name matches one of the given names, ignoring case, dashes,
+// underscores, and dots, or nil if there is none.
+func technologyNamed(model *ArchitectureModel, names ...string) *Technology {
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		normalized = append(normalized, normalizedId(name))
+	}
+	for _, technology := range model.Technologies {
+		if !hasDifferentValueThan(normalizedId(technology.Id), normalized) ||
+			!hasDifferentValueThan(normalizedId(technology.Name), normalized) {
+			return technology
+		}
+	}
+	return nil
+}
+
+// Print prints the differences between the declared and the used technologies.
+func (s *TechnologyScan) Print(printer *Printer) {
+	printFindings(printer, "The repositories match the declared technologies", []findings{
+		{"Used, but not declared:", s.Undeclared},
+		{"Declared, but not used:", s.Unused},
+		{"Used, but on hold:", s.OnHold},
+	})
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestManifestDependencies(t *testing.T) {
+	manifests := []struct {
+		name     string
+		text     string
+		expected []string
+	}{
+		{"go.mod", `module example.com/api
+
+go 1.19
+
+require github.com/spf13/cobra v1.6.1
+
+require (
+	gopkg.in/yaml.v3 v3.0.1 // Comment
+	github.com/inconshreveable/mousetrap v1.0.1 // indirect
+)
+`, []string{"github.com/spf13/cobra", "gopkg.in/yaml.v3"}},
+		{"package.json", `{"name": "web", "dependencies": {"react": "^18.2.0", "axios": "1.2.0"},
+  "devDependencies": {"jest": "29.3.1"}}`, []string{"axios", "react", "jest"}},
+		{"pom.xml", `<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <parent>
+    <groupId>org.springframework.boot</groupId>
+    <artifactId>spring-boot-starter-parent</artifactId>
+  </parent>
+  <dependencies>
+    <dependency>
+      <groupId>org.postgresql</groupId>
+      <artifactId>postgresql</artifactId>
+    </dependency>
+  </dependencies>
+</project>`, []string{"org.springframework.boot:spring-boot-starter-parent", "org.postgresql:postgresql"}},
+		{"build.gradle", `plugins {
+    id 'org.springframework.boot' version '3.0.1'
+}
+
+dependencies {
+    implementation 'org.springframework.boot:spring-boot-starter-web'
+    testImplementation("org.junit.jupiter:junit-jupiter:5.9.1")
+}
+`, []string{"org.springframework.boot", "org.springframework.boot:spring-boot-starter-web",
+			"org.junit.jupiter:junit-jupiter"}},
+		{"requirements.txt", `# Web
+Flask[async]==2.2.2
+requests>=2.28
+-r common.txt
+`, []string{"flask", "requests"}},
+		{"Dockerfile", `FROM --platform=linux/amd64 golang:1.19 AS build
+FROM build AS test
+FROM gcr.io/distroless/static@sha256:abc
+FROM localhost:5000/base
+`, []string{"golang", "gcr.io/distroless/static", "localhost:5000/base"}},
+	}
+	for _, manifest := range manifests {
+		actual, err := manifestParsers[manifest.name]([]byte(manifest.text))
+		if err != nil {
+			t.Errorf("%v: %v", manifest.name, err)
+		} else if !reflect.DeepEqual(actual, manifest.expected) {
+			t.Errorf("%v: expected %v but got %v", manifest.name, manifest.expected, actual)
+		}
+	}
+}
+
+const scanDefinition = `services:
+  api:
+    repository: api
+    technologies:
+      - go
+      - java
+      - https
+  web:
+    repository: web
+    technologies:
+      - react
+      - typescript
+  worker: {}
+
+technologies:
+  go:
+    quadrant: languagesAndFrameworks
+  java:
+    quadrant: languagesAndFrameworks
+  https:
+    quadrant: techniques
+  react:
+    quadrant: languagesAndFrameworks
+  typescript:
+    name: TypeScript
+    quadrant: languagesAndFrameworks
+  jquery:
+    quadrant: languagesAndFrameworks
+    ring: hold
+  docker:
+    quadrant: platforms
+`
+
+func TestScanTechnologies(t *testing.T) {
+	model, issues := LintText(scanDefinition)
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+	directory := t.TempDir()
+	files := map[string]string{
+		"api/go.mod":                    "module example.com/api\n",
+		"api/deploy/Dockerfile":         "FROM golang:1.19\n",
+		"web/package.json":              `{"dependencies": {"react": "18.2.0", "jquery": "3.6.3"}}`,
+		"web/node_modules/x/pom.xml":    "<project/>",
+		"web/.cache/requirements.txt":   "flask\n",
+		"web/public/index.html":         "<html/>",
+		"web/docker/Dockerfile.release": "FROM nginx:1.23\n",
+	}
+	for name, text := range files {
+		path := filepath.Join(directory, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scan, err := ScanTechnologies(model, directory, map[string]string{"ngin*": "docker"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &TechnologyScan{
+		Undeclared: []string{"service:web: docker", "service:web: jquery"},
+		Unused:     []string{"service:api: java"},
+		OnHold:     []string{"service:web: jquery"},
+	}
+	if !reflect.DeepEqual(scan, expected) {
+		t.Errorf("Expected %+v but got %+v", expected, scan)
+	}
+
+	scan, err = ScanTechnologies(model, directory, map[string]string{"package.json": "typescript"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(scan.Unused, []string{"service:api: java"}) ||
+		!reflect.DeepEqual(scan.Undeclared, []string{"service:web: jquery"}) {
+		t.Errorf("Expected a mapping to override the language of a manifest, but got %+v", scan)
+	}
+
+	_, err = ScanTechnologies(model, directory, map[string]string{"go.mod": "golang"})
+	if err == nil {
+		t.Errorf("Expected error for unknown technology in mapping")
+	}
+	_, err = ScanTechnologies(model, filepath.Join(directory, "missing"), nil)
+	if err == nil {
+		t.Errorf("Expected error for missing repository")
+	}
+}
@@ -15,6 +15,8 @@ type DataStoreUse struct {
 	DataFlow    DataFlow
 }
 
+var dataStoreUseFields = []string{"database", "queue", "description", "dataFlow"}
+
 func (d *DataStoreUse) read(node *yaml.Node) []Issue {
 	fields, issue := toMap(node)
 	if issue != nil {
@@ -24,6 +26,7 @@ func (d *DataStoreUse) read(node *yaml.Node) []Issue {
 	issues = append(issues, d.readDataStore(node, fields)...)
 	issues = append(issues, setDescription(fields, d)...)
 	issues = append(issues, setDataFlow(node, fields, d)...)
+	issues = append(issues, checkFields(fields, dataStoreUseFields)...)
 	return issues
 }
 
@@ -67,6 +70,9 @@ type Form struct {
 	Name          string
 	State         State
 	ImplementedBy *Service
+	OwnerId       string
+	Owner         *Team
+	Extensions
 }
 
 func (f *Form) setNode(node *yaml.Node) {
@@ -85,6 +91,22 @@ func (f *Form) setState(state State) {
 	f.State = state
 }
 
+func (f *Form) getNode() *yaml.Node {
+	return f.node
+}
+
+func (f *Form) getOwnerId() string {
+	return f.OwnerId
+}
+
+func (f *Form) setOwnerId(id string) {
+	f.OwnerId = id
+}
+
+func (f *Form) setOwner(team *Team) {
+	f.Owner = team
+}
+
 type Service struct {
 	node               *yaml.Node
 	Id                 string
@@ -92,18 +114,22 @@ type Service struct {
 	Description        string
 	DataStores         []*DataStoreUse
 	Forms              []*Form
+	Commands           []*Command
+	Interfaces         []*Interface
 	Calls              []*Call
 	TechnologyIds      []string
 	TechnologyBundleId string
 	State              State
 	Technologies       []*Technology
-}
-
-func (s *Service) Print(printer *Printer) {
-	printer.Print(s.Name)
-	s.State.Print(printer)
-	PrintTechnologies(s.Technologies, printer)
-	printer.NewLine()
+	OwnerId            string
+	Owner              *Team
+	PublishedEventIds  []string
+	PublishedEvents    []*Event
+	SubscribedEventIds []string
+	SubscribedEvents   []*Event
+	// Repository is the local directory with the source code of the service, relative to the model file.
+	Repository string
+	Extensions
 }
 
 func (s *Service) getDescription() string {
@@ -134,15 +160,32 @@ func (s *Service) setTechnologies(technologies []*Technology) {
 	s.Technologies = technologies
 }
 
+var serviceFields = []string{"name", "description", "dataStores", "forms", "commands", "interfaces", "calls",
+	"technologies", "state", "owner", "publishes", "subscribes", "repository"}
+
+var formFields = []string{"name", "state", "owner"}
+
 func (s *Service) read(id string, node *yaml.Node) []Issue {
 	var fields map[string]*yaml.Node
 	fields, issues := namedObject(node, id, s)
 	issues = append(issues, s.readDataStores(fields)...)
 	issues = append(issues, s.readForms(fields)...)
+	issues = append(issues, s.readCommands(fields)...)
+	issues = append(issues, s.readInterfaces(fields)...)
 	issues = append(issues, s.readCalls(fields)...)
 	issues = append(issues, setDescription(fields, s)...)
 	issues = append(issues, setTechnologies(fields, s)...)
 	issues = append(issues, setState(node, fields, s)...)
+	issues = append(issues, setOwner(fields, s)...)
+	issues = append(issues, readStrings(fields, "publishes", nil, &s.PublishedEventIds)...)
+	issues = append(issues, readStrings(fields, "subscribes", nil, &s.SubscribedEventIds)...)
+	repository, found, issue := stringFieldOf(fields, "repository")
+	if issue != nil {
+		issues = append(issues, *issue)
+	} else if found {
+		s.Repository = repository
+	}
+	issues = append(issues, checkExtensibleFields(fields, serviceFields)...)
 	return issues
 }
 
@@ -198,9 +241,11 @@ func (s *Service) readForms(fields map[string]*yaml.Node) []Issue {
 		for formId, formNode := range formMaps {
 			form := Form{}
 			forms = append(forms, &form)
-			formFields, formIssues := namedObject(formNode, formId, &form)
+			fields, formIssues := namedObject(formNode, formId, &form)
 			issues = append(issues, formIssues...)
-			issues = append(issues, setState(formNode, formFields, &form)...)
+			issues = append(issues, setState(formNode, fields, &form)...)
+			issues = append(issues, setOwner(fields, &form)...)
+			issues = append(issues, checkExtensibleFields(fields, formFields)...)
 		}
 		s.Forms = forms
 	} else if found {
@@ -240,6 +285,18 @@ func (s *Service) setState(state State) {
 	s.State = state
 }
 
+func (s *Service) getOwnerId() string {
+	return s.OwnerId
+}
+
+func (s *Service) setOwnerId(id string) {
+	s.OwnerId = id
+}
+
+func (s *Service) setOwner(team *Team) {
+	s.Owner = team
+}
+
 func (s *Service) findFormById(id string) (*Form, bool) {
 	for _, candidate := range s.Forms {
 		if candidate.Id == id {
@@ -296,10 +353,17 @@ func (s ServiceConnector) connect(model *ArchitectureModel) []Issue {
 		for _, form := range service.Forms {
 			form.ImplementedBy = service
 		}
+		for _, command := range service.Commands {
+			command.HandledBy = service
+		}
 		issues = append(issues, connectTechnologies(service, model)...)
+		for _, iface := range service.Interfaces {
+			issues = append(issues, connectTechnologies(iface, model)...)
+		}
 		for _, call := range service.Calls {
 			issues = append(issues, connectTechnologies(call, model)...)
 			issues = append(issues, s.connectCall(call, model)...)
+			issues = append(issues, connectInterface(call)...)
 		}
 		issues = append(issues, s.connectDataStores(service, model)...)
 	}
@@ -365,6 +429,8 @@ type ServiceValidator struct {
 func (s ServiceValidator) validate(model *ArchitectureModel) []Issue {
 	issues := make([]Issue, 0)
 	issues = append(issues, s.validateFormsAreUnique(model.Services)...)
+	issues = append(issues, s.validateCommandsAreUnique(model.Services)...)
+	issues = append(issues, s.validateInterfacesAreCalled(model)...)
 	return issues
 }
 
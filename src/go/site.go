@@ -0,0 +1,432 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// sitePage is the content of a page of the documentation site, for the index or for a model element.
+type sitePage struct {
+	SystemName   string
+	Title        string
+	Kind         string
+	Description  string
+	State        string
+	StateColor   string
+	Owner        string
+	Technologies []siteLink
+	Diagram      template.HTML
+	Sections     []siteSection
+}
+
+type siteSection struct {
+	Title string
+	Links []siteLink
+}
+
+// siteLink is a link to the page of an element, with an optional note, like the description of a relationship.
+type siteLink struct {
+	Name string
+	Href string
+	Note string
+}
+
+var sitePageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}} - {{.SystemName}}</title>
+<link rel="stylesheet" href="site.css">
+</head>
+<body>
+<nav><a href="index.html">{{.SystemName}}</a></nav>
+<main>
+<h1>{{.Title}}{{if .Kind}} <small>{{.Kind}}</small>{{end}}</h1>
+{{- if .State}}
+<p><span class="state" style="background: {{.StateColor}}">{{.State}}</span></p>
+{{- end}}
+{{- if .Description}}
+<p>{{.Description}}</p>
+{{- end}}
+{{- if .Owner}}
+<p>Owned by {{.Owner}}</p>
+{{- end}}
+{{- if .Technologies}}
+<p>Technologies: {{range $index, $technology := .Technologies}}{{if $index}}, {{end}}<a href="{{$technology.Href}}">
+{{- $technology.Name}}</a>{{end}}</p>
+{{- end}}
+{{- if .Diagram}}
+<figure>{{.Diagram}}</figure>
+{{- end}}
+{{- range .Sections}}
+<h2>{{.Title}}</h2>
+<ul>
+{{- range .Links}}
+<li>{{if .Href}}<a href="{{.Href}}">{{.Name}}</a>{{else}}{{.Name}}{{end}}{{if .Note}} - {{.Note}}{{end}}</li>
+{{- end}}
+</ul>
+{{- end}}
+</main>
+</body>
+</html>
+`))
+
+const siteStyleSheet = `body { font-family: sans-serif; margin: 0; color: #222; }
+nav { background: #3966a0; padding: 0.5em 1em; }
+nav a { color: white; font-weight: bold; text-decoration: none; }
+main { max-width: 60em; margin: 1em auto; padding: 0 1em; }
+h1 small { color: #777; font-size: 50%; font-weight: normal; }
+.state { padding: 0.1em 0.5em; border-radius: 0.3em; }
+figure { margin: 1em 0; overflow-x: auto; }
+`
+
+// WriteSite writes a static HTML site that documents a model to a directory: an index with the system, and a page for
+// each persona, external system, service, database, queue, technology, and workflow. Pages link to each other and
+// show a diagram of the element with its neighbors. The site doesn't need anything outside the directory.
+func WriteSite(model *ArchitectureModel, directory string) error {
+	err := os.MkdirAll(directory, 0755)
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(filepath.Join(directory, "site.css"), []byte(siteStyleSheet), 0666)
+	if err != nil {
+		return err
+	}
+	err = writeSitePage(filepath.Join(directory, "index.html"), siteIndexOf(model))
+	if err != nil {
+		return err
+	}
+	for _, element := range model.Elements() {
+		if hasPage(element) {
+			err = writeSitePage(filepath.Join(directory, pageOf(element)), sitePageOf(model, element))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func writeSitePage(fileName string, page sitePage) error {
+	var builder strings.Builder
+	err := sitePageTemplate.Execute(&builder, page)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(fileName, []byte(builder.String()), 0666)
+}
+
+// siteKinds are the kinds of elements that have pages, with their names, in the order of the index.
+var siteKinds = []struct {
+	kind   string
+	name   string
+	plural string
+}{
+	{"persona", "Persona", "Personas"},
+	{"externalSystem", "External system", "External systems"},
+	{"service", "Service", "Services"},
+	{"database", "Database", "Databases"},
+	{"queue", "Queue", "Queues"},
+	{"technology", "Technology", "Technologies"},
+	{"workflow", "Workflow", "Workflows"},
+}
+
+func siteKindOf(element interface{}) int {
+	for index, candidate := range siteKinds {
+		if candidate.kind == kindOf(element) {
+			return index
+		}
+	}
+	return -1
+}
+
+func hasPage(element interface{}) bool {
+	return siteKindOf(element) >= 0
+}
+
+// pageOf returns the file name of the page of an element. Forms and commands are documented on the page of their
+// service, and views on the page of their database.
+func pageOf(element interface{}) string {
+	container := containerOf(element)
+	return kindOf(container) + "-" + idOf(container) + ".html"
+}
+
+func siteLinkOf(element interface{}, note string) siteLink {
+	if !hasPage(containerOf(element)) {
+		return siteLink{nameOf(element), "", note}
+	}
+	return siteLink{nameOf(element), url.PathEscape(pageOf(element)), note}
+}
+
+func siteIndexOf(model *ArchitectureModel) sitePage {
+	result := sitePage{SystemName: model.System.Name, Title: model.System.Name}
+	sections := make([]siteSection, len(siteKinds))
+	for index, kind := range siteKinds {
+		sections[index].Title = kind.plural
+	}
+	for _, element := range model.Elements() {
+		if index := siteKindOf(element); index >= 0 {
+			sections[index].Links = append(sections[index].Links, siteLinkOf(element, descriptionOf(element)))
+		}
+	}
+	for _, section := range sections {
+		if len(section.Links) > 0 {
+			result.Sections = append(result.Sections, section)
+		}
+	}
+	return result
+}
+
+func descriptionOf(element interface{}) string {
+	if describable, ok := element.(Describable); ok {
+		return describable.getDescription()
+	}
+	return ""
+}
+
+func sitePageOf(model *ArchitectureModel, element interface{}) sitePage {
+	result := sitePage{
+		SystemName:  model.System.Name,
+		Title:       nameOf(element),
+		Kind:        siteKinds[siteKindOf(element)].name,
+		Description: descriptionOf(element),
+	}
+	if state, found := stateOf(element); found {
+		result.State = state.Id()
+		result.StateColor = model.theme().ColorOf(state)
+	}
+	if owner := ownerOf(element); owner != nil {
+		result.Owner = owner.Name
+	}
+	if _, ok := element.(*Technology); !ok {
+		for _, technology := range technologiesOf(element) {
+			result.Technologies = append(result.Technologies, siteLinkOf(technology, ""))
+		}
+	}
+	var incoming, outgoing []siteNeighbor
+	switch e := element.(type) {
+	case *Technology:
+		incoming, result.Sections = usersOfTechnology(model, e)
+	case *Workflow:
+		outgoing, result.Sections = stepsOf(e)
+	default:
+		incoming, outgoing, result.Sections = relationshipsOf(model, element)
+	}
+	result.Diagram = focusedDiagram(model.theme(), element, incoming, outgoing)
+	if workflows := siteWorkflowsOf(model, element); len(workflows) > 0 {
+		result.Sections = append(result.Sections, siteSection{"Appears in workflows", workflows})
+	}
+	return result
+}
+
+// relationshipsOf returns the elements that have a relationship with the given element, and the sections that list
+// them, like calls and called by.
+func relationshipsOf(model *ArchitectureModel, element interface{}) ([]siteNeighbor, []siteNeighbor, []siteSection) {
+	titles := []string{"Calls", "Uses", "Called by", "Used by"}
+	links := make(map[string][]siteLink)
+	incoming := make([]siteNeighbor, 0)
+	outgoing := make([]siteNeighbor, 0)
+	for _, relationship := range model.Relationships() {
+		to := containerOf(relationship.To)
+		if relationship.From == element {
+			title := "Calls"
+			if relationship.Kind() != "calls" {
+				title = "Uses"
+			}
+			links[title] = append(links[title], siteLinkOf(relationship.To, relationship.Description))
+			outgoing = append(outgoing, siteNeighbor{to, relationship.Description})
+		} else if to == element {
+			title := "Called by"
+			if relationship.Kind() != "calls" {
+				title = "Used by"
+			}
+			links[title] = append(links[title], siteLinkOf(relationship.From, relationship.Description))
+			incoming = append(incoming, siteNeighbor{relationship.From, relationship.Description})
+		}
+	}
+	sections := make([]siteSection, 0)
+	for _, title := range titles {
+		if len(links[title]) > 0 {
+			sections = append(sections, siteSection{title, links[title]})
+		}
+	}
+	return incoming, outgoing, sections
+}
+
+func usersOfTechnology(model *ArchitectureModel, technology *Technology) ([]siteNeighbor, []siteSection) {
+	neighbors := make([]siteNeighbor, 0)
+	links := make([]siteLink, 0)
+	for _, element := range model.Elements() {
+		if !hasPage(element) {
+			continue
+		}
+		for _, candidate := range technologiesOf(element) {
+			if candidate == technology {
+				neighbors = append(neighbors, siteNeighbor{element, ""})
+				links = append(links, siteLinkOf(element, ""))
+			}
+		}
+	}
+	if len(links) == 0 {
+		return neighbors, nil
+	}
+	return neighbors, []siteSection{{"Used by", links}}
+}
+
+func stepsOf(workflow *Workflow) ([]siteNeighbor, []siteSection) {
+	neighbors := make([]siteNeighbor, 0)
+	seen := make(map[interface{}]bool)
+	links := make([]siteLink, 0)
+	for _, step := range workflow.Steps {
+		elements := elementsOfStep(step)
+		if len(elements) == 0 {
+			continue
+		}
+		target := elements[len(elements)-1]
+		link := siteLinkOf(target, step.Description)
+		if len(elements) > 1 {
+			link.Name = nameOf(step.Performer) + " → " + nameOf(target)
+		} else if step.View != "" {
+			link.Name = nameOf(step.Performer) + " → " + step.View
+		}
+		links = append(links, link)
+		for _, element := range elements {
+			container := containerOf(element)
+			if hasPage(container) && !seen[container] {
+				seen[container] = true
+				neighbors = append(neighbors, siteNeighbor{container, ""})
+			}
+		}
+	}
+	if len(links) == 0 {
+		return neighbors, nil
+	}
+	return neighbors, []siteSection{{"Steps", links}}
+}
+
+// siteWorkflowsOf returns links to the workflows that have steps that involve the element or its forms or commands.
+func siteWorkflowsOf(model *ArchitectureModel, element interface{}) []siteLink {
+	result := make([]siteLink, 0)
+	for _, workflow := range model.Workflows {
+		for _, step := range workflow.Steps {
+			if involvesElement(step, element) {
+				result = append(result, siteLinkOf(workflow, workflow.Description))
+				break
+			}
+		}
+	}
+	return result
+}
+
+func involvesElement(step *Step, element interface{}) bool {
+	for _, candidate := range elementsOfStep(step) {
+		if containerOf(candidate) == element {
+			return true
+		}
+	}
+	return false
+}
+
+// siteNeighbor is an element in a focused diagram, with the description of its relationship with the focus.
+type siteNeighbor struct {
+	element     interface{}
+	description string
+}
+
+const (
+	diagramBoxWidth  = 180
+	diagramBoxHeight = 50
+	diagramRowHeight = 70
+	diagramColumnGap = 100
+	diagramMaxName   = 22
+)
+
+// focusedDiagram draws an SVG diagram with the focus in the middle, the elements with relationships to it on the left,
+// and the elements it has relationships with on the right. Boxes link to the pages of their elements.
+func focusedDiagram(theme *Theme, focus interface{}, incoming []siteNeighbor, outgoing []siteNeighbor) template.HTML {
+	rows := len(incoming)
+	if len(outgoing) > rows {
+		rows = len(outgoing)
+	}
+	if rows == 0 {
+		rows = 1
+	}
+	height := rows * diagramRowHeight
+	width := 3*diagramBoxWidth + 2*diagramColumnGap
+	printer := NewPrinter()
+	printer.PrintLn(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%v" height="%v" viewBox="0 0 %v %v" `+
+		`font-family="sans-serif" font-size="13">`, width, height, width, height))
+	printer.Start()
+	printer.PrintLn(`<defs><marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" ` +
+		`markerHeight="8" orient="auto-start-reverse"><path d="M 0 0 L 10 5 L 0 10 z"/></marker></defs>`)
+	center := diagramBoxWidth + diagramColumnGap
+	middle := (height - diagramBoxHeight) / 2
+	for index, neighbor := range incoming {
+		y := diagramRowY(index, len(incoming), height)
+		printDiagramEdge(diagramBoxWidth, y+diagramBoxHeight/2, center, middle+diagramBoxHeight/2,
+			neighbor.description, printer)
+		printDiagramBox(theme, neighbor.element, 0, y, printer)
+	}
+	for index, neighbor := range outgoing {
+		y := diagramRowY(index, len(outgoing), height)
+		printDiagramEdge(center+diagramBoxWidth, middle+diagramBoxHeight/2, width-diagramBoxWidth,
+			y+diagramBoxHeight/2, neighbor.description, printer)
+		printDiagramBox(theme, neighbor.element, width-diagramBoxWidth, y, printer)
+	}
+	printDiagramBox(theme, focus, center, middle, printer)
+	printer.End()
+	printer.PrintLn("</svg>")
+	return template.HTML(printer.String())
+}
+
+// diagramRowY returns the top of the box in a row of a column, so that the column is centered vertically.
+func diagramRowY(row int, rows int, height int) int {
+	return (height-rows*diagramRowHeight)/2 + row*diagramRowHeight + (diagramRowHeight-diagramBoxHeight)/2
+}
+
+func printDiagramEdge(x1 int, y1 int, x2 int, y2 int, description string, printer *Printer) {
+	printer.Print(fmt.Sprintf(`<line x1="%v" y1="%v" x2="%v" y2="%v" stroke="#555" marker-end="url(#arrow)">`,
+		x1, y1, x2, y2))
+	if description != "" {
+		printer.Print("<title>", template.HTMLEscapeString(description), "</title>")
+	}
+	printer.PrintLn("</line>")
+}
+
+func printDiagramBox(theme *Theme, element interface{}, x int, y int, printer *Printer) {
+	style := theme.StyleOf(element)
+	background := style.Background
+	if background == "" {
+		background = "white"
+	}
+	stroke := style.Stroke
+	if stroke == "" {
+		stroke = "black"
+	}
+	color := style.Color
+	if color == "" {
+		color = "black"
+	}
+	name := nameOf(element)
+	if runes := []rune(name); len(runes) > diagramMaxName {
+		name = string(runes[:diagramMaxName-1]) + "…"
+	}
+	link := siteLinkOf(element, "")
+	if link.Href != "" {
+		printer.Print(`<a href="`, template.HTMLEscapeString(link.Href), `">`)
+	}
+	printer.Print(fmt.Sprintf(`<rect x="%v" y="%v" width="%v" height="%v" rx="6" fill="%v" stroke="%v"/>`, x, y,
+		diagramBoxWidth, diagramBoxHeight, template.HTMLEscapeString(background), template.HTMLEscapeString(stroke)))
+	printer.Print(fmt.Sprintf(`<text x="%v" y="%v" text-anchor="middle" dominant-baseline="middle" fill="%v">`,
+		x+diagramBoxWidth/2, y+diagramBoxHeight/2, template.HTMLEscapeString(color)))
+	printer.Print(template.HTMLEscapeString(name), "<title>", template.HTMLEscapeString(nameOf(element)), "</title>",
+		"</text>")
+	if link.Href != "" {
+		printer.Print("</a>")
+	}
+	printer.NewLine()
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteSite(t *testing.T) {
+	model, issues := LintText(queryDefinition)
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+	directory := filepath.Join(t.TempDir(), "docs")
+
+	err := WriteSite(model, directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pages := map[string][]string{
+		"index.html": {`<a href="persona-dev.html">`, `<a href="workflow-subscribe.html">`, "<h2>Technologies</h2>"},
+		"service-api.html": {"<h2>Called by</h2>", `<a href="service-console.html">Console</a>`, "<h2>Uses</h2>",
+			`<a href="technology-java.html">Java</a>`, "<svg", `<span class="state"`},
+		"service-console.html":        {"<h2>Appears in workflows</h2>", `<a href="workflow-subscribe.html">`},
+		"database-subscriptions.html": {"<h2>Used by</h2>", `<a href="persona-cs.html">Cs</a>`},
+		"technology-cobol.html":       {"<h2>Used by</h2>", `<a href="service-reporting.html">Reporting</a>`},
+		"workflow-subscribe.html":     {"<h2>Steps</h2>", "Dev → subscriptions"},
+		"persona-dev.html":            {`<a href="service-console.html">subscriptions</a>`},
+	}
+	for page, fragments := range pages {
+		data, err := os.ReadFile(filepath.Join(directory, page))
+		if err != nil {
+			t.Errorf("Missing page %v: %v", page, err)
+			continue
+		}
+		text := string(data)
+		for _, fragment := range fragments {
+			if !strings.Contains(text, fragment) {
+				t.Errorf("Page %v doesn't contain %v:\n%v", page, fragment, text)
+			}
+		}
+		if strings.Contains(text, `src="http`) || strings.Contains(text, `href="http`) {
+			t.Errorf("Page %v isn't self-contained:\n%v", page, text)
+		}
+	}
+	if _, err = os.Stat(filepath.Join(directory, "site.css")); err != nil {
+		t.Errorf("Missing style sheet: %v", err)
+	}
+}
@@ -35,21 +35,20 @@ func (s State) String() string {
 	}
 }
 
-func (s State) Print(printer *Printer) {
-	if s != Ok {
-		printer.Print(" (", s, ")")
-	}
-
-}
-
 type Evolvable interface {
 	setState(state State)
 }
 
-func setState(owner *yaml.Node, fields map[string]*yaml.Node, e Evolvable) []Issue {
-	const defaultState = "ok"
-	var allowedStates = []string{defaultState, "emerging", "review", "revision", "legacy", "deprecated"}
+const defaultState = "ok"
 
+var allowedStates = []string{defaultState, "emerging", "review", "revision", "legacy", "deprecated"}
+
+// Id returns the value used for the state in the model file.
+func (s State) Id() string {
+	return allowedStates[s]
+}
+
+func setState(owner *yaml.Node, fields map[string]*yaml.Node, e Evolvable) []Issue {
 	value, issue := enumFieldOf(owner, fields, "state", allowedStates, defaultState)
 	if issue != nil {
 		return []Issue{*issue}
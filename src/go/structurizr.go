@@ -0,0 +1,736 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// dslToken is a word or quoted string in a Structurizr DSL file, or the end of a line.
+type dslToken struct {
+	text    string
+	quoted  bool
+	newLine bool
+	line    int
+	column  int
+}
+
+func (t dslToken) is(text string) bool {
+	return !t.quoted && !t.newLine && strings.EqualFold(t.text, text)
+}
+
+// dslStatement is a line of a Structurizr DSL file, with the statements in its block, if any.
+type dslStatement struct {
+	tokens   []dslToken
+	children []*dslStatement
+}
+
+// tokenizeDsl splits a Structurizr DSL file into tokens. Comments start with # or // at the start of a line, so that
+// colors like #ff0000 aren't comments, or are between /* and */.
+func tokenizeDsl(text string) ([]dslToken, error) {
+	result := make([]dslToken, 0)
+	runes := []rune(text)
+	line, column := 1, 1
+	startOfLine := true
+	startsWith := func(prefix string) bool {
+		return len(runes) >= 2 && string(runes[:2]) == prefix
+	}
+	advance := func() {
+		if runes[0] == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+		runes = runes[1:]
+	}
+	for len(runes) > 0 {
+		switch {
+		case runes[0] == '\n':
+			result = append(result, dslToken{newLine: true, line: line, column: column})
+			startOfLine = true
+			advance()
+		case unicode.IsSpace(runes[0]):
+			advance()
+		case startOfLine && (runes[0] == '#' || startsWith("//")):
+			for len(runes) > 0 && runes[0] != '\n' {
+				advance()
+			}
+		case startsWith("/*"):
+			startLine := line
+			for len(runes) > 0 && !startsWith("*/") {
+				advance()
+			}
+			if len(runes) == 0 {
+				return nil, fmt.Errorf("unterminated comment on line %v", startLine)
+			}
+			advance()
+			advance()
+		case runes[0] == '"':
+			token := dslToken{quoted: true, line: line, column: column}
+			advance()
+			var value strings.Builder
+			for len(runes) > 0 && runes[0] != '"' && runes[0] != '\n' {
+				if runes[0] == '\\' && len(runes) > 1 && (runes[1] == '"' || runes[1] == '\\') {
+					advance()
+				}
+				value.WriteRune(runes[0])
+				advance()
+			}
+			if len(runes) == 0 || runes[0] != '"' {
+				return nil, fmt.Errorf("unterminated string on line %v", token.line)
+			}
+			advance()
+			token.text = value.String()
+			result = append(result, token)
+			startOfLine = false
+		default:
+			token := dslToken{line: line, column: column}
+			var value strings.Builder
+			for len(runes) > 0 && !unicode.IsSpace(runes[0]) {
+				value.WriteRune(runes[0])
+				advance()
+			}
+			token.text = value.String()
+			result = append(result, token)
+			startOfLine = false
+		}
+	}
+	return result, nil
+}
+
+// parseDsl parses the statements of a Structurizr DSL file.
+func parseDsl(text string) ([]*dslStatement, error) {
+	tokens, err := tokenizeDsl(text)
+	if err != nil {
+		return nil, err
+	}
+	index := 0
+	return parseDslStatements(tokens, &index, nil)
+}
+
+func parseDslStatements(tokens []dslToken, index *int, opening *dslToken) ([]*dslStatement, error) {
+	result := make([]*dslStatement, 0)
+	current := &dslStatement{}
+	for *index < len(tokens) {
+		token := tokens[*index]
+		*index++
+		switch {
+		case token.newLine:
+			if len(current.tokens) > 0 {
+				result = append(result, current)
+				current = &dslStatement{}
+			}
+		case token.is("{"):
+			children, err := parseDslStatements(tokens, index, &token)
+			if err != nil {
+				return nil, err
+			}
+			current.children = children
+			result = append(result, current)
+			current = &dslStatement{}
+		case token.is("}"):
+			if opening == nil {
+				return nil, fmt.Errorf("unexpected '}' on line %v", token.line)
+			}
+			if len(current.tokens) > 0 {
+				result = append(result, current)
+			}
+			return result, nil
+		default:
+			current.tokens = append(current.tokens, token)
+		}
+	}
+	if opening != nil {
+		return nil, fmt.Errorf("missing '}' for '{' on line %v", opening.line)
+	}
+	if len(current.tokens) > 0 {
+		result = append(result, current)
+	}
+	return result, nil
+}
+
+// parts splits a statement into its identifier, if it assigns one, its keyword, and its arguments.
+func (s *dslStatement) parts() (string, string, []dslToken) {
+	tokens := s.tokens
+	identifier := ""
+	if len(tokens) >= 3 && tokens[1].is("=") {
+		identifier = tokens[0].text
+		tokens = tokens[2:]
+	}
+	if len(tokens) == 0 {
+		return identifier, "", tokens
+	}
+	return identifier, strings.ToLower(tokens[0].text), tokens[1:]
+}
+
+// relationship returns the source, destination, and arguments of a relationship statement. An empty source means the
+// element whose block contains the statement.
+func (s *dslStatement) relationship() (string, string, []dslToken, bool) {
+	tokens := s.tokens
+	if len(tokens) >= 3 && tokens[1].is("=") {
+		tokens = tokens[2:]
+	}
+	for index, token := range tokens {
+		if token.is("->") && index <= 1 && index+1 < len(tokens) {
+			source := ""
+			if index == 1 && !tokens[0].is("this") {
+				source = tokens[0].text
+			}
+			return source, tokens[index+1].text, tokens[index+2:], true
+		}
+	}
+	return "", "", nil, false
+}
+
+func argument(arguments []dslToken, index int) string {
+	if index < len(arguments) {
+		return arguments[index].text
+	}
+	return ""
+}
+
+// splitDsl splits a comma-separated list of tags or technologies.
+func splitDsl(value string) []string {
+	result := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// structurizrRef is an element of the imported model that an identifier in the workspace refers to.
+type structurizrRef struct {
+	kind string
+	id   string
+}
+
+// structurizrRelationship is a relationship whose elements are looked up once all elements are imported.
+type structurizrRelationship struct {
+	source    string
+	statement *dslStatement
+}
+
+type structurizrImporter struct {
+	result        *ImportedModel
+	elements      map[string]structurizrRef
+	relationships []structurizrRelationship
+	environments  []*dslStatement
+	// persons are the statements of the imported personas by ID.
+	persons map[string]*dslStatement
+}
+
+// ImportStructurizr creates a draft model from a Structurizr DSL workspace.
+func ImportStructurizr(fileName string) (*ImportedModel, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return importStructurizr(string(data))
+}
+
+// importStructurizr turns a workspace into a model, like the one that the C4 exporter exports. Persons become personas
+// and software systems external systems, except the system of interest: the one tagged System of Interest, or else
+// the first with containers. Its containers become services, databases, or queues, based on their tags and
+// technologies, and groups of containers become teams. Relationships become uses and calls, and deployment
+// environments become environments. Views and styles are ignored, and whatever can't be imported gets a warning.
+func importStructurizr(text string) (*ImportedModel, error) {
+	statements, err := parseDsl(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Structurizr DSL: %v", err)
+	}
+	if len(statements) != 1 || len(statements[0].tokens) == 0 || !statements[0].tokens[0].is("workspace") ||
+		statements[0].children == nil {
+		return nil, fmt.Errorf("invalid Structurizr DSL: expected a workspace")
+	}
+	s := structurizrImporter{result: NewImportedModel(), elements: make(map[string]structurizrRef),
+		persons: make(map[string]*dslStatement)}
+	for _, statement := range statements[0].children {
+		switch _, keyword, _ := statement.parts(); keyword {
+		case "model":
+			s.importModel(statement.children, "")
+		case "views", "configuration", "name", "description", "properties", "!identifiers":
+		default:
+			s.warnUnknown(statement)
+		}
+	}
+	for _, relationship := range s.relationships {
+		s.importRelationship(relationship)
+	}
+	s.removePersonasWithoutUses()
+	for _, environment := range s.environments {
+		s.importEnvironment(environment)
+	}
+	return s.result, nil
+}
+
+// warnUnknown warns about a statement that can't be imported, by its keyword.
+func (s *structurizrImporter) warnUnknown(statement *dslStatement) {
+	tokens := statement.tokens
+	if len(tokens) >= 3 && tokens[1].is("=") {
+		tokens = tokens[2:]
+	}
+	s.warn(statement, fmt.Sprintf("Can't import '%v'", tokens[0].text))
+}
+
+func (s *structurizrImporter) warn(statement *dslStatement, message string) {
+	token := statement.tokens[0]
+	s.result.Warnings = append(s.result.Warnings, Issue{Level: Warning, Message: message, Line: token.line,
+		Column: token.column})
+}
+
+// importModel imports the statements of the model, or of a group in the model, whose elements the given team owns.
+func (s *structurizrImporter) importModel(statements []*dslStatement, owner string) {
+	for _, statement := range statements {
+		if _, _, _, ok := statement.relationship(); ok {
+			s.relationships = append(s.relationships, structurizrRelationship{"", statement})
+			continue
+		}
+		identifier, keyword, arguments := statement.parts()
+		switch keyword {
+		case "person":
+			s.importPerson(identifier, arguments, statement)
+		case "softwaresystem":
+			s.importSoftwareSystem(identifier, arguments, statement, owner)
+		case "group":
+			s.importModel(statement.children, s.teamOf(argument(arguments, 0)))
+		case "enterprise":
+			s.importModel(statement.children, owner)
+		case "deploymentenvironment":
+			s.environments = append(s.environments, statement)
+		case "!identifiers", "!impliedrelationships":
+		default:
+			s.warnUnknown(statement)
+		}
+	}
+}
+
+func (s *structurizrImporter) teamOf(name string) string {
+	id := idFromName(name)
+	if _, found := s.result.Teams[id]; !found {
+		s.result.Teams[id] = &ImportedElement{Name: s.nameFor(id, name)}
+	}
+	return id
+}
+
+// nameFor returns the name of an element, unless it's the name that the model derives from the ID anyway.
+func (s *structurizrImporter) nameFor(id string, name string) string {
+	if name == friendlyNameFrom(id) {
+		return ""
+	}
+	return name
+}
+
+// idFor returns the ID of a new element, based on the identifier in the workspace, without the given suffix, or
+// else on the name.
+func (s *structurizrImporter) idFor(identifier string, name string, suffix string) string {
+	if identifier == "" {
+		return idFromName(name)
+	}
+	if trimmed := strings.TrimSuffix(identifier, suffix); trimmed != "" {
+		return trimmed
+	}
+	return identifier
+}
+
+// register makes an element findable by its identifier in the workspace. Elements without an identifier get one that
+// can't occur in the workspace, so that relationships in their blocks can refer to them. It returns the identifier.
+func (s *structurizrImporter) register(identifier string, kind string, id string) string {
+	if identifier == "" {
+		identifier = fmt.Sprintf("<%v %v>", kind, id)
+	}
+	s.elements[identifier] = structurizrRef{kind, id}
+	return identifier
+}
+
+// importElement reads the name, description, technologies, tags, and properties of an element from the arguments of
+// its statement, at the given indexes, and from its block. It returns the tags and the statements it doesn't know.
+func (s *structurizrImporter) importElement(element *ImportedElement, id string, arguments []dslToken,
+	descriptionIndex int, technologyIndex int, tagsIndex int, statement *dslStatement) ([]string, []*dslStatement) {
+	element.Name = s.nameFor(id, argument(arguments, 0))
+	element.Description = argument(arguments, descriptionIndex)
+	technologies := ""
+	if technologyIndex > 0 {
+		technologies = argument(arguments, technologyIndex)
+	}
+	tags := splitDsl(argument(arguments, tagsIndex))
+	unknown := make([]*dslStatement, 0)
+	for _, child := range statement.children {
+		_, keyword, childArguments := child.parts()
+		switch keyword {
+		case "description":
+			element.Description = argument(childArguments, 0)
+		case "technology":
+			technologies = argument(childArguments, 0)
+		case "tags":
+			for _, token := range childArguments {
+				tags = append(tags, splitDsl(token.text)...)
+			}
+		case "properties":
+			for _, property := range child.children {
+				if len(property.tokens) == 2 {
+					if element.Properties == nil {
+						element.Properties = make(map[string]string)
+					}
+					element.Properties[property.tokens[0].text] = property.tokens[1].text
+				}
+			}
+		default:
+			unknown = append(unknown, child)
+		}
+	}
+	for _, name := range splitDsl(technologies) {
+		s.result.addTechnology(&element.Technologies, technologyIdOf(name), name)
+	}
+	return tags, unknown
+}
+
+// technologyIdOf returns the ID of a technology with the given name, preferring the IDs that other importers use.
+func technologyIdOf(name string) string {
+	for _, candidate := range infrastructureImages {
+		if strings.EqualFold(candidate.technologyName, name) {
+			return candidate.technologyId
+		}
+	}
+	return idFromName(name)
+}
+
+// customTags returns the tags, except for those that Structurizr or the C4 exporter adds.
+func customTags(tags []string, builtIn ...string) []string {
+	result := make([]string, 0)
+	for _, tag := range tags {
+		if !hasDifferentValueThan(tag, append(builtIn, "Element")) {
+			continue
+		}
+		result = append(result, tag)
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// importChildren handles relationships in the block of an element and warns about other statements it doesn't know.
+func (s *structurizrImporter) importChildren(identifier string, statements []*dslStatement) {
+	for _, statement := range statements {
+		if source, _, _, ok := statement.relationship(); ok {
+			if source == "" {
+				source = identifier
+			}
+			s.relationships = append(s.relationships, structurizrRelationship{source, statement})
+			continue
+		}
+		s.warnUnknown(statement)
+	}
+}
+
+func (s *structurizrImporter) importPerson(identifier string, arguments []dslToken, statement *dslStatement) {
+	id := s.idFor(identifier, argument(arguments, 0), "")
+	persona := &ImportedPersona{}
+	tags, unknown := s.importElement(&persona.ImportedElement, id, arguments, 1, 0, 2, statement)
+	persona.Tags = customTags(tags, "Person")
+	persona.Technologies = nil
+	s.result.Personas[id] = persona
+	s.persons[id] = statement
+	identifier = s.register(identifier, "persona", id)
+	s.importChildren(identifier, unknown)
+}
+
+func (s *structurizrImporter) importSoftwareSystem(identifier string, arguments []dslToken, statement *dslStatement,
+	owner string) {
+	name := argument(arguments, 0)
+	id := s.idFor(identifier, name, "")
+	externalSystem := &ImportedExternalSystem{}
+	tags, unknown := s.importElement(&externalSystem.ImportedElement, id, arguments, 1, 0, 2, statement)
+	hasContainers := false
+	for _, child := range unknown {
+		if _, keyword, _ := child.parts(); keyword == "container" || keyword == "group" {
+			hasContainers = true
+		}
+	}
+	if s.result.SystemName == "" && (hasContainers || !hasDifferentValueThan("System of Interest", tags)) {
+		s.result.SystemName = name
+		identifier = s.register(identifier, "system", id)
+		s.importContainers(identifier, unknown, owner)
+		return
+	}
+	if hasContainers {
+		s.warn(statement, fmt.Sprintf("Can't import the containers of software system '%v': only those of the "+
+			"system of interest become services, databases, and queues", name))
+	}
+	for index, tag := range tags {
+		if tag == "External System" && index+1 < len(tags) {
+			externalSystem.Type = tags[index+1]
+			tags = append(tags[:index+1], tags[index+2:]...)
+			break
+		}
+	}
+	externalSystem.Tags = customTags(tags, "Software System", "External System", "External")
+	externalSystem.Owner = owner
+	s.result.ExternalSystems[id] = externalSystem
+	identifier = s.register(identifier, "externalSystem", id)
+	for _, child := range unknown {
+		if _, keyword, _ := child.parts(); keyword != "container" && keyword != "group" {
+			s.importChildren(identifier, []*dslStatement{child})
+		}
+	}
+}
+
+// importContainers imports the containers of the system of interest, and the groups of containers.
+func (s *structurizrImporter) importContainers(systemIdentifier string, statements []*dslStatement, owner string) {
+	for _, statement := range statements {
+		identifier, keyword, arguments := statement.parts()
+		switch keyword {
+		case "container":
+			s.importContainer(identifier, arguments, statement, owner)
+		case "group":
+			s.importContainers(systemIdentifier, statement.children, s.teamOf(argument(arguments, 0)))
+		default:
+			s.importChildren(systemIdentifier, []*dslStatement{statement})
+		}
+	}
+}
+
+func (s *structurizrImporter) importContainer(identifier string, arguments []dslToken, statement *dslStatement,
+	owner string) {
+	var element ImportedElement
+	tags, unknown := s.importElement(&element, "", arguments, 1, 2, 3, statement)
+	kind := ""
+	for _, tag := range tags {
+		switch tag {
+		case "Service":
+			kind = "service"
+		case "Database":
+			kind = "database"
+		case "Queue":
+			kind = "queue"
+		}
+	}
+	if kind == "" {
+		kind = "service"
+		for _, name := range element.Technologies {
+			for _, candidate := range infrastructureImages {
+				if strings.Contains(strings.ToLower(name), candidate.keyword) {
+					kind = candidate.kind
+				}
+			}
+		}
+	}
+	suffixes := map[string]string{"service": "", "database": "_db", "queue": "_q"}
+	id := s.idFor(identifier, argument(arguments, 0), suffixes[kind])
+	element.Name = s.nameFor(id, argument(arguments, 0))
+	element.Owner = owner
+	custom := make([]string, 0)
+	for _, tag := range customTags(tags, "Container", "Service", "Database", "Queue") {
+		if state, found := stateOfTag(tag); found {
+			if state != defaultState {
+				element.State = state
+			}
+		} else {
+			custom = append(custom, tag)
+		}
+	}
+	if len(custom) > 0 {
+		element.Tags = custom
+	}
+	if kind == "service" {
+		s.result.addService(id).ImportedElement = element
+	} else {
+		s.result.addDataStore(kind, id, "", "").ImportedElement = element
+	}
+	identifier = s.register(identifier, kind, id)
+	for _, child := range unknown {
+		if _, keyword, _ := child.parts(); keyword == "component" {
+			s.warn(child, "Can't import components")
+		} else {
+			s.importChildren(identifier, []*dslStatement{child})
+		}
+	}
+}
+
+// stateOfTag returns the state for a tag that the C4 exporter uses for states, like legacy.
+func stateOfTag(tag string) (string, bool) {
+	for index, id := range allowedStates {
+		if strings.EqualFold(tag, State(index).String()) || tag == id {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// find returns the element that an identifier refers to. With hierarchical identifiers, like shop.api, only the last
+// part is used.
+func (s *structurizrImporter) find(identifier string) (structurizrRef, bool) {
+	if ref, found := s.elements[identifier]; found {
+		return ref, true
+	}
+	if index := strings.LastIndex(identifier, "."); index >= 0 {
+		ref, found := s.elements[identifier[index+1:]]
+		return ref, found
+	}
+	return structurizrRef{}, false
+}
+
+func (s *structurizrImporter) importRelationship(relationship structurizrRelationship) {
+	statement := relationship.statement
+	source, destination, arguments, _ := statement.relationship()
+	if source == "" {
+		source = relationship.source
+	}
+	from, found := s.find(source)
+	if !found {
+		s.warn(statement, fmt.Sprintf("Unknown element '%v'", source))
+		return
+	}
+	to, found := s.find(destination)
+	if !found {
+		s.warn(statement, fmt.Sprintf("Unknown element '%v'", destination))
+		return
+	}
+	var element ImportedElement
+	arguments = append([]dslToken{{}}, arguments...)
+	tags, unknown := s.importElement(&element, "", arguments, 1, 2, 3, statement)
+	for _, child := range unknown {
+		s.warnUnknown(child)
+	}
+	use := ImportedUse{Kind: to.kind, Id: to.id, Description: element.Description, Technologies: element.Technologies,
+		Tags: customTags(tags, "Relationship", "Using")}
+	modelProperties := make(map[string]string)
+	for key, value := range element.Properties {
+		if strings.HasPrefix(key, modelPropertyPrefix) {
+			modelProperties[strings.TrimPrefix(key, modelPropertyPrefix)] = value
+		} else {
+			if use.Properties == nil {
+				use.Properties = make(map[string]string)
+			}
+			use.Properties[key] = value
+		}
+	}
+	if dataFlow, found := modelProperties["dataFlow"]; found {
+		if hasDifferentValueThan(dataFlow, allowedDataFlows) {
+			s.warn(statement, fmt.Sprintf("Unknown data flow '%v'", dataFlow))
+		} else if dataFlow != defaultDataFlow {
+			use.DataFlow = dataFlow
+		}
+	}
+	if from.kind == "persona" {
+		s.importPersonaUse(&use, to, modelProperties)
+	}
+	if from.kind != "service" && from.kind != "externalSystem" || to.kind != "service" && to.kind != "externalSystem" {
+		if len(use.Technologies)+len(use.Tags)+len(use.Properties) > 0 {
+			s.warn(statement, fmt.Sprintf("Can't import the technologies, tags, and properties of the relationship "+
+				"from '%v' to '%v': only calls have them", source, destination))
+		}
+		use.Technologies, use.Tags, use.Properties = nil, nil, nil
+	}
+	if !s.result.addUse(from.kind, from.id, use) {
+		s.warn(statement, fmt.Sprintf("Can't import relationship from %v '%v' to %v '%v'", from.kind, from.id,
+			to.kind, to.id))
+	}
+}
+
+// removePersonasWithoutUses removes the personas that don't use anything the model supports, since a model requires
+// personas to use something.
+func (s *structurizrImporter) removePersonasWithoutUses() {
+	for _, id := range sortedKeys(s.result.Personas) {
+		if len(s.result.Personas[id].Uses) == 0 {
+			delete(s.result.Personas, id)
+			s.warn(s.persons[id], fmt.Sprintf("Can't import person '%v': it doesn't use a form, a view, or an external "+
+				"system", id))
+		}
+	}
+}
+
+// importPersonaUse turns the use of a container by a persona into that of a form of a service or a view on a database,
+// which the C4 exporter records in the properties of the relationship. Without that property, the form or view has the
+// ID of the container.
+func (s *structurizrImporter) importPersonaUse(use *ImportedUse, to structurizrRef,
+	modelProperties map[string]string) {
+	switch to.kind {
+	case "service":
+		use.Kind, use.Id = "form", to.id
+		if id, found := modelProperties["form"]; found {
+			use.Id = id
+		}
+		service := s.result.Services[to.id]
+		if !contains(service.Forms, use.Id) {
+			service.Forms = append(service.Forms, use.Id)
+		}
+	case "database":
+		use.Kind, use.Id = "view", to.id
+		if id, found := modelProperties["view"]; found {
+			use.Id = id
+		}
+		database := s.result.Databases[to.id]
+		if !contains(database.Views, use.Id) {
+			database.Views = append(database.Views, use.Id)
+		}
+	}
+}
+
+func (s *structurizrImporter) importEnvironment(statement *dslStatement) {
+	identifier, _, arguments := statement.parts()
+	name := argument(arguments, 0)
+	id := s.idFor(identifier, name, "_env")
+	environment := &ImportedEnvironment{Name: s.nameFor(id, name),
+		DeploymentNodes: make(map[string]*ImportedDeploymentNode)}
+	s.result.Environments[id] = environment
+	for _, child := range statement.children {
+		s.importDeploymentNode(child, id, environment.DeploymentNodes, nil)
+	}
+}
+
+// importDeploymentNode imports a deployment node into the deployment nodes of its parent. The instances of a deployment
+// node are the replicas of its container instances. A deployment node that the C4 exporter created for the replicas of a
+// single container instance becomes part of its parent.
+func (s *structurizrImporter) importDeploymentNode(statement *dslStatement, parentIdentifier string,
+	deploymentNodes map[string]*ImportedDeploymentNode, parent *ImportedDeploymentNode) {
+	identifier, keyword, arguments := statement.parts()
+	if keyword != "deploymentnode" {
+		if _, _, _, ok := statement.relationship(); !ok {
+			s.warnUnknown(statement)
+		}
+		return
+	}
+	name := argument(arguments, 0)
+	id := idFromName(name)
+	if identifier != "" {
+		id = strings.TrimPrefix(identifier, parentIdentifier+"_")
+	} else {
+		identifier = parentIdentifier + "_" + id
+	}
+	deploymentNode := &ImportedDeploymentNode{DeploymentNodes: make(map[string]*ImportedDeploymentNode)}
+	tags, unknown := s.importElement(&deploymentNode.ImportedElement, id, arguments, 1, 2, 3, statement)
+	deploymentNode.Tags = customTags(tags, "Deployment Node")
+	replicas := 1
+	for _, child := range unknown {
+		_, childKeyword, childArguments := child.parts()
+		switch childKeyword {
+		case "instances":
+			if instances, err := strconv.Atoi(argument(childArguments, 0)); err == nil && instances > 1 {
+				replicas = instances
+			}
+		case "containerinstance":
+			ref, found := s.find(argument(childArguments, 0))
+			if !found || (ref.kind != "service" && ref.kind != "database" && ref.kind != "queue") {
+				s.warn(child, fmt.Sprintf("Unknown container '%v'", argument(childArguments, 0)))
+				continue
+			}
+			deploymentNode.Instances = append(deploymentNode.Instances, ImportedInstance{ref.kind, ref.id, 1})
+		default:
+			s.importDeploymentNode(child, identifier, deploymentNode.DeploymentNodes, deploymentNode)
+		}
+	}
+	for index := range deploymentNode.Instances {
+		deploymentNode.Instances[index].Replicas *= replicas
+	}
+	if parent != nil && len(deploymentNode.Tags) == 1 && deploymentNode.Tags[0] == replicasTag {
+		parent.Instances = append(parent.Instances, deploymentNode.Instances...)
+		return
+	}
+	deploymentNodes[id] = deploymentNode
+}
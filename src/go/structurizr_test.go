@@ -0,0 +1,313 @@
+package main
+
+import (
+	"gopkg.in/yaml.v3"
+	"strings"
+	"testing"
+)
+
+const roundTripDefinition = `system:
+  name: Shop
+
+personas:
+  customer:
+    uses:
+      - externalSystem: bank
+        description: Pays
+      - form: order
+        dataFlow: send
+      - view: orderHistory
+        description: Tracks orders
+        dataFlow: receive
+
+externalSystems:
+  bank:
+    name: The Bank
+    type: central
+    calls:
+      - service: api
+        description: Confirms payments
+
+teams:
+  payments:
+    name: Payments
+
+services:
+  api:
+    name: API
+    description: Handles orders
+    state: legacy
+    owner: payments
+    technologies:
+      - go
+    forms:
+      - order
+    calls:
+      - service: worker
+        dataFlow: send
+        technologies:
+          - grpc
+        tags:
+          - internal
+        properties:
+          timeout: 5s
+      - externalSystem: bank
+    dataStores:
+      - database: orders
+        description: Stores orders
+      - queue: events
+        dataFlow: send
+    tags:
+      - pci
+    properties:
+      repository: https://git.example.com/api
+  worker: {}
+
+databases:
+  orders:
+    technologies:
+      - postgresql
+    views:
+      - orderHistory
+
+queues:
+  events:
+    owner: payments
+    technologies:
+      - kafka
+
+technologies:
+  go:
+    name: Go
+    quadrant: platforms
+  grpc:
+    name: gRPC
+    quadrant: platforms
+  kafka:
+    name: Kafka
+    quadrant: platforms
+  kubernetes:
+    name: Kubernetes
+    quadrant: platforms
+  postgresql:
+    name: PostgreSQL
+    quadrant: platforms
+
+environments:
+  production:
+    deploymentNodes:
+      cloud:
+        name: Cloud
+        deploymentNodes:
+          cluster:
+            technologies: kubernetes
+            instances:
+              - service: api
+                replicas: 3
+              - service: worker
+              - queue: events
+        instances:
+          - database: orders
+
+workflows:
+  order:
+    steps:
+      - performer: customer
+        form: order
+      - performer: customer
+        view: orderHistory
+`
+
+func TestStructurizrRoundTrip(t *testing.T) {
+	model, issues := LintText(roundTripDefinition)
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+	printer := NewPrinter()
+	err := NewC4Exporter().export(*model, printer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	imported, err := importStructurizr(printer.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(imported.Warnings) > 0 {
+		t.Errorf("Unexpected warnings: %+v", imported.Warnings)
+	}
+	text, err := yaml.Marshal(imported.Node())
+	if err != nil {
+		t.Fatal(err)
+	}
+	roundTripped, issues := LintText(string(text))
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v\n%v", issues, string(text))
+	}
+	// C4 has no place for workflows.
+	model.Workflows = nil
+	expected := NewPrinter()
+	actual := NewPrinter()
+	_ = NewJsonExporter().export(*model, expected)
+	_ = NewJsonExporter().export(*roundTripped, actual)
+	if actual.String() != expected.String() {
+		t.Errorf("Expected:\n%v\nbut got:\n%v\nfrom:\n%v", expected.String(), actual.String(), string(text))
+	}
+}
+
+const structurizrDefinition = `workspace "Shop" {
+    !identifiers hierarchical
+    !docs docs
+
+    model {
+        # Not a color, but a comment
+        user = person "Online Customer" "Buys things" "Customer"
+        admin = person "Admin"
+        guest = person "Guest"
+        shop = softwareSystem "Shop" {
+            api = container "Order API" "Handles orders" "Java, Spring Boot" {
+                url https://example.com/api
+                orders = component "Orders"
+            }
+            db = container "Orders" {
+                technology "MySQL"
+            }
+            /* A queue, because of the tag */
+            events = container "Events" "" "" "Queue,Legacy"
+            api -> db "Reads from and writes to" "JDBC"
+            -> events
+        }
+        softwareSystem "Payment Provider" "" "Existing System" {
+            -> shop.api "Confirms payments" "HTTPS"
+        }
+        user -> shop "Orders things"
+        user -> shop.db "Tracks orders"
+        admin -> shop.api "Manages orders"
+    }
+
+    views {
+        styles {
+            element "Person" {
+                background #08427b
+            }
+        }
+    }
+}
+`
+
+func TestImportStructurizr(t *testing.T) {
+	imported, err := importStructurizr(structurizrDefinition)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if imported.SystemName != "Shop" || len(imported.Personas) != 2 || len(imported.ExternalSystems) != 1 ||
+		len(imported.Services) != 1 || len(imported.Databases) != 1 || len(imported.Queues) != 1 {
+		t.Fatalf("Invalid elements: %+v", imported)
+	}
+	if user := imported.Personas["user"]; user.Name != "Online Customer" || user.Description != "Buys things" ||
+		len(user.Tags) != 1 || user.Tags[0] != "Customer" || len(user.Uses) != 1 || user.Uses[0].Kind != "view" ||
+		user.Uses[0].Id != "db" {
+		t.Errorf("Invalid persona: %+v", user)
+	}
+	if admin := imported.Personas["admin"]; len(admin.Uses) != 1 || admin.Uses[0].Kind != "form" ||
+		admin.Uses[0].Id != "api" {
+		t.Errorf("Invalid persona: %+v", admin)
+	}
+	api := imported.Services["api"]
+	if api.Name != "Order API" || len(api.Technologies) != 2 || api.Technologies[1] != "springBoot" ||
+		len(api.DataStores) != 1 || api.DataStores[0].Id != "db" || !equalStrings(api.Forms, []string{"api"}) {
+		t.Errorf("Invalid service: %+v", api)
+	}
+	if db := imported.Databases["db"]; len(db.Technologies) != 1 || db.Technologies[0] != "mysql" ||
+		!equalStrings(db.Views, []string{"db"}) {
+		t.Errorf("Invalid database: %+v", db)
+	}
+	if events := imported.Queues["events"]; events.State != "legacy" || events.Tags != nil {
+		t.Errorf("Invalid queue: %+v", events)
+	}
+	provider := imported.ExternalSystems["paymentProvider"]
+	if provider == nil || provider.Type != "" || len(provider.Tags) != 1 || len(provider.Calls) != 1 ||
+		provider.Calls[0].Technologies[0] != "https" {
+		t.Errorf("Invalid external system: %+v", provider)
+	}
+	expected := []string{
+		"Can't import '!docs'",
+		"Can't import 'url'",
+		"Can't import components",
+		"Can't import the technologies, tags, and properties of the relationship from 'api' to 'db': only calls have them",
+		"Can't import relationship from system 'shop' to queue 'events'",
+		"Can't import relationship from persona 'user' to system 'shop'",
+		"Can't import person 'guest': it doesn't use a form, a view, or an external system",
+	}
+	if len(imported.Warnings) != len(expected) {
+		t.Fatalf("Invalid warnings: %+v", imported.Warnings)
+	}
+	for index, warning := range imported.Warnings {
+		if warning.Message != expected[index] {
+			t.Errorf("Expected warning '%v' but got '%v'", expected[index], warning.Message)
+		}
+	}
+}
+
+func TestImportStructurizrDeploymentNodeInstances(t *testing.T) {
+	imported, err := importStructurizr(`workspace {
+    model {
+        shop = softwareSystem "Shop" {
+            api = container "API"
+        }
+        live = deploymentEnvironment "Live" {
+            web = deploymentNode "Web" {
+                instances 2
+                containerInstance api
+            }
+        }
+    }
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	web := imported.Environments["live"].DeploymentNodes["web"]
+	if web == nil || len(web.Instances) != 1 || web.Instances[0] != (ImportedInstance{"service", "api", 2}) {
+		t.Errorf("Invalid deployment node: %+v", web)
+	}
+	if len(imported.Warnings) > 0 {
+		t.Errorf("Unexpected warnings: %+v", imported.Warnings)
+	}
+}
+
+func TestImportInvalidStructurizr(t *testing.T) {
+	for _, text := range []string{"model {", "workspace {\n}\n}", "workspace {\n  model {\n    a = person \"A\n  }\n}"} {
+		_, err := importStructurizr(text)
+		if err == nil {
+			t.Errorf("Expected error for %v", text)
+		}
+	}
+}
+
+func TestC4TeamView(t *testing.T) {
+	model, issues := LintText(roundTripDefinition)
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+	printer := NewPrinter()
+
+	err := NewC4Exporter().export(*model, printer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `        container system "teams" "Containers by team" {
+            title "Teams"
+            include api events_q
+            exclude "*->*"
+            autolayout
+        }
+`
+	if !strings.Contains(printer.String(), expected) {
+		t.Errorf("Missing team view:\n%v\nin:\n%v", expected, printer.String())
+	}
+}
@@ -2,9 +2,14 @@ package main
 
 import (
 	"gopkg.in/yaml.v3"
+	"path/filepath"
+	"strings"
 )
 
 type System struct {
+	// Id identifies the system in a landscape of multiple models. It defaults to the name of the model file without
+	// its extension.
+	Id   string
 	Name string
 }
 
@@ -23,10 +28,21 @@ func (s *System) setName(name string) {
 type SystemReader struct {
 }
 
+var systemFields = []string{"id", "name"}
+
 func (_ SystemReader) read(node *yaml.Node, fileName string, model *ArchitectureModel) []Issue {
 	fields, issue := toMap(node)
 	if issue != nil {
 		return []Issue{*issue}
 	}
-	return setName(fields, &model.System, fileName)
+	issues := setName(fields, &model.System, fileName)
+	id, found, issue := stringFieldOf(fields, "id")
+	if issue != nil {
+		issues = append(issues, *issue)
+	} else if found {
+		model.System.Id = id
+	} else if fileName != "" {
+		model.System.Id = strings.TrimSuffix(filepath.Base(fileName), filepath.Ext(fileName))
+	}
+	return append(issues, checkFields(fields, systemFields)...)
 }
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"sort"
+	"strings"
+)
+
+// Team owns elements of the model.
+type Team struct {
+	node        *yaml.Node
+	Id          string
+	Name        string
+	Description string
+	Contact     string
+	Links       []*Link
+	Extensions
+}
+
+type Link struct {
+	Name string
+	Url  string
+}
+
+func (t *Team) setNode(node *yaml.Node) {
+	t.node = node
+}
+
+func (t *Team) setId(id string) {
+	t.Id = id
+}
+
+func (t *Team) setName(name string) {
+	t.Name = name
+}
+
+func (t *Team) getDescription() string {
+	return t.Description
+}
+
+func (t *Team) setDescription(description string) {
+	t.Description = description
+}
+
+var teamFields = []string{"name", "description", "contact", "links"}
+
+func (t *Team) read(id string, node *yaml.Node) []Issue {
+	var fields map[string]*yaml.Node
+	fields, issues := namedObject(node, id, t)
+	issues = append(issues, setDescription(fields, t)...)
+	contact, found, issue := stringFieldOf(fields, "contact")
+	if issue != nil {
+		issues = append(issues, *issue)
+	} else if found {
+		t.Contact = contact
+	}
+	issues = append(issues, t.readLinks(fields)...)
+	issues = append(issues, checkExtensibleFields(fields, teamFields)...)
+	return issues
+}
+
+func (t *Team) readLinks(fields map[string]*yaml.Node) []Issue {
+	linksByName, _, issue := mapFieldOf(fields, "links")
+	if issue != nil {
+		return []Issue{*issue}
+	}
+	issues := make([]Issue, 0)
+	links := make([]*Link, 0)
+	for name, urlNode := range linksByName {
+		url, issue := toString(urlNode, name)
+		if issue != nil {
+			issues = append(issues, *issue)
+		} else {
+			links = append(links, &Link{name, url})
+		}
+	}
+	sort.Slice(links, func(i, j int) bool {
+		return links[i].Name < links[j].Name
+	})
+	t.Links = links
+	return issues
+}
+
+type TeamReader struct {
+}
+
+func (r TeamReader) read(node *yaml.Node, _ string, model *ArchitectureModel) []Issue {
+	if node == nil {
+		return []Issue{}
+	}
+	teamsById, issue := toMap(node)
+	if issue != nil {
+		return []Issue{*issue}
+	}
+	issues := make([]Issue, 0)
+	teams := make([]*Team, 0)
+	for id, teamNode := range teamsById {
+		team := Team{}
+		teams = append(teams, &team)
+		issues = append(issues, team.read(id, teamNode)...)
+	}
+	sort.Slice(teams, func(i, j int) bool {
+		return teams[i].Name < teams[j].Name
+	})
+	model.Teams = teams
+	return issues
+}
+
+func (model ArchitectureModel) findTeamById(id string) (*Team, bool) {
+	for _, candidate := range model.Teams {
+		if candidate.Id == id {
+			return candidate, true
+		}
+	}
+	return nil, false
+}
+
+// Ownable is an element that a team can own.
+type Ownable interface {
+	getNode() *yaml.Node
+	getOwnerId() string
+	setOwnerId(id string)
+	setOwner(team *Team)
+}
+
+func setOwner(fields map[string]*yaml.Node, o Ownable) []Issue {
+	owner, found, issue := stringFieldOf(fields, "owner")
+	if issue != nil {
+		return []Issue{*issue}
+	}
+	if found {
+		o.setOwnerId(owner)
+	}
+	return []Issue{}
+}
+
+// ownablesOf returns the elements of the model that a team can own.
+func ownablesOf(model *ArchitectureModel) []Ownable {
+	result := make([]Ownable, 0)
+	for _, externalSystem := range model.ExternalSystems {
+		result = append(result, externalSystem)
+	}
+	for _, service := range model.Services {
+		result = append(result, service)
+		for _, form := range service.Forms {
+			result = append(result, form)
+		}
+	}
+	for _, database := range model.Databases {
+		result = append(result, database)
+	}
+	for _, queue := range model.Queues {
+		result = append(result, queue)
+	}
+	return result
+}
+
+type OwnerConnector struct {
+}
+
+func (c OwnerConnector) connect(model *ArchitectureModel) []Issue {
+	issues := make([]Issue, 0)
+	for _, ownable := range ownablesOf(model) {
+		if ownable.getOwnerId() == "" {
+			continue
+		}
+		team, found := model.findTeamById(ownable.getOwnerId())
+		if found {
+			ownable.setOwner(team)
+		} else {
+			issues = append(issues, *NodeError(fmt.Sprintf("Unknown team '%v'", ownable.getOwnerId()), ownable.getNode()))
+		}
+	}
+	return issues
+}
+
+// OwnerValidator requires an owner for every service that isn't deprecated.
+type OwnerValidator struct {
+}
+
+func (v OwnerValidator) validate(model *ArchitectureModel) []Issue {
+	issues := make([]Issue, 0)
+	for _, service := range model.Services {
+		if service.Owner == nil && service.State != Deprecated {
+			issues = append(issues, *NodeWarning(fmt.Sprintf("Service '%v' has no owner", service.Id), service.node))
+		}
+	}
+	return issues
+}
+
+// ownerOf returns the team that owns an element. A form without an owner of its own is owned by the owner of its
+// service.
+func ownerOf(element interface{}) *Team {
+	switch e := element.(type) {
+	case *ExternalSystem:
+		return e.Owner
+	case *Service:
+		return e.Owner
+	case *Form:
+		if e.Owner == nil {
+			return e.ImplementedBy.Owner
+		}
+		return e.Owner
+	case *Database:
+		return e.Owner
+	case *DataStore:
+		return e.Owner
+	default:
+		return nil
+	}
+}
+
+// PrintOwners prints the teams with the elements they own, followed by the elements without an owner, as Markdown or
+// CSV.
+func PrintOwners(model *ArchitectureModel, format string, printer *Printer) error {
+	owned := make(map[*Team][]interface{})
+	for _, element := range model.Elements() {
+		if _, ok := element.(Ownable); ok {
+			owner := ownerOf(element)
+			owned[owner] = append(owned[owner], element)
+		}
+	}
+	switch format {
+	case "", "markdown":
+		for _, team := range model.Teams {
+			printer.PrintLn("## ", team.Name)
+			printer.NewLine()
+			if team.Description != "" {
+				printer.PrintLn(team.Description)
+				printer.NewLine()
+			}
+			if team.Contact != "" {
+				printer.PrintLn("Contact: ", team.Contact)
+				printer.NewLine()
+			}
+			for _, link := range team.Links {
+				printer.PrintLn("- [", link.Name, "](", link.Url, ")")
+			}
+			if len(team.Links) > 0 {
+				printer.NewLine()
+			}
+			printOwnedElements(owned[team], printer)
+		}
+		if len(owned[nil]) > 0 {
+			printer.PrintLn("## Without owner")
+			printer.NewLine()
+			printOwnedElements(owned[nil], printer)
+		}
+	case "csv":
+		printer.PrintLn("Team,Kind,Id,Name")
+		for _, team := range teamsAndNobody(model) {
+			teamId := ""
+			if team != nil {
+				teamId = team.Id
+			}
+			for _, element := range owned[team] {
+				printer.PrintLn(strings.Join([]string{teamId, kindOf(element), idOf(element), nameOf(element)}, ","))
+			}
+		}
+	default:
+		return fmt.Errorf("unknown format '%v': must be one of 'markdown' or 'csv'", format)
+	}
+	return nil
+}
+
+// teamsAndNobody returns the teams of the model followed by nil, for elements without owner.
+func teamsAndNobody(model *ArchitectureModel) []*Team {
+	result := make([]*Team, 0, len(model.Teams)+1)
+	result = append(result, model.Teams...)
+	return append(result, nil)
+}
+
+func printOwnedElements(elements []interface{}, printer *Printer) {
+	if len(elements) == 0 {
+		printer.PrintLn("Owns nothing")
+		printer.NewLine()
+		return
+	}
+	printer.PrintLn("| Kind | ID | Name |")
+	printer.PrintLn("|---|---|---|")
+	for _, element := range elements {
+		printer.PrintLn("| ", kindOf(element), " | ", idOf(element), " | ", nameOf(element), " |")
+	}
+	printer.NewLine()
+}
@@ -16,18 +16,6 @@ type Implementable interface {
 	setTechnologies([]*Technology)
 }
 
-func PrintTechnologies(technologies []*Technology, printer *Printer) {
-	if len(technologies) > 0 {
-		printer.Print(" [")
-		prefix := ""
-		for _, tech := range technologies {
-			printer.Print(prefix, tech.Name)
-			prefix = ", "
-		}
-		printer.Print("]")
-	}
-}
-
 func setTechnologies(fields map[string]*yaml.Node, implementable Implementable) []Issue {
 	return setTechnologiesFrom(fields, "technologies", implementable)
 }
@@ -88,16 +76,29 @@ type Technology struct {
 	Description string
 	Quadrant    Quadrant
 	Ring        Ring
+	Extensions
 }
 
+var technologyFields = []string{"name", "description", "quadrant", "ring"}
+
 func (t *Technology) read(id string, node *yaml.Node) []Issue {
 	var fields map[string]*yaml.Node
 	fields, issues := namedObject(node, id, t)
+	issues = append(issues, setDescription(fields, t)...)
 	issues = append(issues, setQuadrant(node, fields, t)...)
 	issues = append(issues, setRing(node, fields, t)...)
+	issues = append(issues, checkExtensibleFields(fields, technologyFields)...)
 	return issues
 }
 
+func (t *Technology) getDescription() string {
+	return t.Description
+}
+
+func (t *Technology) setDescription(description string) {
+	t.Description = description
+}
+
 func (t *Technology) setNode(node *yaml.Node) {
 	t.node = node
 }
@@ -110,9 +111,13 @@ func (t *Technology) setName(name string) {
 	t.Name = name
 }
 
-func setQuadrant(owner *yaml.Node, fields map[string]*yaml.Node, technology *Technology) []Issue {
-	var allowedQuadrants = []string{"languagesAndFrameworks", "platforms", "tools", "techniques"}
+var allowedQuadrants = []string{"languagesAndFrameworks", "platforms", "tools", "techniques"}
 
+func (q Quadrant) String() string {
+	return allowedQuadrants[q]
+}
+
+func setQuadrant(owner *yaml.Node, fields map[string]*yaml.Node, technology *Technology) []Issue {
 	value, issue := enumFieldOf(owner, fields, "quadrant", allowedQuadrants, "")
 	if issue != nil {
 		return []Issue{*issue}
@@ -125,10 +130,15 @@ func setQuadrant(owner *yaml.Node, fields map[string]*yaml.Node, technology *Tec
 	return []Issue{}
 }
 
-func setRing(owner *yaml.Node, fields map[string]*yaml.Node, technology *Technology) []Issue {
-	defaultRing := "adopt"
-	var allowedRings = []string{"trial", "assess", defaultRing, "hold"}
+const defaultRing = "adopt"
+
+var allowedRings = []string{"trial", "assess", defaultRing, "hold"}
+
+func (r Ring) String() string {
+	return allowedRings[r]
+}
 
+func setRing(owner *yaml.Node, fields map[string]*yaml.Node, technology *Technology) []Issue {
 	value, issue := enumFieldOf(owner, fields, "ring", allowedRings, defaultRing)
 	if issue != nil {
 		return []Issue{*issue}
@@ -170,14 +180,27 @@ type TechnologyBundle struct {
 	Technologies  []*Technology
 }
 
+var technologyBundleFields = []string{"technologies"}
+
+// read reads a technology bundle, which is either a sequence of technologies or a map with such a sequence in its
+// technologies field.
 func (b *TechnologyBundle) read(id string, node *yaml.Node) []Issue {
 	b.node = node
 	b.Id = id
+	issues := make([]Issue, 0)
+	if node.Kind == yaml.MappingNode {
+		fields, _ := toMap(node)
+		issues = append(issues, checkFields(fields, technologyBundleFields)...)
+		technologiesNode, found := fields["technologies"]
+		if !found {
+			return append(issues, *NodeError("Missing required field technologies", node))
+		}
+		node = technologiesNode
+	}
 	technologyIdNodes, issue := toSequence(node, "technologies")
 	if issue != nil {
-		return []Issue{*issue}
+		return append(issues, *issue)
 	}
-	issues := make([]Issue, 0)
 	technologyIds := make([]string, 0)
 	for _, technologyIdNode := range technologyIdNodes {
 		technologyId, issue := toString(technologyIdNode, "technology")
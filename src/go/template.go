@@ -0,0 +1,111 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var builtInTemplates embed.FS
+
+type templateExporter struct {
+	name string
+	text string
+}
+
+// NewTemplateExporter creates an exporter that runs a text/template over the model. The template is either the name
+// of a built-in template, like dfd or dot, or the name of a template file.
+func NewTemplateExporter(nameOrFileName string) (TextExporter, error) {
+	data, err := builtInTemplates.ReadFile(fmt.Sprintf("templates/%v.tmpl", nameOrFileName))
+	if err != nil {
+		data, err = os.ReadFile(nameOrFileName)
+		if err != nil {
+			return nil, fmt.Errorf("unknown template '%v': %v", nameOrFileName, err)
+		}
+	}
+	return templateExporter{nameOrFileName, string(data)}, nil
+}
+
+func (t templateExporter) export(model ArchitectureModel, printer *Printer) error {
+	tmpl, err := template.New(t.name).Funcs(templateFunctions(&model)).Parse(t.text)
+	if err != nil {
+		return err
+	}
+	var builder strings.Builder
+	err = tmpl.Execute(&builder, model)
+	if err != nil {
+		return err
+	}
+	printer.Print(builder.String())
+	return nil
+}
+
+func templateFunctions(model *ArchitectureModel) template.FuncMap {
+	return template.FuncMap{
+		"technologies": func(technologies []*Technology) string {
+			return joinTechnologies(technologies)
+		},
+		"state": func(state State) string {
+			return state.Id()
+		},
+		"dataFlow": func(dataFlow DataFlow) string {
+			return arrowOf(dataFlow)
+		},
+		"stateColor": func(state State) string {
+			return model.theme().ColorOf(state)
+		},
+		"style": func(element interface{}) Style {
+			return model.theme().StyleOf(element)
+		},
+		"styleFor": func(command string, element interface{}) Style {
+			switch command {
+			case "dfd":
+				return dfdThemeOf(model).StyleOf(element)
+			case "dot":
+				return dotThemeOf(model).StyleOf(element)
+			default:
+				return model.theme().StyleOf(element)
+			}
+		},
+		"penWidth": dotPenWidth,
+		"escape":   escapeQuoted,
+		"nodeId":   nodeIdOf,
+		"lookup": func(kind string, id string) (interface{}, error) {
+			return lookUpElement(model, kind, id)
+		},
+		"relationships": model.Relationships,
+		"relationshipsFrom": func(element interface{}) []*Relationship {
+			return relationshipsFrom(model, element)
+		},
+	}
+}
+
+func joinTechnologies(technologies []*Technology) string {
+	names := make([]string, 0)
+	for _, technology := range technologies {
+		names = append(names, technology.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func arrowOf(dataFlow DataFlow) string {
+	switch dataFlow {
+	case Send:
+		return "->"
+	case Receive:
+		return "<-"
+	default:
+		return "<->"
+	}
+}
+
+func lookUpElement(model *ArchitectureModel, kind string, id string) (interface{}, error) {
+	element, found := model.findElementByRef(kind, id)
+	if !found {
+		return nil, fmt.Errorf("unknown %v '%v'", kind, id)
+	}
+	return element, nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestBuiltInTemplatesMatchExporters(t *testing.T) {
+	definition := `personas:
+  dev:
+    uses:
+      - form: subscriptions
+      - view: requests
+        dataFlow: receive
+
+externalSystems:
+  platform:
+    type: local
+    tags:
+      - onPremise
+      - critical
+    properties:
+      vendor: 'ACME "Cloud" \ EU'
+    calls:
+      - service: api
+        dataFlow: send
+        technologies: http
+        tags:
+          - sync
+        properties:
+          sla: 99.9%
+
+services:
+  api:
+    state: legacy
+    dataStores:
+      - queue: events
+        dataFlow: send
+      - database: subscriptions
+    calls:
+      - externalSystem: platform
+        dataFlow: receive
+  console:
+    forms:
+      - subscriptions
+
+databases:
+  subscriptions:
+    apiTechnologies: sql
+    views:
+      - requests
+
+queues:
+  events:
+    state: emerging
+
+technologies:
+  http:
+    name: HTTP
+    quadrant: techniques
+  sql:
+    name: SQL
+    quadrant: languagesAndFrameworks
+
+styles:
+  elements:
+    service:
+      shape: Hexagon
+      font: mono
+      fontSize: 12
+      strokeWidth: 2
+  types:
+    local:
+      color: "#ffffff"
+`
+	model, issues := LintText(definition)
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+
+	for name, exporter := range map[string]TextExporter{"dfd": NewDfdExporter(), "dot": NewDotExporter()} {
+		expected := NewPrinter()
+		if err := exporter.export(*model, expected); err != nil {
+			t.Fatalf("Failed to export %v: %v", name, err)
+		}
+		templateExporter, err := NewTemplateExporter(name)
+		if err != nil {
+			t.Fatalf("Missing built-in template %v: %v", name, err)
+		}
+		actual := NewPrinter()
+		if err := templateExporter.export(*model, actual); err != nil {
+			t.Fatalf("Failed to run template %v: %v", name, err)
+		}
+
+		if actual.String() != expected.String() {
+			t.Errorf("Template %v differs from exporter\n\nExpected:\n%v\n\nActual:\n%v", name, expected, actual)
+		}
+	}
+}
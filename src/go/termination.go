@@ -62,12 +62,11 @@ type noProgressMade[G Cloner[G]] struct {
 }
 
 func (f *noProgressMade[G]) isMet(population Population[G]) bool {
-	f.fitnesses[f.index] = population.avgFitness()
+	f.fitnesses[f.index%len(f.fitnesses)] = population.avgFitness()
+	f.index++
 	if f.index < len(f.fitnesses) {
-		f.index++
 		return false
 	}
-	f.index = (f.index + 1) % len(f.fitnesses)
 	prev := f.fitnesses[0]
 	const maxFitnessDelta = 1e-6
 	for i := 1; i < len(f.fitnesses); i++ {
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+type terraformState struct {
+	Resources []struct {
+		Mode      string `json:"mode"`
+		Type      string `json:"type"`
+		Name      string `json:"name"`
+		Instances []struct {
+			Attributes map[string]interface{} `json:"attributes"`
+		} `json:"instances"`
+	} `json:"resources"`
+}
+
+// terraformResources are the types of Terraform resources that importers recognize as databases and queues. The ID of
+// a data store is the value of the ID attribute, or else the name of the resource. The engine attribute, if any, holds
+// the name of the database software, like postgres.
+var terraformResources = map[string]struct {
+	kind           string
+	idAttribute    string
+	engine         string
+	technologyId   string
+	technologyName string
+}{
+	"aws_db_instance":              {"database", "identifier", "engine", "amazon-rds", "Amazon RDS"},
+	"aws_rds_cluster":              {"database", "cluster_identifier", "engine", "amazon-rds", "Amazon RDS"},
+	"google_sql_database_instance": {"database", "name", "database_version", "cloud-sql", "Google Cloud SQL"},
+	"aws_sqs_queue":                {"queue", "name", "", "amazon-sqs", "Amazon SQS"},
+	"aws_sns_topic":                {"queue", "name", "", "amazon-sns", "Amazon SNS"},
+	"google_pubsub_topic":          {"queue", "name", "", "pubsub", "Google Pub/Sub"},
+	"aws_msk_cluster":              {"queue", "cluster_name", "", "kafka", "Kafka"},
+	"confluent_kafka_topic":        {"queue", "topic_name", "", "kafka", "Kafka"},
+	"kafka_topic":                  {"queue", "name", "", "kafka", "Kafka"},
+}
+
+// ImportTerraform creates a draft model from a Terraform state file.
+func ImportTerraform(fileName string) (*ImportedModel, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return importTerraform(data)
+}
+
+// importTerraform turns the managed databases, queues, and topics in a Terraform state into databases and queues. They
+// get the technology of the managed service and, for databases, of their engine.
+func importTerraform(data []byte) (*ImportedModel, error) {
+	var state terraformState
+	err := json.Unmarshal(data, &state)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Terraform state: %v", err)
+	}
+	result := NewImportedModel()
+	result.Kinds = []string{"database", "queue"}
+	for _, resource := range state.Resources {
+		definition, found := terraformResources[resource.Type]
+		if resource.Mode != "managed" || !found {
+			continue
+		}
+		for _, instance := range resource.Instances {
+			id, _ := instance.Attributes[definition.idAttribute].(string)
+			if id == "" {
+				id = resource.Name
+			}
+			engine, _ := instance.Attributes[definition.engine].(string)
+			for _, candidate := range infrastructureImages {
+				if engine != "" && candidate.kind == "database" && strings.Contains(strings.ToLower(engine),
+					candidate.keyword) {
+					result.addDataStore(definition.kind, id, candidate.technologyId, candidate.technologyName)
+					break
+				}
+			}
+			result.addDataStore(definition.kind, id, definition.technologyId, definition.technologyName)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Style describes how an exporter draws an element. Empty fields aren't set.
+type Style struct {
+	Background  string `json:"background,omitempty"`
+	Color       string `json:"color,omitempty"`
+	Stroke      string `json:"stroke,omitempty"`
+	StrokeWidth int    `json:"strokeWidth,omitempty"`
+	// Shape is one of allowedShapes. Exporters that use a notation with fixed shapes, like dfd, ignore it.
+	Shape    string `json:"shape,omitempty"`
+	Font     string `json:"font,omitempty"`
+	FontSize int    `json:"fontSize,omitempty"`
+}
+
+var styleFields = []string{"background", "color", "stroke", "strokeWidth", "shape", "font", "fontSize"}
+
+var allowedShapes = []string{"Box", "RoundedBox", "Circle", "Ellipse", "Hexagon", "Cylinder", "Pipe", "Person"}
+
+// IsEmpty returns whether none of the fields of the style are set.
+func (s Style) IsEmpty() bool {
+	return s == Style{}
+}
+
+// merge overrides the fields of the style with the fields that are set in the other style.
+func (s *Style) merge(other *Style) {
+	if other == nil {
+		return
+	}
+	if other.Background != "" {
+		s.Background = other.Background
+	}
+	if other.Color != "" {
+		s.Color = other.Color
+	}
+	if other.Stroke != "" {
+		s.Stroke = other.Stroke
+	}
+	if other.StrokeWidth != 0 {
+		s.StrokeWidth = other.StrokeWidth
+	}
+	if other.Shape != "" {
+		s.Shape = other.Shape
+	}
+	if other.Font != "" {
+		s.Font = other.Font
+	}
+	if other.FontSize != 0 {
+		s.FontSize = other.FontSize
+	}
+}
+
+func (s *Style) read(node *yaml.Node) []Issue {
+	fields, issue := toMap(node)
+	if issue != nil {
+		return []Issue{*issue}
+	}
+	issues := checkFields(fields, styleFields)
+	for field, target := range map[string]*string{"background": &s.Background, "color": &s.Color, "stroke": &s.Stroke,
+		"font": &s.Font} {
+		value, found, issue := stringFieldOf(fields, field)
+		if issue != nil {
+			issues = append(issues, *issue)
+		} else if found {
+			*target = value
+		}
+	}
+	for field, target := range map[string]*int{"strokeWidth": &s.StrokeWidth, "fontSize": &s.FontSize} {
+		valueNode, found := fields[field]
+		if !found {
+			continue
+		}
+		value, issue := toNumber(valueNode, field)
+		if issue != nil {
+			issues = append(issues, *issue)
+		} else if value <= 0 || value != float64(int(value)) {
+			issues = append(issues, *NodeError(fmt.Sprintf("%v must be a positive whole number", field), valueNode))
+		} else {
+			*target = int(value)
+		}
+	}
+	shape, found, issue := stringFieldOf(fields, "shape")
+	if issue != nil {
+		issues = append(issues, *issue)
+	} else if found {
+		if hasDifferentValueThan(shape, allowedShapes) {
+			issues = append(issues, *NodeError(fmt.Sprintf("Invalid shape: must be one of %v", stringsIn(allowedShapes)),
+				fields["shape"]))
+		} else {
+			s.Shape = shape
+		}
+	}
+	return issues
+}
+
+// Theme maps element kinds, external system types, and states to styles. The style of an element combines the style
+// of its kind with the style of its type and then with the style of its state.
+type Theme struct {
+	// Elements maps kinds of elements, as well as the system of interest, to styles.
+	Elements map[string]*Style `json:"elements"`
+	// Types maps external system types to styles.
+	Types map[string]*Style `json:"types"`
+	// States maps states, as used in the model, to styles.
+	States map[string]*Style `json:"states"`
+}
+
+var themeFields = []string{"elements", "types", "states"}
+
+var styledElements = []string{"persona", "system", "externalSystem", "service", "database", "queue"}
+
+func defaultTheme() *Theme {
+	return &Theme{
+		Elements: map[string]*Style{
+			"persona":        {Shape: "Person", Stroke: "#3966a0", StrokeWidth: 10, Background: "GhostWhite"},
+			"system":         {Background: "#b6d7a8", FontSize: 36, Shape: "RoundedBox", Stroke: "black"},
+			"externalSystem": {Background: "#e2e2e2", Shape: "RoundedBox", Stroke: "black"},
+			"service":        {Stroke: "black"},
+			"database":       {Shape: "Cylinder", Stroke: "black"},
+			"queue":          {Shape: "Pipe", Stroke: "black"},
+		},
+		Types: map[string]*Style{
+			"central": {Background: "#a2c4c9"},
+			"local":   {Background: "#38761d", Color: "white"},
+		},
+		States: map[string]*Style{
+			"ok":         {Background: "#b6d7a8"},
+			"emerging":   {Background: "#a4c1f4"},
+			"review":     {Background: "#fff2cc"},
+			"revision":   {Background: "#ffd966"},
+			"legacy":     {Background: "#e69238"},
+			"deprecated": {Background: "#cc0000"},
+		},
+	}
+}
+
+// StyleOf returns the style of an element.
+func (t *Theme) StyleOf(element interface{}) Style {
+	result := Style{}
+	result.merge(t.Elements[kindOf(element)])
+	if externalSystem, ok := element.(*ExternalSystem); ok {
+		result.merge(t.Types[externalSystem.Type])
+	}
+	if state, found := stateOf(element); found {
+		result.merge(t.States[state.Id()])
+	}
+	return result
+}
+
+// ColorOf returns the background color of a state.
+func (t *Theme) ColorOf(state State) string {
+	style, found := t.States[state.Id()]
+	if !found {
+		return ""
+	}
+	return style.Background
+}
+
+// TypeNames returns the external system types that have a style, in alphabetical order.
+func (t *Theme) TypeNames() []string {
+	result := make([]string, 0)
+	for name := range t.Types {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// read overrides the styles of the theme with the ones defined in the node.
+func (t *Theme) read(node *yaml.Node) []Issue {
+	parts, issue := toMap(node)
+	if issue != nil {
+		return []Issue{*issue}
+	}
+	issues := checkFields(parts, themeFields)
+	issues = append(issues, readStyles(parts, "elements", styledElements, t.Elements)...)
+	issues = append(issues, readStyles(parts, "types", nil, t.Types)...)
+	issues = append(issues, readStyles(parts, "states", allowedStates, t.States)...)
+	return issues
+}
+
+func readStyles(parts map[string]*yaml.Node, part string, allowed []string, styles map[string]*Style) []Issue {
+	stylesByName, _, issue := mapFieldOf(parts, part)
+	if issue != nil {
+		return []Issue{*issue}
+	}
+	issues := make([]Issue, 0)
+	for name, styleNode := range stylesByName {
+		if allowed != nil && hasDifferentValueThan(name, allowed) {
+			issues = append(issues, *NodeError(fmt.Sprintf("Invalid %v: must be one of %v", part, stringsIn(allowed)),
+				styleNode))
+			continue
+		}
+		style, found := styles[name]
+		if !found {
+			style = &Style{}
+			styles[name] = style
+		}
+		issues = append(issues, style.read(styleNode)...)
+	}
+	return issues
+}
+
+// ReadFile overrides the styles of the theme with the ones in a theme file, which has the same structure as the
+// styles element of a model.
+func (t *Theme) ReadFile(fileName string) []Issue {
+	bytes, err := os.ReadFile(fileName)
+	if err != nil {
+		return []Issue{*FileError(fmt.Sprintf("Couldn't read file %s: %v", fileName, err))}
+	}
+	var node yaml.Node
+	err = yaml.Unmarshal(bytes, &node)
+	if err != nil {
+		return invalidYaml(err.Error())
+	}
+	if node.IsZero() {
+		return []Issue{}
+	}
+	return t.read(node.Content[0])
+}
+
+func (model ArchitectureModel) theme() *Theme {
+	if model.Theme == nil {
+		return defaultTheme()
+	}
+	return model.Theme
+}
+
+type StylesReader struct {
+}
+
+// read leaves the theme of a model without styles empty, so that exporters can fall back to their own defaults.
+func (s StylesReader) read(node *yaml.Node, _ string, model *ArchitectureModel) []Issue {
+	if node == nil {
+		return []Issue{}
+	}
+	model.Theme = defaultTheme()
+	return model.Theme.read(node)
+}
+
+// ReadThemeFile overrides the styles of the model with the ones in a theme file.
+func (model *ArchitectureModel) ReadThemeFile(fileName string) []Issue {
+	model.Theme = model.theme()
+	return model.Theme.ReadFile(fileName)
+}
+
+// dotAttributesOf returns the Graphviz node attributes for a style. The shape defaults to a box.
+func dotAttributesOf(style Style) string {
+	result := ""
+	var styles []string
+	switch style.Shape {
+	case "Person":
+		result += "shape=polygon,sides=5"
+	case "RoundedBox":
+		result += "shape=rectangle"
+		styles = append(styles, "rounded")
+	case "Pipe":
+		result += "shape=parallelogram"
+	case "Circle", "Ellipse", "Hexagon", "Cylinder":
+		result += "shape=" + strings.ToLower(style.Shape)
+	default:
+		result += "shape=box"
+	}
+	if style.Background != "" {
+		styles = append(styles, "filled")
+	}
+	if len(styles) == 1 {
+		result += ",style=" + styles[0]
+	} else if len(styles) > 1 {
+		result += ",style=\"" + strings.Join(styles, ",") + "\""
+	}
+	if style.Background != "" {
+		result += ",fillcolor=\"" + style.Background + "\""
+	}
+	if style.Stroke != "" {
+		result += ",color=\"" + style.Stroke + "\""
+	}
+	if style.StrokeWidth != 0 {
+		result += ",penwidth=" + dotPenWidth(style.StrokeWidth)
+	}
+	if style.Color != "" {
+		result += ",fontcolor=\"" + style.Color + "\""
+	}
+	if style.Font != "" {
+		result += ",fontname=\"" + style.Font + "\""
+	}
+	if style.FontSize != 0 {
+		result += ",fontsize=" + strconv.Itoa(style.FontSize)
+	}
+	return result
+}
+
+// dotPenWidth returns the Graphviz pen width for a stroke width. Structurizr draws borders 2 wide by default, where
+// Graphviz uses a pen width of 1.
+func dotPenWidth(strokeWidth int) string {
+	return strconv.FormatFloat(float64(strokeWidth)/2, 'f', -1, 64)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"testing"
+)
+
+const unstyledDefinition = `personas:
+  dev:
+    name: Developer
+    uses:
+      - form: register
+      - view: accounts
+        dataFlow: receive
+      - externalSystem: idp
+externalSystems:
+  idp:
+    name: Identity provider
+    type: central
+    calls:
+      - service: api
+  crm:
+    type: local
+services:
+  api:
+    state: emerging
+    forms:
+      - register
+    calls:
+      - service: worker
+      - externalSystem: crm
+        dataFlow: send
+    dataStores:
+      - database: main
+        dataFlow: bidirectional
+      - queue: jobs
+        dataFlow: send
+  worker:
+    state: legacy
+    technologies:
+      - java
+    dataStores:
+      - queue: jobs
+        dataFlow: receive
+databases:
+  main:
+    state: review
+    views:
+      - accounts
+queues:
+  jobs:
+    state: deprecated
+technologies:
+  java:
+    quadrant: languagesAndFrameworks
+`
+
+// TestExportsOfModelWithoutStyles checks that models without styles export like they did before themes existed.
+func TestExportsOfModelWithoutStyles(t *testing.T) {
+	model, issues := LintText(unstyledDefinition)
+	if hasIssue(issues, hasError("")) {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+
+	for name, expected := range map[string]string{
+		"dot": `digraph {
+    splines=ortho
+    
+    dev[shape=polygon,sides=5,color="#3966a0",label="Developer"]
+    dev -> api [dir=both]
+    dev -> main_db [dir=back]
+    dev -> idp [dir=both]
+    
+    crm[shape=rectangle,style="rounded,filled",fillcolor="#e2e2e2",label="Crm"]
+    idp[shape=rectangle,style="rounded,filled",fillcolor="#e2e2e2",label="Identity provider"]
+    idp -> api [dir=both]
+    
+    api[shape=box,style=filled,fillcolor="#a4c1f4",label="Api"]
+    api -> worker [dir=both]
+    api -> crm [dir=forward]
+    api -> main_db [dir=both]
+    api -> jobs_q [dir=forward]
+    worker[shape=box,style=filled,fillcolor="#e69238",label="Worker"]
+    worker -> jobs_q [dir=back]
+    
+    main_db [shape=cylinder,style=filled,fillcolor="#fff2cc",label="Main"]
+    jobs_q [shape=parallelogram,style=filled,fillcolor="#cc0000",label="Jobs"]
+}
+`,
+		"dfd": `dev: Developer
+dev <-> api
+dev <- main_db
+dev <-> idp
+crm: Crm
+idp: Identity provider
+idp <-> api
+api: Api { shape: circle }
+api <-> worker
+api -> crm
+api <-> main_db
+api -> jobs_q
+worker: Worker { shape: circle }
+worker <- jobs_q
+main_db: Main {
+    shape: image
+    icon: https://github.com/RemonSinnema/architecture-diagrams/raw/main/static/data-store.png
+}
+jobs_q: Jobs {
+    shape: image
+    icon: https://github.com/RemonSinnema/architecture-diagrams/raw/main/static/data-store.png
+}
+`,
+	} {
+		for _, template := range []bool{false, true} {
+			var exporter TextExporter
+			var err error
+			if template {
+				exporter, err = NewTemplateExporter(name)
+				if err != nil {
+					t.Fatal(err)
+				}
+			} else if name == "dot" {
+				exporter = NewDotExporter()
+			} else {
+				exporter = NewDfdExporter()
+			}
+			printer := NewPrinter()
+
+			err = exporter.export(*model, printer)
+
+			if err != nil {
+				t.Fatal(err)
+			} else if printer.String() != expected {
+				t.Errorf("Export %v (template: %v) changed\n\nExpected:\n%v\n\nActual:\n%v", name, template, expected,
+					printer.String())
+			}
+		}
+	}
+}
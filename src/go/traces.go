@@ -0,0 +1,417 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// span is a span of a trace, in a form that is independent of the format of the file it was read from.
+type span struct {
+	traceId    string
+	id         string
+	parentId   string
+	service    string
+	kind       string
+	attributes map[string]string
+}
+
+type otlpAttribute struct {
+	Key   string `json:"key"`
+	Value struct {
+		StringValue *string     `json:"stringValue"`
+		IntValue    interface{} `json:"intValue"`
+		BoolValue   *bool       `json:"boolValue"`
+	} `json:"value"`
+}
+
+type otlpScopeSpans struct {
+	Spans []struct {
+		TraceId      string          `json:"traceId"`
+		SpanId       string          `json:"spanId"`
+		ParentSpanId string          `json:"parentSpanId"`
+		Kind         interface{}     `json:"kind"`
+		Attributes   []otlpAttribute `json:"attributes"`
+	} `json:"spans"`
+}
+
+// otlpExport is an export of spans in the JSON encoding of the OpenTelemetry protocol.
+type otlpExport struct {
+	ResourceSpans []struct {
+		Resource struct {
+			Attributes []otlpAttribute `json:"attributes"`
+		} `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+		// InstrumentationLibrarySpans is what older versions of the protocol called ScopeSpans.
+		InstrumentationLibrarySpans []otlpScopeSpans `json:"instrumentationLibrarySpans"`
+	} `json:"resourceSpans"`
+}
+
+type zipkinEndpoint struct {
+	ServiceName string `json:"serviceName"`
+}
+
+type zipkinSpan struct {
+	TraceId        string            `json:"traceId"`
+	Id             string            `json:"id"`
+	ParentId       string            `json:"parentId"`
+	Kind           string            `json:"kind"`
+	LocalEndpoint  zipkinEndpoint    `json:"localEndpoint"`
+	RemoteEndpoint zipkinEndpoint    `json:"remoteEndpoint"`
+	Tags           map[string]string `json:"tags"`
+}
+
+var otlpSpanKinds = []string{"", "INTERNAL", "SERVER", "CLIENT", "PRODUCER", "CONSUMER"}
+
+// ReadSpans reads spans from a file with OpenTelemetry or Zipkin JSON. An OpenTelemetry file has one or more exports,
+// and a Zipkin file has a list of spans or a list of traces, which are lists of spans.
+func ReadSpans(fileName string) ([]span, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return readSpans(data)
+}
+
+func readSpans(data []byte) ([]span, error) {
+	result := make([]span, 0)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var value json.RawMessage
+		err := decoder.Decode(&value)
+		if errors.Is(err, io.EOF) {
+			return result, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid spans: %v", err)
+		}
+		var spans []span
+		if bytes.HasPrefix(bytes.TrimSpace(value), []byte("[")) {
+			spans, err = readZipkinSpans(value)
+		} else {
+			spans, err = readOtlpSpans(value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid spans: %v", err)
+		}
+		result = append(result, spans...)
+	}
+}
+
+func readOtlpSpans(data []byte) ([]span, error) {
+	var export otlpExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, err
+	}
+	result := make([]span, 0)
+	for _, resourceSpans := range export.ResourceSpans {
+		service := otlpAttributesOf(resourceSpans.Resource.Attributes)["service.name"]
+		for _, scopeSpans := range append(resourceSpans.ScopeSpans, resourceSpans.InstrumentationLibrarySpans...) {
+			for _, otlpSpan := range scopeSpans.Spans {
+				kind := fmt.Sprint(otlpSpan.Kind)
+				if number, ok := otlpSpan.Kind.(float64); ok && int(number) < len(otlpSpanKinds) {
+					kind = otlpSpanKinds[int(number)]
+				}
+				result = append(result, span{otlpSpan.TraceId, otlpSpan.SpanId, otlpSpan.ParentSpanId, service,
+					strings.TrimPrefix(kind, "SPAN_KIND_"), otlpAttributesOf(otlpSpan.Attributes)})
+			}
+		}
+	}
+	return result, nil
+}
+
+func otlpAttributesOf(attributes []otlpAttribute) map[string]string {
+	result := make(map[string]string)
+	for _, attribute := range attributes {
+		switch {
+		case attribute.Value.StringValue != nil:
+			result[attribute.Key] = *attribute.Value.StringValue
+		case attribute.Value.IntValue != nil:
+			result[attribute.Key] = fmt.Sprint(attribute.Value.IntValue)
+		case attribute.Value.BoolValue != nil:
+			result[attribute.Key] = fmt.Sprint(*attribute.Value.BoolValue)
+		}
+	}
+	return result
+}
+
+func readZipkinSpans(data []byte) ([]span, error) {
+	var values []json.RawMessage
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	result := make([]span, 0)
+	for _, value := range values {
+		if bytes.HasPrefix(bytes.TrimSpace(value), []byte("[")) {
+			spans, err := readZipkinSpans(value)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, spans...)
+			continue
+		}
+		var zipkin zipkinSpan
+		if err := json.Unmarshal(value, &zipkin); err != nil {
+			return nil, err
+		}
+		attributes := zipkin.Tags
+		if attributes == nil {
+			attributes = make(map[string]string)
+		}
+		if _, found := attributes["peer.service"]; !found && zipkin.RemoteEndpoint.ServiceName != "" {
+			attributes["peer.service"] = zipkin.RemoteEndpoint.ServiceName
+		}
+		result = append(result, span{zipkin.TraceId, zipkin.Id, zipkin.ParentId, zipkin.LocalEndpoint.ServiceName,
+			zipkin.Kind, attributes})
+	}
+	return result, nil
+}
+
+// ReadMapping reads a YAML map of strings, like one from the names in traces to references to elements of the model,
+// e.g. orders-db: database:orders. Without a file name, the map is empty.
+func ReadMapping(fileName string) (map[string]string, error) {
+	result := make(map[string]string)
+	if fileName == "" {
+		return result, nil
+	}
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	if err = yaml.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("invalid mapping: %v", err)
+	}
+	return result, nil
+}
+
+// observedUse is a call or data store use that was observed in traces, with the directions in which data was seen to
+// flow.
+type observedUse struct {
+	from     interface{}
+	to       interface{}
+	sends    bool
+	receives bool
+}
+
+// TraceReport compares the calls and data store uses that are declared in a model with those that traces show.
+// Relationships are written like service:api -> database:orders.
+type TraceReport struct {
+	Undeclared []string
+	Unobserved []string
+	// WrongDataFlows are the data store uses whose declared data flow doesn't allow the observed directions.
+	WrongDataFlows []string
+	// UnknownNames are the names in the traces that aren't in the mapping and don't match any element.
+	UnknownNames []string
+}
+
+type traceVerifier struct {
+	model        *ArchitectureModel
+	mapping      map[string]string
+	observed     map[string]*observedUse
+	unknownNames map[string]bool
+}
+
+// VerifyTraces compares a model with the spans of traces. Spans are mapped to services by the service.name of their
+// resource, and their db.system and messaging.system attributes, with the names of the database and destination, are
+// mapped to databases and queues. A span calls the service of its parent span, or the service given by its
+// peer.service attribute. A name maps to the element that the mapping gives, or else to the element whose ID or name
+// matches, ignoring case, dashes, underscores, and dots.
+func VerifyTraces(model *ArchitectureModel, spans []span, mapping map[string]string) (*TraceReport, error) {
+	for _, name := range sortedKeys(mapping) {
+		if _, err := model.findElement(mapping[name]); err != nil {
+			return nil, fmt.Errorf("invalid mapping for '%v': %v", name, err)
+		}
+	}
+	v := traceVerifier{model, mapping, make(map[string]*observedUse), make(map[string]bool)}
+	spansById := make(map[string]span)
+	for _, s := range spans {
+		spansById[s.traceId+"/"+s.id] = s
+	}
+	for _, s := range spans {
+		v.observeSpan(s, spansById)
+	}
+	return v.report(), nil
+}
+
+func (v *traceVerifier) observeSpan(s span, spansById map[string]span) {
+	from := v.find(s.service, "service")
+	if from == nil {
+		return
+	}
+	if system, found := s.attributes["db.system"]; found {
+		database := v.find(firstAttribute(s.attributes, system, "db.name", "db.namespace"), "database")
+		operation := strings.ToLower(firstAttribute(s.attributes, "", "db.operation", "db.operation.name",
+			"db.statement", "db.query.text"))
+		operation, _, _ = strings.Cut(strings.TrimSpace(operation), " ")
+		v.observe(from, database, !hasDifferentValueThan(operation, databaseWrites),
+			!hasDifferentValueThan(operation, databaseReads))
+		return
+	}
+	if system, found := s.attributes["messaging.system"]; found {
+		queue := v.find(firstAttribute(s.attributes, system, "messaging.destination.name", "messaging.destination"),
+			"queue")
+		operation := firstAttribute(s.attributes, "", "messaging.operation", "messaging.operation.type")
+		v.observe(from, queue, s.kind == "PRODUCER" || operation == "publish" || operation == "send",
+			s.kind == "CONSUMER" || operation == "receive" || operation == "process")
+		return
+	}
+	if parent, found := spansById[s.traceId+"/"+s.parentId]; found && parent.service != s.service {
+		if _, messaging := parent.attributes["messaging.system"]; !messaging {
+			v.observe(v.find(parent.service, "service"), from, false, false)
+		}
+	}
+	if peer, found := s.attributes["peer.service"]; found && peer != s.service && s.kind != "SERVER" {
+		v.observe(from, v.find(peer, "service", "externalSystem"), false, false)
+	}
+}
+
+// databaseWrites and databaseReads are the first words of database operations and statements that show in which
+// direction data flows.
+var (
+	databaseWrites = []string{"insert", "update", "delete", "upsert", "merge", "replace", "set"}
+	databaseReads  = []string{"select", "get", "find"}
+)
+
+// firstAttribute returns the value of the first of the given attributes that has a value, or else the default.
+func firstAttribute(attributes map[string]string, defaultValue string, names ...string) string {
+	for _, name := range names {
+		if value := attributes[name]; value != "" {
+			return value
+		}
+	}
+	return defaultValue
+}
+
+// find returns the element of one of the given kinds that a name in the traces maps to, or nil for an unknown name.
+func (v *traceVerifier) find(name string, kinds ...string) interface{} {
+	if ref, found := v.mapping[name]; found {
+		element, _ := v.model.findElement(ref)
+		return element
+	}
+	for _, element := range v.model.Elements() {
+		if hasDifferentValueThan(kindOf(element), kinds) {
+			continue
+		}
+		if normalizedId(idOf(element)) == normalizedId(name) || normalizedId(nameOf(element)) == normalizedId(name) {
+			return element
+		}
+	}
+	if name != "" {
+		v.unknownNames[name] = true
+	}
+	return nil
+}
+
+func (v *traceVerifier) observe(from interface{}, to interface{}, sends bool, receives bool) {
+	if from == nil || to == nil || from == to {
+		return
+	}
+	key := edgeOf(from, to)
+	use, found := v.observed[key]
+	if !found {
+		use = &observedUse{from: from, to: to}
+		v.observed[key] = use
+	}
+	use.sends = use.sends || sends
+	use.receives = use.receives || receives
+}
+
+func edgeOf(from interface{}, to interface{}) string {
+	return refOf(from) + " -> " + refOf(to)
+}
+
+func (v *traceVerifier) report() *TraceReport {
+	result := &TraceReport{make([]string, 0), make([]string, 0), make([]string, 0), sortedKeys(v.unknownNames)}
+	declared := make(map[string]bool)
+	for _, service := range v.model.Services {
+		for _, call := range service.Calls {
+			var to interface{} = call.Service
+			if call.ExternalSystem != nil {
+				to = call.ExternalSystem
+			}
+			key := edgeOf(service, to)
+			declared[key] = true
+			if _, found := v.observed[key]; !found {
+				result.Unobserved = append(result.Unobserved, key)
+			}
+		}
+		for _, use := range service.DataStores {
+			var to interface{} = use.Database
+			if use.Queue != nil {
+				to = use.Queue
+			}
+			key := edgeOf(service, to)
+			declared[key] = true
+			observed, found := v.observed[key]
+			if !found {
+				result.Unobserved = append(result.Unobserved, key)
+			} else if use.DataFlow == Send && observed.receives || use.DataFlow == Receive && observed.sends {
+				result.WrongDataFlows = append(result.WrongDataFlows, fmt.Sprintf("%v: declared %v, observed %v", key,
+					use.DataFlow, observed.dataFlow()))
+			}
+		}
+	}
+	for key := range v.observed {
+		if !declared[key] {
+			result.Undeclared = append(result.Undeclared, key)
+		}
+	}
+	sort.Strings(result.Undeclared)
+	sort.Strings(result.Unobserved)
+	sort.Strings(result.WrongDataFlows)
+	return result
+}
+
+func (o *observedUse) dataFlow() DataFlow {
+	switch {
+	case o.sends && o.receives:
+		return Bidirectional
+	case o.sends:
+		return Send
+	default:
+		return Receive
+	}
+}
+
+// Print prints the differences between the model and the traces.
+func (r *TraceReport) Print(printer *Printer) {
+	printFindings(printer, "The traces match the model", []findings{
+		{"Observed, but not declared:", r.Undeclared},
+		{"Declared, but never observed:", r.Unobserved},
+		{"Wrong data flow:", r.WrongDataFlows},
+		{"Unknown names, add them to the mapping:", r.UnknownNames},
+	})
+}
+
+// findings are the lines of a section of a report, under a title.
+type findings struct {
+	title string
+	lines []string
+}
+
+// printFindings prints the sections of a report that have lines, or the given message when none of them have.
+func printFindings(printer *Printer, none string, sections []findings) {
+	printed := false
+	for _, section := range sections {
+		if len(section.lines) == 0 {
+			continue
+		}
+		if printed {
+			printer.NewLine()
+		}
+		printed = true
+		printer.PrintLn(section.title)
+		for _, line := range section.lines {
+			printer.PrintLn("- ", line)
+		}
+	}
+	if !printed {
+		printer.PrintLn(none)
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+const tracesDefinition = `services:
+  web:
+    calls:
+      - service: orders
+  orders:
+    calls:
+      - service: billing
+    dataStores:
+      - database: orders
+        dataFlow: receive
+      - queue: events
+        dataFlow: send
+  billing: {}
+
+databases:
+  orders: {}
+
+queues:
+  events: {}
+`
+
+const otlpSpans = `{"resourceSpans": [
+  {
+    "resource": {"attributes": [{"key": "service.name", "value": {"stringValue": "web-frontend"}}]},
+    "scopeSpans": [{"spans": [
+      {"traceId": "t1", "spanId": "a", "kind": 3, "attributes": []}
+    ]}]
+  },
+  {
+    "resource": {"attributes": [{"key": "service.name", "value": {"stringValue": "orders"}}]},
+    "scopeSpans": [{"spans": [
+      {"traceId": "t1", "spanId": "b", "parentSpanId": "a", "kind": "SPAN_KIND_SERVER"},
+      {"traceId": "t1", "spanId": "c", "parentSpanId": "b", "kind": 3, "attributes": [
+        {"key": "db.system", "value": {"stringValue": "postgresql"}},
+        {"key": "db.name", "value": {"stringValue": "orders"}},
+        {"key": "db.statement", "value": {"stringValue": "UPDATE orders SET paid = true"}}
+      ]},
+      {"traceId": "t1", "spanId": "d", "parentSpanId": "b", "kind": 4, "attributes": [
+        {"key": "messaging.system", "value": {"stringValue": "kafka"}},
+        {"key": "messaging.destination.name", "value": {"stringValue": "order-events"}}
+      ]},
+      {"traceId": "t1", "spanId": "e", "parentSpanId": "b", "kind": 3, "attributes": [
+        {"key": "peer.service", "value": {"stringValue": "stock"}}
+      ]}
+    ]}]
+  }
+]}
+`
+
+const zipkinSpans = `[[
+  {"traceId": "t2", "id": "a", "kind": "CLIENT", "localEndpoint": {"serviceName": "web-frontend"},
+    "remoteEndpoint": {"serviceName": "orders"}},
+  {"traceId": "t2", "id": "b", "parentId": "a", "kind": "SERVER", "localEndpoint": {"serviceName": "orders"},
+    "remoteEndpoint": {"serviceName": "web-frontend"}}
+]]
+`
+
+func TestReadSpans(t *testing.T) {
+	spans, err := readSpans([]byte(otlpSpans + zipkinSpans))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(spans) != 7 {
+		t.Fatalf("Invalid spans: %+v", spans)
+	}
+	if spans[0].service != "web-frontend" || spans[0].kind != "CLIENT" || spans[1].kind != "SERVER" ||
+		spans[2].attributes["db.name"] != "orders" {
+		t.Errorf("Invalid OpenTelemetry spans: %+v", spans)
+	}
+	if spans[5].service != "web-frontend" || spans[5].attributes["peer.service"] != "orders" ||
+		spans[6].parentId != "a" {
+		t.Errorf("Invalid Zipkin spans: %+v", spans)
+	}
+}
+
+func TestVerifyTraces(t *testing.T) {
+	model, issues := LintText(tracesDefinition)
+	if model == nil {
+		t.Fatalf("Invalid model: %+v", issues)
+	}
+	spans, err := readSpans([]byte(otlpSpans + zipkinSpans))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := VerifyTraces(model, spans, map[string]string{"web-frontend": "service:web",
+		"order-events": "queue:events"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &TraceReport{
+		Undeclared:     []string{},
+		Unobserved:     []string{"service:orders -> service:billing"},
+		WrongDataFlows: []string{"service:orders -> database:orders: declared receive, observed send"},
+		UnknownNames:   []string{"stock"},
+	}
+	if !reflect.DeepEqual(report, expected) {
+		t.Errorf("Expected %+v, but got %+v", expected, report)
+	}
+}
+
+func TestVerifyTracesWithInvalidMapping(t *testing.T) {
+	model, _ := LintText(tracesDefinition)
+
+	_, err := VerifyTraces(model, []span{}, map[string]string{"payments": "service:payments"})
+
+	if err == nil || err.Error() != "invalid mapping for 'payments': unknown service 'payments'" {
+		t.Errorf("Expected error for invalid mapping, got %v", err)
+	}
+}
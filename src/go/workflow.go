@@ -14,8 +14,10 @@ type Step struct {
 	Performer        interface{}
 	FormId           string
 	Form             *Form
-	Command          string
-	Event            string
+	CommandId        string
+	Command          *Command
+	EventId          string
+	Event            *Event
 	ExternalSystemId string
 	ExternalSystem   *ExternalSystem
 	ServiceId        string
@@ -31,6 +33,9 @@ func (s *Step) setDescription(description string) {
 	s.Description = description
 }
 
+var stepFields = []string{"description", "workflow", "performer", "command", "event", "externalSystem", "form",
+	"service", "view"}
+
 func (s *Step) read(node *yaml.Node) []Issue {
 	s.node = node
 	fields, issue := toMap(node)
@@ -38,6 +43,7 @@ func (s *Step) read(node *yaml.Node) []Issue {
 		return []Issue{*issue}
 	}
 	issues := make([]Issue, 0)
+	issues = append(issues, checkFields(fields, stepFields)...)
 	issues = append(issues, setDescription(fields, s)...)
 	subWorkflowId, found, issue := stringFieldOf(fields, "workflow")
 	if issue != nil {
@@ -94,8 +100,8 @@ func (s *Step) readPerformance(node *yaml.Node, fields map[string]*yaml.Node) []
 	if issue != nil {
 		return append(issues, *issue)
 	}
-	s.Command = command
-	s.Event = event
+	s.CommandId = command
+	s.EventId = event
 	s.ExternalSystemId = externalSystemId
 	s.FormId = formId
 	s.ServiceId = serviceId
@@ -127,19 +133,7 @@ type Workflow struct {
 	Description string
 	Steps       []*Step
 	TopLevel    bool
-}
-
-func (w *Workflow) Print(printer *Printer) {
-	printer.Print(w.Name, " (")
-	switch len(w.Steps) {
-	case 0:
-		printer.Print("no steps")
-	case 1:
-		printer.Print(" 1 step")
-	default:
-		printer.Print(len(w.Steps), " steps")
-	}
-	printer.PrintLn(")")
+	Extensions
 }
 
 func (w *Workflow) setNode(node *yaml.Node) {
@@ -162,11 +156,14 @@ func (w *Workflow) setDescription(description string) {
 	w.Description = description
 }
 
+var workflowFields = []string{"name", "description", "steps"}
+
 func (w *Workflow) read(id string, node *yaml.Node) []Issue {
 	var fields map[string]*yaml.Node
 	fields, issues := namedObject(node, id, w)
 	issues = append(issues, setDescription(fields, w)...)
 	issues = append(issues, w.readSteps(fields)...)
+	issues = append(issues, checkExtensibleFields(fields, workflowFields)...)
 	return issues
 }
 
@@ -261,6 +258,23 @@ func (w WorkflowCollector) connectTarget(step *Step, model *ArchitectureModel) [
 		step.ExternalSystem = externalSystem
 		return []Issue{}
 	}
+	if step.CommandId != "" && model.hasCommands() {
+		// Models without commands use free-form command names
+		command, found := model.findCommandById(step.CommandId)
+		if !found {
+			return []Issue{*NodeError(fmt.Sprintf("Unknown command '%v'", step.CommandId), step.node)}
+		}
+		step.Command = command
+		return []Issue{}
+	}
+	if step.EventId != "" && len(model.Events) > 0 {
+		// Models without events use free-form event names
+		event, found := model.findEventById(step.EventId)
+		if !found {
+			return []Issue{*NodeError(fmt.Sprintf("Unknown event '%v'", step.EventId), step.node)}
+		}
+		step.Event = event
+	}
 	return []Issue{}
 }
 
@@ -281,11 +295,9 @@ func findService(node *yaml.Node, id string, model *ArchitectureModel) (*Service
 }
 
 func findForm(node *yaml.Node, id string, model *ArchitectureModel) (*Form, *Issue) {
-	for _, service := range model.Services {
-		form, found := service.findFormById(id)
-		if found {
-			return form, nil
-		}
+	form, found := model.findFormById(id)
+	if found {
+		return form, nil
 	}
 	return nil, NodeError(fmt.Sprintf("Unknown form '%v'", id), node)
 }
@@ -305,7 +317,7 @@ func (w WorkflowCollector) connectPerformer(step *Step, model *ArchitectureModel
 	if step.View != "" {
 		return w.connectViewPerformer(step, model)
 	}
-	if step.Command != "" {
+	if step.CommandId != "" {
 		return w.connectCommandPerformer(step, model)
 	}
 	if step.ServiceId != "" {
@@ -314,7 +326,7 @@ func (w WorkflowCollector) connectPerformer(step *Step, model *ArchitectureModel
 	if step.ExternalSystemId != "" {
 		return w.connectExternalSystemPerformer(step, model)
 	}
-	if step.Event != "" {
+	if step.EventId != "" {
 		return w.connectEventPerformer(step, model)
 	}
 	return []Issue{}
@@ -350,23 +362,21 @@ func (w WorkflowCollector) connectViewPerformer(step *Step, model *ArchitectureM
 }
 
 func (w WorkflowCollector) connectCommandPerformer(step *Step, model *ArchitectureModel) []Issue {
-	form, issue := findForm(step.node, step.PerformerId, model)
-	if issue == nil {
+	if form, found := model.findFormById(step.PerformerId); found {
 		step.Performer = form
-		return []Issue{}
-	}
-	service, issue := findService(step.node, step.PerformerId, model)
-	if issue == nil {
+	} else if service, found := model.findServiceById(step.PerformerId); found {
 		step.Performer = service
-		return []Issue{}
-	}
-	externalSystem, issue := findExternalSystem(step.node, step.PerformerId, model)
-	if issue == nil {
+	} else if externalSystem, found := model.findExternalSystemById(step.PerformerId); found {
 		step.Performer = externalSystem
-		return []Issue{}
+	} else {
+		return []Issue{*NodeError(fmt.Sprintf("Unknown form, service, or external system '%v'",
+			step.PerformerId), step.node)}
 	}
-	return []Issue{*NodeError(fmt.Sprintf("Unknown form, service, or external system '%v'",
-		step.PerformerId), step.node)}
+	if step.Command != nil && !canIssueCommandTo(step.Performer, step.Command.HandledBy) {
+		return []Issue{*NodeError(fmt.Sprintf("Command '%v' is handled by service '%v', which '%v' doesn't call",
+			step.Command.Id, step.Command.HandledBy.Id, step.PerformerId), step.node)}
+	}
+	return []Issue{}
 }
 
 func (w WorkflowCollector) connectServicePerformer(step *Step, model *ArchitectureModel) []Issue {
@@ -403,6 +413,10 @@ func (w WorkflowCollector) connectEventPerformer(step *Step, model *Architecture
 	service, issue := findService(step.node, step.PerformerId, model)
 	if issue == nil {
 		step.Performer = service
+		if step.Event != nil && !service.publishes(step.Event) {
+			return []Issue{*NodeError(fmt.Sprintf("Service '%v' doesn't publish event '%v'", service.Id, step.Event.Id),
+				step.node)}
+		}
 		return []Issue{}
 	}
 	return []Issue{*NodeError(fmt.Sprintf("Unknown service '%v'", step.PerformerId), step.node)}